@@ -3,43 +3,119 @@ package main
 import (
 	"context"
 	"devmetrics/internal/api/rest/server"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"devmetrics/internal/adapters/oidc"
+	"devmetrics/internal/adapters/storage/memory"
 	adapter "devmetrics/internal/adapters/vcs"
+	aggregatehandler "devmetrics/internal/api/rest/handlers/aggregate"
+	authhandler "devmetrics/internal/api/rest/handlers/auth"
+	healthhandler "devmetrics/internal/api/rest/handlers/health"
+	identityhandler "devmetrics/internal/api/rest/handlers/identity"
+	reloadhandler "devmetrics/internal/api/rest/handlers/reload"
+	reposhandler "devmetrics/internal/api/rest/handlers/repos"
+	secrethandler "devmetrics/internal/api/rest/handlers/secrets"
+	teamhandler "devmetrics/internal/api/rest/handlers/team"
+	tenanthandler "devmetrics/internal/api/rest/handlers/tenant"
 	"devmetrics/internal/api/rest/handlers/vcs/github"
 	"devmetrics/internal/api/rest/handlers/vcs/gitlab"
+	"devmetrics/internal/api/rest/middleware"
 	"devmetrics/internal/api/rest/routes"
 	"devmetrics/internal/app"
 	"devmetrics/internal/config"
-	domain "devmetrics/internal/domain/vcs"
+	authdomain "devmetrics/internal/domain/auth"
+	identitydomain "devmetrics/internal/domain/identity"
+	teamdomain "devmetrics/internal/domain/team"
+	"devmetrics/internal/health"
+	"devmetrics/internal/secrets"
+	"devmetrics/internal/services/auth"
+	"devmetrics/internal/services/identity"
+	"devmetrics/internal/services/metrics"
+	"devmetrics/internal/services/reload"
+	"devmetrics/internal/services/team"
+	"devmetrics/internal/services/tenant"
 	"devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
 	"github.com/gofiber/fiber/v2/log"
 	"go.uber.org/dig"
 )
 
 func main() {
-	if err := run(buildContainer()); err != nil {
-		log.Fatal(err)
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML, TOML or JSON configuration file")
+	flag.Parse()
+
+	if err := run(buildContainer(*configFile)); err != nil {
+		log.Fatal(dig.RootCause(err))
 	}
 }
 
-func providers() []interface{} {
+func providers(configFile string) []interface{} {
 	return []interface{}{
 		// Config
-		config.NewConfig,
-		provideVCSConfig,
+		func() (*config.Config, error) {
+			return config.Load(configFile)
+		},
+
+		// Logging
+		provideLogger,
+
+		// Secrets
+		provideSecretStore,
+		provideMasterKey,
+		provideSecretResolver,
+		secrets.NewManager,
+
+		// Tenants
+		tenant.NewService,
+
+		// Identities
+		provideIdentityStore,
+		identity.NewService,
 
 		// VCS
 		adapter.NewFactory,
+		vcs.NewBotDetector,
 		provideVCSService,
+		vcs.NewAggregator,
+		metrics.NewSizeClassifier,
+		metrics.NewKnowledgeAnalyzer,
+
+		// Teams
+		provideTeamStore,
+		team.NewService,
+		func(cfg *config.Config, factory *adapter.Factory, vcs *vcs.Service, tenants *tenant.Service, resolver *secrets.Resolver, log logger.Logger) *reload.Service {
+			return reload.NewService(configFile, cfg, factory, vcs, tenants, resolver, log)
+		},
+
+		// Auth
+		provideAuthConfig,
+		provideKeyStore,
+		auth.NewService,
+		auth.LoadPolicy,
+		provideKeyProvider,
+		auth.NewTokenVerifier,
+		middleware.NewAuthenticator,
 
 		// HTTP Handlers
 		provideGitHubHandler,
 		provideGitLabHandler,
-		provideRoutes,
+		reposhandler.NewHandler,
+		aggregatehandler.NewHandler,
+		identityhandler.NewHandler,
+		teamhandler.NewHandler,
+		authhandler.NewHandler,
+		tenanthandler.NewHandler,
+		secrethandler.NewHandler,
+		reloadhandler.NewHandler,
+		provideHealthRegistry,
+		healthhandler.NewHandler,
+		routes.NewRoutes,
 		server.NewServer,
 
 		// Application
@@ -47,34 +123,134 @@ func providers() []interface{} {
 	}
 }
 
-func provideVCSConfig(cfg *config.Config) config.VCSConfig {
-	return cfg.VCS
+func provideAuthConfig(cfg *config.Config) config.AuthConfig {
+	return cfg.Auth
+}
+
+func provideKeyStore() authdomain.KeyStore {
+	return memory.NewKeyStore()
+}
+
+func provideIdentityStore() identitydomain.Store {
+	return memory.NewIdentityStore()
 }
 
-func provideVCSService(factory *adapter.Factory) (*vcs.Service, error) {
-	providers, err := factory.CreateProviders()
+func provideTeamStore() teamdomain.Store {
+	return memory.NewTeamStore()
+}
+
+func provideKeyProvider(cfg config.AuthConfig, log logger.Logger) auth.KeyProvider {
+	return oidc.NewKeySet(cfg.OIDC, log.With(logger.String("component", "jwks")))
+}
+
+func provideLogger(cfg *config.Config) (logger.Logger, error) {
+	loggerConfig, err := logger.NewConfig(
+		cfg.Logger.Level,
+		cfg.Logger.Format,
+		cfg.Logger.Output,
+		cfg.Logger.TimeFormat,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logger configuration: %w", err)
+	}
+	loggerConfig.Redact = secrets.Redact
+
+	log, err := logger.NewLogger(loggerConfig)
 	if err != nil {
-		return vcs.NewService(make(map[domain.ProviderType]domain.Provider)), nil
+		return nil, fmt.Errorf("failed to create logger: %w", err)
 	}
-	return vcs.NewService(providers), nil
+
+	log = log.With(logger.String("environment", cfg.Environment))
+	for _, warning := range cfg.Warnings {
+		log.Warn("Configuration: " + warning)
+	}
+	return log, nil
 }
 
-func provideGitHubHandler(service *vcs.Service) *github.Handler {
-	return github.NewHandler(service)
+func provideSecretStore(cfg *config.Config) (secrets.Store, error) {
+	return secrets.NewFileStore(cfg.Secrets.StoreFile)
+}
+
+func provideMasterKey(cfg *config.Config, log logger.Logger) (*secrets.MasterKey, error) {
+	masterKey, err := secrets.LoadMasterKey(cfg.Secrets.MasterKey, cfg.Secrets.MasterKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets master key: %w", err)
+	}
+	if masterKey == nil {
+		log.Warn("No secrets master key configured, the encrypted secret store is disabled")
+	}
+	return masterKey, nil
+}
+
+func provideSecretResolver(cfg *config.Config, store secrets.Store, masterKey *secrets.MasterKey) *secrets.Resolver {
+	resolver := secrets.NewResolver(time.Duration(cfg.Secrets.CacheTTL) * time.Second)
+	resolver.RegisterBackend(secrets.EnvBackendName, secrets.EnvBackend{})
+	resolver.RegisterBackend(secrets.FileBackendName, secrets.FileBackend{Dir: cfg.Secrets.FileDir})
+	resolver.RegisterBackend(secrets.StoreBackendName, secrets.StoreBackend{Store: store, MasterKey: masterKey})
+	return resolver
+}
+
+// provideVCSService creates and verifies every tenant's providers. In strict
+// mode any failure aborts startup. Otherwise providers that could not be
+// created are left out, while those that fail verification keep serving;
+// both are reported on the admin health report.
+func provideVCSService(cfg *config.Config, factory *adapter.Factory, identities *identity.Service, bots *vcs.BotDetector, log logger.Logger) (*vcs.Service, error) {
+	providers, failures := factory.CreateTenantProviders(cfg.Tenants)
+	service := vcs.NewService(providers, identities, bots, log)
+	for _, failure := range failures {
+		service.RecordFailure(failure.TenantID, failure.Provider, failure.Err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Startup.VerifyTimeout)*time.Second)
+	defer cancel()
+
+	var failed []string
+	for _, status := range service.VerifyProviders(ctx) {
+		if status.State != vcs.ProviderStateOK {
+			failed = append(failed, fmt.Sprintf("%s/%s: %s", status.TenantID, status.Provider, status.State))
+		}
+	}
+	if len(failed) > 0 && cfg.Startup.Strict {
+		return nil, fmt.Errorf("strict startup: VCS providers failed verification: %s", strings.Join(failed, ", "))
+	}
+
+	return service, nil
+}
+
+// provideHealthRegistry registers the readiness checks. The service has no
+// database, cache or background workers yet; they register here when added.
+func provideHealthRegistry(cfg *config.Config, vcsService *vcs.Service, store secrets.Store, keys auth.KeyProvider) *health.Registry {
+	registry := health.NewRegistry(time.Duration(cfg.Health.CheckTimeout) * time.Second)
+	registry.Register(health.Check{
+		Name:     "vcs_providers",
+		Critical: true,
+		Run:      health.ProviderCheck(vcsService, cfg.Health.MinRateLimitHeadroom),
+	})
+	registry.Register(health.Check{
+		Name: "secret_store",
+		Run:  health.PingCheck(store),
+	})
+	if pinger, ok := keys.(health.Pinger); ok && cfg.Auth.OIDC.Enabled {
+		registry.Register(health.Check{
+			Name: "oidc_jwks",
+			Run:  health.PingCheck(pinger),
+		})
+	}
+	return registry
 }
 
-func provideGitLabHandler(service *vcs.Service) *gitlab.Handler {
-	return gitlab.NewHandler(service)
+func provideGitHubHandler(service *vcs.Service, log logger.Logger) *github.Handler {
+	return github.NewHandler(service, log)
 }
 
-func provideRoutes(githubHandler *github.Handler, gitlabHandler *gitlab.Handler) *routes.Routes {
-	return routes.NewRoutes(githubHandler, gitlabHandler)
+func provideGitLabHandler(service *vcs.Service, log logger.Logger) *gitlab.Handler {
+	return gitlab.NewHandler(service, log)
 }
 
-func buildContainer() *dig.Container {
+func buildContainer(configFile string) *dig.Container {
 	container := dig.New()
 
-	for _, provider := range providers() {
+	for _, provider := range providers(configFile) {
 		if err := container.Provide(provider); err != nil {
 			log.Fatalf("dependency injection error: %v", err)
 		}
@@ -0,0 +1,267 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/pkg/logger"
+)
+
+// minRefreshInterval stops unknown key IDs from hammering the IdP
+const minRefreshInterval = time.Minute
+
+var ErrKeyNotFound = errors.New("signing key not found in JWKS")
+
+// KeySet fetches and caches the identity provider's JSON Web Key Set. Keys
+// come from a local file when one is configured, which is handy for tests.
+type KeySet struct {
+	issuer  string
+	jwksURL string
+	file    string
+	ttl     time.Duration
+	client  *http.Client
+	logger  logger.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func NewKeySet(cfg config.OIDCConfig, log logger.Logger) *KeySet {
+	return &KeySet{
+		issuer:  strings.TrimSuffix(cfg.Issuer, "/"),
+		jwksURL: cfg.JWKSURL,
+		file:    cfg.JWKSFile,
+		ttl:     time.Duration(cfg.JWKSCacheTTL) * time.Second,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  log,
+	}
+}
+
+// Key returns the public key with the given key ID, refreshing the cache when
+// it has expired or the key is unknown (the IdP may have rotated keys)
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	expired := time.Since(k.fetchedAt) > k.ttl
+	recentlyFetched := time.Since(k.fetchedAt) < minRefreshInterval
+	k.mu.RUnlock()
+
+	if ok && !expired {
+		return key, nil
+	}
+	if !ok && recentlyFetched && !expired {
+		return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+	}
+
+	if err := k.refresh(ctx); err != nil {
+		if ok {
+			// Serve the stale key rather than failing every request while the IdP is down
+			k.logger.Warn("Failed to refresh JWKS, using cached keys", logger.Error(err))
+			return key, nil
+		}
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if key, ok := k.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+}
+
+// Ping checks that signing keys are available, loading them if none are cached yet
+func (k *KeySet) Ping(ctx context.Context) error {
+	k.mu.RLock()
+	loaded := len(k.keys) > 0
+	k.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	return k.refresh(ctx)
+}
+
+func (k *KeySet) refresh(ctx context.Context) error {
+	data, err := k.load(ctx)
+	if err != nil {
+		return fmt.Errorf("loading JWKS: %w", err)
+	}
+
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+
+	k.logger.Debug("JWKS refreshed", logger.Int("keys", len(keys)))
+	return nil
+}
+
+func (k *KeySet) load(ctx context.Context) ([]byte, error) {
+	if k.file != "" {
+		return os.ReadFile(k.file)
+	}
+
+	jwksURL := k.jwksURL
+	if jwksURL == "" {
+		discovered, err := k.discover(ctx)
+		if err != nil {
+			return nil, err
+		}
+		jwksURL = discovered
+	}
+
+	return k.get(ctx, jwksURL)
+}
+
+// discover reads the jwks_uri from the issuer's OpenID configuration
+func (k *KeySet) discover(ctx context.Context) (string, error) {
+	data, err := k.get(ctx, k.issuer+"/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("OIDC discovery: %w", err)
+	}
+
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("OIDC discovery: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return "", fmt.Errorf("OIDC discovery: issuer did not advertise jwks_uri")
+	}
+	return doc.JWKSURI, nil
+}
+
+func (k *KeySet) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS decodes the RSA and EC signing keys of a JWKS document by key ID.
+// Keys of other types or meant for encryption are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decoding JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch jwk.Kty {
+		case "RSA":
+			key, err = jwk.rsaKey()
+		case "EC":
+			key, err = jwk.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding JWK %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (j jsonWebKey) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(j.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBigInt(j.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (j jsonWebKey) ecKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch j.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+	}
+
+	x, err := decodeBigInt(j.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBigInt(j.Y)
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"devmetrics/internal/domain/auth"
+)
+
+// KeyStore is an in-memory auth.KeyStore. Keys created at runtime are lost on
+// restart; persistent keys belong in configuration.
+type KeyStore struct {
+	mu     sync.RWMutex
+	byID   map[string]*auth.APIKey
+	byHash map[string]string
+}
+
+var _ auth.KeyStore = (*KeyStore)(nil)
+
+func NewKeyStore() *KeyStore {
+	return &KeyStore{
+		byID:   make(map[string]*auth.APIKey),
+		byHash: make(map[string]string),
+	}
+}
+
+func (s *KeyStore) Create(_ context.Context, key *auth.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.byID[key.ID]; ok {
+		return auth.ErrKeyExists
+	}
+	if _, ok := s.byHash[key.Hash]; ok {
+		return auth.ErrKeyExists
+	}
+
+	stored := *key
+	s.byID[key.ID] = &stored
+	s.byHash[key.Hash] = key.ID
+	return nil
+}
+
+func (s *KeyStore) GetByHash(_ context.Context, hash string) (*auth.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return nil, auth.ErrKeyNotFound
+	}
+	key := *s.byID[id]
+	return &key, nil
+}
+
+func (s *KeyStore) Get(_ context.Context, id string) (*auth.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.byID[id]
+	if !ok {
+		return nil, auth.ErrKeyNotFound
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (s *KeyStore) List(_ context.Context) ([]*auth.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*auth.APIKey, 0, len(s.byID))
+	for _, key := range s.byID {
+		copied := *key
+		keys = append(keys, &copied)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+func (s *KeyStore) Update(_ context.Context, key *auth.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.byID[key.ID]; !ok {
+		return auth.ErrKeyNotFound
+	}
+	stored := *key
+	s.byID[key.ID] = &stored
+	return nil
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"devmetrics/internal/domain/identity"
+)
+
+// IdentityStore is an in-memory identity.Store. Overrides and mailmaps set at
+// runtime are lost on restart.
+type IdentityStore struct {
+	mu        sync.RWMutex
+	overrides map[string]map[string]identity.Override
+	mailmaps  map[string]string
+}
+
+var _ identity.Store = (*IdentityStore)(nil)
+
+func NewIdentityStore() *IdentityStore {
+	return &IdentityStore{
+		overrides: make(map[string]map[string]identity.Override),
+		mailmaps:  make(map[string]string),
+	}
+}
+
+func (s *IdentityStore) ListOverrides(_ context.Context, tenantID string) ([]identity.Override, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	overrides := make([]identity.Override, 0, len(s.overrides[tenantID]))
+	for _, override := range s.overrides[tenantID] {
+		overrides = append(overrides, override)
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].ID < overrides[j].ID
+	})
+	return overrides, nil
+}
+
+func (s *IdentityStore) PutOverride(_ context.Context, tenantID string, override identity.Override) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.overrides[tenantID] == nil {
+		s.overrides[tenantID] = make(map[string]identity.Override)
+	}
+	s.overrides[tenantID][override.ID] = override
+	return nil
+}
+
+func (s *IdentityStore) DeleteOverride(_ context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.overrides[tenantID][id]; !ok {
+		return identity.ErrOverrideNotFound
+	}
+	delete(s.overrides[tenantID], id)
+	return nil
+}
+
+func (s *IdentityStore) GetMailmap(_ context.Context, tenantID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mailmaps[tenantID], nil
+}
+
+func (s *IdentityStore) PutMailmap(_ context.Context, tenantID, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mailmaps[tenantID] = content
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"devmetrics/internal/domain/team"
+)
+
+// TeamStore is an in-memory team.Store. Teams created at runtime are lost on restart.
+type TeamStore struct {
+	mu    sync.RWMutex
+	teams map[string]map[string]team.Team
+}
+
+var _ team.Store = (*TeamStore)(nil)
+
+func NewTeamStore() *TeamStore {
+	return &TeamStore{teams: make(map[string]map[string]team.Team)}
+}
+
+func (s *TeamStore) List(_ context.Context, tenantID string) ([]team.Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	teams := make([]team.Team, 0, len(s.teams[tenantID]))
+	for _, t := range s.teams[tenantID] {
+		teams = append(teams, t)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].ID < teams[j].ID
+	})
+	return teams, nil
+}
+
+func (s *TeamStore) Get(_ context.Context, tenantID, id string) (*team.Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.teams[tenantID][id]
+	if !ok {
+		return nil, team.ErrNotFound
+	}
+	return &t, nil
+}
+
+func (s *TeamStore) Put(_ context.Context, tenantID string, t team.Team) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.teams[tenantID] == nil {
+		s.teams[tenantID] = make(map[string]team.Team)
+	}
+	s.teams[tenantID][t.ID] = t
+	return nil
+}
+
+func (s *TeamStore) Delete(_ context.Context, tenantID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.teams[tenantID][id]; !ok {
+		return team.ErrNotFound
+	}
+	delete(s.teams[tenantID], id)
+	return nil
+}
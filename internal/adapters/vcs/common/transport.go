@@ -0,0 +1,68 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+
+	"devmetrics/internal/secrets"
+	"devmetrics/pkg/requestid"
+)
+
+// RequestIDTransport forwards the request ID found in the outgoing request's
+// context to the upstream API as an X-Request-ID header
+type RequestIDTransport struct {
+	Base http.RoundTripper
+}
+
+func NewRequestIDTransport(base http.RoundTripper) *RequestIDTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RequestIDTransport{Base: base}
+}
+
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := requestid.FromContext(req.Context())
+	if id == "" || req.Header.Get(requestid.Header) != "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the caller's request
+	clone := req.Clone(req.Context())
+	clone.Header.Set(requestid.Header, id)
+	return t.Base.RoundTrip(clone)
+}
+
+// AuthTransport sets a credential header from a token source on every
+// request, so rotated tokens take effect without rebuilding the client
+type AuthTransport struct {
+	Base   http.RoundTripper
+	Header string
+	Prefix string
+	Token  secrets.TokenSource
+}
+
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("resolving provider token: %w", err)
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Header.Set(t.Header, t.Prefix+token)
+	return t.Base.RoundTrip(clone)
+}
+
+// NewHTTPClient builds the HTTP client used by provider adapters. Request IDs
+// are only forwarded when enabled for the provider; auth may be nil.
+func NewHTTPClient(forwardRequestID bool, auth *AuthTransport) *http.Client {
+	transport := http.DefaultTransport
+	if forwardRequestID {
+		transport = NewRequestIDTransport(transport)
+	}
+	if auth != nil {
+		auth.Base = transport
+		transport = auth
+	}
+	return &http.Client{Transport: transport}
+}
@@ -1,12 +1,16 @@
 package vcs
 
 import (
+	"context"
 	"devmetrics/internal/adapters/vcs/github"
 	"devmetrics/internal/adapters/vcs/gitlab"
 	"devmetrics/internal/config"
 	"devmetrics/internal/domain/vcs"
+	"devmetrics/internal/secrets"
+	"devmetrics/pkg/logger"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -14,57 +18,127 @@ var (
 )
 
 type Factory struct {
-	vcsConfig config.VCSConfig
+	secrets *secrets.Resolver
+	logger  logger.Logger
 }
 
-func NewFactory(cfg config.VCSConfig) *Factory {
+func NewFactory(resolver *secrets.Resolver, log logger.Logger) *Factory {
 	return &Factory{
-		vcsConfig: cfg,
+		secrets: resolver,
+		logger:  log,
 	}
 }
 
-func (f *Factory) CreateProviders() (map[vcs.ProviderType]vcs.Provider, error) {
-	providers := make(map[vcs.ProviderType]vcs.Provider)
+// tokenSource resolves a configured credential once up front, so a broken
+// secret reference fails provider creation instead of the first request
+func (f *Factory) tokenSource(value string) (secrets.TokenSource, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	if err := f.createGitHubProvider(providers); err != nil {
+	if _, err := f.secrets.Resolve(ctx, value); err != nil {
 		return nil, err
 	}
+	return f.secrets.Source(value), nil
+}
 
-	if err := f.createGitLabProvider(providers); err != nil {
-		return nil, err
+// ProviderError records a tenant's provider that could not be created
+type ProviderError struct {
+	TenantID string
+	Provider vcs.ProviderType
+	Err      error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("tenant %q: %v", e.TenantID, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// CreateTenantProviders builds an isolated provider set for every tenant, so
+// no tenant ever shares credentials or clients with another. Providers that
+// can't be created are left out and reported, the others are still returned.
+func (f *Factory) CreateTenantProviders(tenants []config.TenantConfig) (map[string]map[vcs.ProviderType]vcs.Provider, []*ProviderError) {
+	result := make(map[string]map[vcs.ProviderType]vcs.Provider, len(tenants))
+	var failures []*ProviderError
+
+	for _, tenant := range tenants {
+		providers, errs := f.createProviders(tenant.ID, tenant.VCS)
+		result[tenant.ID] = providers
+		failures = append(failures, errs...)
 	}
 
+	return result, failures
+}
+
+// CreateProviders builds a tenant's providers, failing if any of them can't be created
+func (f *Factory) CreateProviders(tenantID string, cfg config.VCSConfig) (map[vcs.ProviderType]vcs.Provider, error) {
+	providers, errs := f.createProviders(tenantID, cfg)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
 	return providers, nil
 }
 
-func (f *Factory) createGitHubProvider(providers map[vcs.ProviderType]vcs.Provider) error {
-	if !f.vcsConfig.GitHub.Enabled {
+func (f *Factory) createProviders(tenantID string, cfg config.VCSConfig) (map[vcs.ProviderType]vcs.Provider, []*ProviderError) {
+	providers := make(map[vcs.ProviderType]vcs.Provider)
+	log := f.logger.With(logger.String("tenant", tenantID))
+	var errs []*ProviderError
+
+	if err := f.createGitHubProvider(cfg, providers, log); err != nil {
+		errs = append(errs, &ProviderError{TenantID: tenantID, Provider: vcs.ProviderGitHub, Err: err})
+	}
+
+	if err := f.createGitLabProvider(cfg, providers, log); err != nil {
+		errs = append(errs, &ProviderError{TenantID: tenantID, Provider: vcs.ProviderGitLab, Err: err})
+	}
+
+	return providers, errs
+}
+
+func (f *Factory) createGitHubProvider(cfg config.VCSConfig, providers map[vcs.ProviderType]vcs.Provider, log logger.Logger) error {
+	if !cfg.GitHub.Enabled {
 		return nil
 	}
 
-	provider, err := github.NewAdapter(f.vcsConfig.GitHub)
+	log = log.With(logger.String("provider", string(vcs.ProviderGitHub)))
+	token, err := f.tokenSource(cfg.GitHub.Token)
+	if err != nil {
+		return fmt.Errorf("failed to resolve GitHub token: %w", err)
+	}
+
+	provider, err := github.NewAdapter(cfg.GitHub, token, log)
 	if err != nil {
 		return fmt.Errorf("failed to create GitHub provider: %w", err)
 	}
 
 	providers[vcs.ProviderGitHub] = provider
+	log.Info("VCS provider enabled")
 	return nil
 }
 
-func (f *Factory) createGitLabProvider(providers map[vcs.ProviderType]vcs.Provider) error {
-	if !f.vcsConfig.GitLab.Enabled {
+func (f *Factory) createGitLabProvider(cfg config.VCSConfig, providers map[vcs.ProviderType]vcs.Provider, log logger.Logger) error {
+	if !cfg.GitLab.Enabled {
 		return nil
 	}
 
-	provider, err := gitlab.NewAdapter(f.vcsConfig.GitLab)
+	log = log.With(logger.String("provider", string(vcs.ProviderGitLab)))
+	token, err := f.tokenSource(cfg.GitLab.Token)
+	if err != nil {
+		return fmt.Errorf("failed to resolve GitLab token: %w", err)
+	}
+
+	provider, err := gitlab.NewAdapter(cfg.GitLab, token, log)
 	if err != nil {
 		return fmt.Errorf("failed to create GitLab provider: %w", err)
 	}
 
 	providers[vcs.ProviderGitLab] = provider
+	log.Info("VCS provider enabled")
 	return nil
 }
 
-func (f *Factory) createBitBucketProvider(providers map[vcs.ProviderType]vcs.Provider) error {
+func (f *Factory) createBitBucketProvider(cfg config.VCSConfig, providers map[vcs.ProviderType]vcs.Provider) error {
 	return ErrProviderNotImplemented
 }
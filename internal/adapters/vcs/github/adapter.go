@@ -5,35 +5,49 @@ import (
 	"devmetrics/internal/adapters/vcs/common"
 	"devmetrics/internal/config"
 	"fmt"
-	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
 
+	"devmetrics/internal/secrets"
 	"github.com/google/go-github/v45/github"
-	"golang.org/x/oauth2"
 )
 
 type Adapter struct {
 	client *github.Client
 	config config.GitHubConfig
+	logger logger.Logger
 }
 
 var _ vcs.Provider = (*Adapter)(nil)
 
-func NewAdapter(cfg config.GitHubConfig) (*Adapter, error) {
+func NewAdapter(cfg config.GitHubConfig, token secrets.TokenSource, log logger.Logger) (*Adapter, error) {
 	if cfg.Token == "" {
 		return nil, fmt.Errorf("github token is required")
 	}
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cfg.Token})
-	tc := oauth2.NewClient(context.Background(), ts)
-
-	client := github.NewClient(tc)
+	httpClient := common.NewHTTPClient(cfg.ForwardRequestID, &common.AuthTransport{
+		Header: "Authorization",
+		Prefix: "Bearer ",
+		Token:  token,
+	})
+
+	client := github.NewClient(httpClient)
+	if cfg.BaseURL != "" {
+		baseURL, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid github base URL: %w", err)
+		}
+		client.BaseURL = baseURL
+	}
 
 	return &Adapter{
 		client: client,
 		config: cfg,
+		logger: log,
 	}, nil
 }
 
@@ -42,7 +56,7 @@ func (a *Adapter) GetRepository(ctx context.Context, repo string) (*vcs.Reposito
 
 	repository, _, err := a.client.Repositories.Get(ctx, owner, repoName)
 	if err != nil {
-		return nil, fmt.Errorf("getting repository: %w", err)
+		return nil, translateError("getting repository", err)
 	}
 
 	return a.mapRepository(repository), nil
@@ -73,7 +87,7 @@ func (a *Adapter) GetCommits(ctx context.Context, repo string, since, until time
 
 	commits, resp, err := a.client.Repositories.ListCommits(ctx, owner, repoName, opts)
 	if err != nil {
-		return nil, 0, fmt.Errorf("listing commits: %w", err)
+		return nil, 0, translateError("listing commits", err)
 	}
 
 	var results []vcs.Commit
@@ -111,7 +125,7 @@ func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until
 
 	prs, resp, err := a.client.PullRequests.List(ctx, owner, repoName, opts)
 	if err != nil {
-		return nil, 0, fmt.Errorf("listing pull requests: %w", err)
+		return nil, 0, translateError("listing pull requests", err)
 	}
 
 	var results []vcs.PullRequest
@@ -119,7 +133,11 @@ func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until
 		if isInTimeRange(pr.CreatedAt, since, until) {
 			details, _, err := a.client.PullRequests.Get(ctx, owner, repoName, pr.GetNumber())
 			if err != nil {
-				log.Printf("Failed to fetch PR details: pr_number=%d, error=%v", pr.GetNumber(), err)
+				logger.FromContext(ctx, a.logger).Warn("Failed to fetch PR details",
+					logger.String("repo", repo),
+					logger.Int("pr_number", pr.GetNumber()),
+					logger.Error(err),
+				)
 				continue
 			}
 			results = append(results, a.mapPullRequest(details, repo))
@@ -133,3 +151,13 @@ func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until
 
 	return results, total, nil
 }
+
+// WebHost returns github.com for the public API and the server host for
+// GitHub Enterprise, whose API lives under /api/v3 or on an api. subdomain
+func (a *Adapter) WebHost() string {
+	host := a.client.BaseURL.Hostname()
+	if host == "api.github.com" {
+		return "github.com"
+	}
+	return strings.TrimPrefix(host, "api.")
+}
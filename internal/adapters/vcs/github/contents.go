@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+
+	"devmetrics/internal/adapters/vcs/common"
+	"devmetrics/internal/domain/vcs"
+)
+
+// GetFileContent reads a file of the default branch. The contents API
+// serves files of up to 1 MB.
+func (a *Adapter) GetFileContent(ctx context.Context, repo, path string) ([]byte, error) {
+	owner, repoName := common.ParseRepoString(repo)
+
+	file, _, _, err := a.client.Repositories.GetContents(ctx, owner, repoName, path, nil)
+	if err != nil {
+		return nil, translateError("getting file content", err)
+	}
+	if file == nil {
+		return nil, vcs.NewError(vcs.ErrNotFound, vcs.ProviderGitHub, "getting file content", nil)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, translateError("decoding file content", err)
+	}
+	return []byte(content), nil
+}
+
+// GetTree lists the files of the default branch in one request. GitHub
+// truncates trees of more than 100,000 entries.
+func (a *Adapter) GetTree(ctx context.Context, repo string) (*vcs.Tree, error) {
+	owner, repoName := common.ParseRepoString(repo)
+
+	tree, _, err := a.client.Git.GetTree(ctx, owner, repoName, "HEAD", true)
+	if err != nil {
+		return nil, translateError("getting tree", err)
+	}
+	result := &vcs.Tree{Paths: []string{}, Truncated: tree.GetTruncated()}
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			result.Paths = append(result.Paths, entry.GetPath())
+		}
+	}
+	return result, nil
+}
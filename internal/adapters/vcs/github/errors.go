@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/google/go-github/v45/github"
+)
+
+// translateError converts go-github errors into vcs.Error values
+func translateError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		vcsErr := vcs.NewError(vcs.ErrRateLimited, vcs.ProviderGitHub, op, err)
+		vcsErr.ResetAt = rateErr.Rate.Reset.Time
+		return vcsErr
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		vcsErr := vcs.NewError(vcs.ErrRateLimited, vcs.ProviderGitHub, op, err)
+		if abuseErr.RetryAfter != nil {
+			vcsErr.ResetAt = time.Now().Add(*abuseErr.RetryAfter)
+		}
+		return vcsErr
+	}
+
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		kind := vcs.KindForStatus(respErr.Response.StatusCode)
+		if kind == nil {
+			return err
+		}
+		vcsErr := vcs.NewError(kind, vcs.ProviderGitHub, op, err)
+		if kind == vcs.ErrRateLimited {
+			vcsErr.ResetAt = resetFromHeaders(respErr.Response.Header)
+		}
+		return vcsErr
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return vcs.NewError(vcs.ErrUpstreamUnavailable, vcs.ProviderGitHub, op, err)
+	}
+
+	return err
+}
+
+// resetFromHeaders reads Retry-After or X-RateLimit-Reset from a response
+func resetFromHeaders(header http.Header) time.Time {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(epoch, 0)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+
+	"devmetrics/internal/adapters/vcs/common"
+	"devmetrics/internal/domain/vcs"
+	"github.com/google/go-github/v45/github"
+)
+
+// GetCommitFiles pages through the files of a commit. GitHub lists at most
+// 3000 files of a commit.
+func (a *Adapter) GetCommitFiles(ctx context.Context, repo, sha string) ([]vcs.FileChange, error) {
+	owner, repoName := common.ParseRepoString(repo)
+
+	files := []vcs.FileChange{}
+	for page := 1; page <= a.config.MaxPages; page++ {
+		commit, resp, err := a.client.Repositories.GetCommit(ctx, owner, repoName, sha, &github.ListOptions{Page: page, PerPage: a.config.PageSize})
+		if err != nil {
+			return nil, translateError("getting commit files", err)
+		}
+		for _, file := range commit.Files {
+			files = append(files, mapFileChange(file))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return files, nil
+}
+
+// GetPullRequestFiles pages through the files of a pull request. GitHub
+// lists at most 3000 files of a pull request.
+func (a *Adapter) GetPullRequestFiles(ctx context.Context, repo string, number int) ([]vcs.FileChange, error) {
+	owner, repoName := common.ParseRepoString(repo)
+
+	files := []vcs.FileChange{}
+	for page := 1; page <= a.config.MaxPages; page++ {
+		prFiles, resp, err := a.client.PullRequests.ListFiles(ctx, owner, repoName, number, &github.ListOptions{Page: page, PerPage: a.config.PageSize})
+		if err != nil {
+			return nil, translateError("listing pull request files", err)
+		}
+		for _, file := range prFiles {
+			files = append(files, mapFileChange(file))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return files, nil
+}
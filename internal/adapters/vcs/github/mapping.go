@@ -16,11 +16,13 @@ func (a *Adapter) mapCommit(ghCommit *github.RepositoryCommit, repoID string) vc
 		Message:      ghCommit.Commit.GetMessage(),
 		AuthorName:   ghCommit.Commit.Author.GetName(),
 		AuthorEmail:  ghCommit.Commit.Author.GetEmail(),
+		AuthorLogin:  ghCommit.GetAuthor().GetLogin(),
+		AuthorBot:    isBot(ghCommit.GetAuthor()),
 		CommittedAt:  ghCommit.Commit.Author.GetDate(),
-		ChangedFiles: ghCommit.GetStats().GetTotal(),
 		Additions:    ghCommit.GetStats().GetAdditions(),
 		Deletions:    ghCommit.GetStats().GetDeletions(),
 		RepositoryID: repoID,
+		Merge:        len(ghCommit.Parents) > 1,
 	}
 }
 
@@ -29,15 +31,18 @@ func (a *Adapter) mapPullRequest(pr *github.PullRequest, repoID string) vcs.Pull
 		return vcs.PullRequest{}
 	}
 
-	return vcs.PullRequest{
+	mapped := vcs.PullRequest{
 		Number:       pr.GetNumber(),
 		Title:        pr.GetTitle(),
 		State:        pr.GetState(),
+		BaseBranch:   pr.GetBase().GetRef(),
 		CreatedAt:    pr.GetCreatedAt(),
 		UpdatedAt:    pr.GetUpdatedAt(),
 		ClosedAt:     pr.ClosedAt,
 		MergedAt:     pr.MergedAt,
 		AuthorName:   pr.User.GetLogin(),
+		AuthorLogin:  pr.User.GetLogin(),
+		AuthorBot:    isBot(pr.User),
 		ReviewCount:  pr.GetReviewComments(),
 		CommitCount:  pr.GetCommits(),
 		ChangedFiles: pr.GetChangedFiles(),
@@ -45,6 +50,58 @@ func (a *Adapter) mapPullRequest(pr *github.PullRequest, repoID string) vcs.Pull
 		Deletions:    pr.GetDeletions(),
 		RepositoryID: repoID,
 	}
+	for _, reviewer := range pr.RequestedReviewers {
+		mapped.RequestedReviewers = append(mapped.RequestedReviewers, mapReviewer(reviewer))
+	}
+	return mapped
+}
+
+// mapFileChange folds GitHub's copied, changed and unchanged statuses into
+// added and modified
+func mapFileChange(file *github.CommitFile) vcs.FileChange {
+	change := vcs.FileChange{
+		Path:      file.GetFilename(),
+		Additions: file.GetAdditions(),
+		Deletions: file.GetDeletions(),
+	}
+	switch file.GetStatus() {
+	case "added", "copied":
+		change.Status = vcs.FileAdded
+	case "removed":
+		change.Status = vcs.FileRemoved
+	case "renamed":
+		change.Status = vcs.FileRenamed
+		change.PreviousPath = file.GetPreviousFilename()
+	default:
+		change.Status = vcs.FileModified
+	}
+	return change
+}
+
+// mapReview maps GitHub review states; drafts have no submission time
+func mapReview(review *github.PullRequestReview) vcs.Review {
+	mapped := vcs.Review{Reviewer: mapReviewer(review.GetUser())}
+	switch review.GetState() {
+	case "APPROVED":
+		mapped.State = vcs.ReviewApproved
+	case "CHANGES_REQUESTED":
+		mapped.State = vcs.ReviewChangesRequested
+	case "DISMISSED":
+		mapped.State = vcs.ReviewDismissed
+	case "PENDING":
+		mapped.State = vcs.ReviewPending
+	default:
+		mapped.State = vcs.ReviewCommented
+	}
+	if review.SubmittedAt != nil {
+		submitted := review.GetSubmittedAt()
+		mapped.SubmittedAt = &submitted
+	}
+	return mapped
+}
+
+func mapReviewer(user *github.User) vcs.Reviewer {
+	return vcs.Reviewer{Login: user.GetLogin(), Bot: isBot(user)}
 }
 
 func (a *Adapter) mapRepository(repo *github.Repository) *vcs.Repository {
@@ -62,5 +119,30 @@ func (a *Adapter) mapRepository(repo *github.Repository) *vcs.Repository {
 		Description:   repo.GetDescription(),
 		Language:      repo.GetLanguage(),
 		Private:       repo.GetPrivate(),
+		Path:          repo.GetFullName(),
+		Visibility:    repositoryVisibility(repo),
+		Archived:      repo.GetArchived(),
+		Fork:          repo.GetFork(),
+		Topics:        repo.Topics,
+		// Metadata edits bump updated_at, pushed_at reflects code activity
+		LastActivityAt: repo.GetPushedAt().Time,
+	}
+}
+
+// isBot reports whether GitHub reports the account as a bot, as it does for
+// GitHub Apps such as Dependabot
+func isBot(user *github.User) bool {
+	return user.GetType() == "Bot"
+}
+
+// repositoryVisibility falls back to the private flag for servers that
+// don't report visibility
+func repositoryVisibility(repo *github.Repository) string {
+	if visibility := repo.GetVisibility(); visibility != "" {
+		return visibility
+	}
+	if repo.GetPrivate() {
+		return "private"
 	}
+	return "public"
 }
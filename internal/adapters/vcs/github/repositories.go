@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"errors"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/google/go-github/v45/github"
+)
+
+// ListRepositories lists an organization's repositories, falling back to a
+// user's when no such organization exists. Results are ordered by last push,
+// so paging stops once repositories fall outside filter.ActiveSince.
+func (a *Adapter) ListRepositories(ctx context.Context, owner string, filter vcs.RepositoryFilter) ([]vcs.Repository, error) {
+	results, err := a.listRepositories(ctx, owner, filter, a.orgPage)
+	if errors.Is(err, vcs.ErrNotFound) {
+		results, err = a.listRepositories(ctx, owner, filter, a.userPage)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+type repositoryPageFunc func(ctx context.Context, owner string, filter vcs.RepositoryFilter, page int) ([]*github.Repository, *github.Response, error)
+
+func (a *Adapter) listRepositories(ctx context.Context, owner string, filter vcs.RepositoryFilter, fetch repositoryPageFunc) ([]vcs.Repository, error) {
+	results := []vcs.Repository{}
+	for page := 1; page <= a.config.MaxPages; page++ {
+		repos, resp, err := fetch(ctx, owner, filter, page)
+		if err != nil {
+			return nil, translateError("listing repositories", err)
+		}
+
+		for _, repo := range repos {
+			mapped := a.mapRepository(repo)
+			if !filter.ActiveSince.IsZero() && mapped.LastActivityAt.Before(filter.ActiveSince) {
+				return results, nil
+			}
+			if filter.Matches(*mapped) {
+				results = append(results, *mapped)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (a *Adapter) orgPage(ctx context.Context, owner string, filter vcs.RepositoryFilter, page int) ([]*github.Repository, *github.Response, error) {
+	repoType := "all"
+	if filter.Visibility == "public" || filter.Visibility == "private" {
+		repoType = filter.Visibility
+	}
+	return a.client.Repositories.ListByOrg(ctx, owner, &github.RepositoryListByOrgOptions{
+		Type:        repoType,
+		Sort:        "pushed",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{Page: page, PerPage: a.config.PageSize},
+	})
+}
+
+func (a *Adapter) userPage(ctx context.Context, owner string, _ vcs.RepositoryFilter, page int) ([]*github.Repository, *github.Response, error) {
+	return a.client.Repositories.List(ctx, owner, &github.RepositoryListOptions{
+		Type:        "owner",
+		Sort:        "pushed",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{Page: page, PerPage: a.config.PageSize},
+	})
+}
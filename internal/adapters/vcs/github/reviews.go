@@ -0,0 +1,29 @@
+package github
+
+import (
+	"context"
+
+	"devmetrics/internal/adapters/vcs/common"
+	"devmetrics/internal/domain/vcs"
+	"github.com/google/go-github/v45/github"
+)
+
+// GetPullRequestReviews pages through the reviews of a pull request, oldest first
+func (a *Adapter) GetPullRequestReviews(ctx context.Context, repo string, number int) ([]vcs.Review, error) {
+	owner, repoName := common.ParseRepoString(repo)
+
+	reviews := []vcs.Review{}
+	for page := 1; page <= a.config.MaxPages; page++ {
+		prReviews, resp, err := a.client.PullRequests.ListReviews(ctx, owner, repoName, number, &github.ListOptions{Page: page, PerPage: a.config.PageSize})
+		if err != nil {
+			return nil, translateError("listing pull request reviews", err)
+		}
+		for _, review := range prReviews {
+			reviews = append(reviews, mapReview(review))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return reviews, nil
+}
@@ -0,0 +1,48 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/google/go-github/v45/github"
+)
+
+func (a *Adapter) GetUser(ctx context.Context, login string) (*vcs.User, error) {
+	user, _, err := a.client.Users.Get(ctx, login)
+	if err != nil {
+		return nil, translateError("getting user", err)
+	}
+
+	return &vcs.User{
+		Login: user.GetLogin(),
+		Name:  user.GetName(),
+		Email: user.GetEmail(),
+	}, nil
+}
+
+// ListGroupMembers lists the members of a team, including those of its child teams
+func (a *Adapter) ListGroupMembers(ctx context.Context, group string) ([]vcs.User, error) {
+	org, slug, ok := strings.Cut(group, "/")
+	if !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, vcs.ProviderGitHub, "listing team members", fmt.Errorf("team %q must be written as org/team-slug", group))
+	}
+
+	members := []vcs.User{}
+	for page := 1; page <= a.config.MaxPages; page++ {
+		users, resp, err := a.client.Teams.ListTeamMembersBySlug(ctx, org, slug, &github.TeamListTeamMembersOptions{
+			ListOptions: github.ListOptions{Page: page, PerPage: a.config.PageSize},
+		})
+		if err != nil {
+			return nil, translateError("listing team members", err)
+		}
+		for _, user := range users {
+			members = append(members, vcs.User{Login: user.GetLogin()})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return members, nil
+}
@@ -1,7 +1,7 @@
 package github
 
 import (
-	"log"
+	"devmetrics/pkg/logger"
 	"net/url"
 	"strconv"
 	"strings"
@@ -36,7 +36,7 @@ func (a *Adapter) extractTotalFromLink(links string) int64 {
 		// Parse the URL
 		parsedURL, err := url.Parse(urlStr)
 		if err != nil {
-			log.Printf("Failed to parse URL from Link header: %v", err)
+			a.logger.Debug("Failed to parse URL from Link header", logger.Error(err))
 			continue
 		}
 
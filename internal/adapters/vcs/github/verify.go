@@ -0,0 +1,56 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// Verify fetches the token's user. Classic tokens report their scopes and
+// need repo or public_repo; fine-grained and app tokens report none.
+func (a *Adapter) Verify(ctx context.Context) (*vcs.Verification, error) {
+	user, resp, err := a.client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, translateError("verifying credentials", err)
+	}
+
+	verification := &vcs.Verification{
+		Identity:  user.GetLogin(),
+		RateLimit: rateLimitFromHeaders(resp.Header),
+	}
+	if header, ok := resp.Header[http.CanonicalHeaderKey("X-OAuth-Scopes")]; ok {
+		verification.Scopes = splitScopes(strings.Join(header, ","))
+		verification.MissingScopes = vcs.MissingScopes(verification.Scopes, "repo", "public_repo")
+	}
+	return verification, nil
+}
+
+func splitScopes(header string) []string {
+	scopes := []string{}
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func rateLimitFromHeaders(header http.Header) *vcs.RateLimit {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return nil
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	rateLimit := &vcs.RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.ResetAt = time.Unix(reset, 0)
+	}
+	return rateLimit
+}
@@ -5,13 +5,19 @@ import (
 	"fmt"
 	"time"
 
+	"devmetrics/internal/adapters/vcs/common"
 	"devmetrics/internal/config"
 	"devmetrics/internal/domain/vcs"
+	"devmetrics/internal/secrets"
+	"devmetrics/pkg/logger"
 	"github.com/xanzy/go-gitlab"
 )
 
 type Adapter struct {
-	client *gitlab.Client
+	client   *gitlab.Client
+	maxPages int
+	pageSize int
+	logger   logger.Logger
 }
 
 type commitResult struct {
@@ -24,21 +30,34 @@ type prResult struct {
 	err error
 }
 
-func NewAdapter(cfg config.GitLabConfig) (*Adapter, error) {
-	client, err := gitlab.NewClient(cfg.Token, gitlab.WithBaseURL(cfg.BaseURL))
+func NewAdapter(cfg config.GitLabConfig, token secrets.TokenSource, log logger.Logger) (*Adapter, error) {
+	// The token is set per request by the transport; go-gitlab only sees a placeholder
+	httpClient := common.NewHTTPClient(cfg.ForwardRequestID, &common.AuthTransport{
+		Header: "PRIVATE-TOKEN",
+		Token:  token,
+	})
+
+	client, err := gitlab.NewClient(
+		"",
+		gitlab.WithBaseURL(cfg.BaseURL),
+		gitlab.WithHTTPClient(httpClient),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
 
 	return &Adapter{
-		client: client,
+		client:   client,
+		maxPages: cfg.MaxPages,
+		pageSize: cfg.PageSize,
+		logger:   log,
 	}, nil
 }
 
 func (a *Adapter) GetRepository(ctx context.Context, repo string) (*vcs.Repository, error) {
 	project, _, err := a.client.Projects.GetProject(repo, &gitlab.GetProjectOptions{}, gitlab.WithContext(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get GitLab project: %w", err)
+		return nil, translateError("getting project", err)
 	}
 
 	return a.mapRepository(project), nil
@@ -47,7 +66,7 @@ func (a *Adapter) GetRepository(ctx context.Context, repo string) (*vcs.Reposito
 func (a *Adapter) GetCommits(ctx context.Context, repo string, since, until time.Time, offset, limit int) ([]vcs.Commit, int64, error) {
 	project, _, err := a.client.Projects.GetProject(repo, &gitlab.GetProjectOptions{}, gitlab.WithContext(ctx))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get GitLab project for commits: %w", err)
+		return nil, 0, translateError("getting project for commits", err)
 	}
 
 	// Count total commits by fetching until we hit a page with no commits
@@ -65,7 +84,7 @@ func (a *Adapter) GetCommits(ctx context.Context, repo string, since, until time
 
 		commits, resp, err := a.client.Commits.ListCommits(project.ID, countOpts, gitlab.WithContext(ctx))
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to count commits: %w", err)
+			return nil, 0, translateError("counting commits", err)
 		}
 
 		if len(commits) == 0 {
@@ -93,7 +112,7 @@ func (a *Adapter) GetCommits(ctx context.Context, repo string, since, until time
 
 	commits, _, err := a.client.Commits.ListCommits(project.ID, opts, gitlab.WithContext(ctx))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list GitLab commits: %w", err)
+		return nil, 0, translateError("listing commits", err)
 	}
 
 	var results []vcs.Commit
@@ -112,7 +131,7 @@ func (a *Adapter) GetCommits(ctx context.Context, repo string, since, until time
 				gitlab.WithContext(ctx),
 			)
 			if err != nil {
-				resultChan <- commitResult{err: fmt.Errorf("failed to get commit details for %s: %w", commit.ID, err)}
+				resultChan <- commitResult{err: translateError(fmt.Sprintf("getting commit details for %s", commit.ID), err)}
 				return
 			}
 
@@ -134,7 +153,7 @@ func (a *Adapter) GetCommits(ctx context.Context, repo string, since, until time
 func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until time.Time, offset, limit int) ([]vcs.PullRequest, int64, error) {
 	project, _, err := a.client.Projects.GetProject(repo, &gitlab.GetProjectOptions{}, gitlab.WithContext(ctx))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get GitLab project for merge requests: %w", err)
+		return nil, 0, translateError("getting project for merge requests", err)
 	}
 
 	opts := &gitlab.ListProjectMergeRequestsOptions{
@@ -148,7 +167,7 @@ func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until
 
 	mrs, resp, err := a.client.MergeRequests.ListProjectMergeRequests(project.ID, opts, gitlab.WithContext(ctx))
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list GitLab merge requests: %w", err)
+		return nil, 0, translateError("listing merge requests", err)
 	}
 
 	var results []vcs.PullRequest
@@ -167,7 +186,7 @@ func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until
 				gitlab.WithContext(ctx),
 			)
 			if err != nil {
-				resultChan <- prResult{err: fmt.Errorf("failed to get merge request details for %d: %w", mr.IID, err)}
+				resultChan <- prResult{err: translateError(fmt.Sprintf("getting merge request details for %d", mr.IID), err)}
 				return
 			}
 
@@ -185,3 +204,8 @@ func (a *Adapter) GetPullRequests(ctx context.Context, repo string, since, until
 
 	return results, int64(resp.TotalItems), nil
 }
+
+// WebHost returns the host of the GitLab instance
+func (a *Adapter) WebHost() string {
+	return a.client.BaseURL().Hostname()
+}
@@ -0,0 +1,46 @@
+package gitlab
+
+import (
+	"context"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetFileContent reads a file of the default branch
+func (a *Adapter) GetFileContent(ctx context.Context, repo, path string) ([]byte, error) {
+	content, _, err := a.client.RepositoryFiles.GetRawFile(repo, path, &gitlab.GetRawFileOptions{
+		Ref: gitlab.Ptr("HEAD"),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, translateError("getting file content", err)
+	}
+	return content, nil
+}
+
+// GetTree pages through the files of the default branch
+func (a *Adapter) GetTree(ctx context.Context, repo string) (*vcs.Tree, error) {
+	tree := &vcs.Tree{Paths: []string{}}
+	for page := 1; ; page++ {
+		nodes, resp, err := a.client.Repositories.ListTree(repo, &gitlab.ListTreeOptions{
+			ListOptions: gitlab.ListOptions{Page: page, PerPage: a.pageSize},
+			Recursive:   gitlab.Ptr(true),
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, translateError("listing tree", err)
+		}
+		for _, node := range nodes {
+			if node.Type == "blob" {
+				tree.Paths = append(tree.Paths, node.Path)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		if page == a.maxPages {
+			tree.Truncated = true
+			break
+		}
+	}
+	return tree, nil
+}
@@ -0,0 +1,56 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// translateError converts go-gitlab errors into vcs.Error values
+func translateError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// go-gitlab reports 404 as a bare sentinel rather than an ErrorResponse
+	if errors.Is(err, gitlab.ErrNotFound) {
+		return vcs.NewError(vcs.ErrNotFound, vcs.ProviderGitLab, op, err)
+	}
+
+	var respErr *gitlab.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		kind := vcs.KindForStatus(respErr.Response.StatusCode)
+		if kind == nil {
+			return err
+		}
+		vcsErr := vcs.NewError(kind, vcs.ProviderGitLab, op, err)
+		if kind == vcs.ErrRateLimited {
+			vcsErr.ResetAt = resetFromHeaders(respErr.Response.Header)
+		}
+		return vcsErr
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return vcs.NewError(vcs.ErrUpstreamUnavailable, vcs.ProviderGitLab, op, err)
+	}
+
+	return err
+}
+
+// resetFromHeaders reads Retry-After or RateLimit-Reset from a response
+func resetFromHeaders(header http.Header) time.Time {
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if epoch, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(epoch, 0)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,64 @@
+package gitlab
+
+import (
+	"context"
+	"strings"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetCommitFiles pages through the diff of a commit
+func (a *Adapter) GetCommitFiles(ctx context.Context, repo, sha string) ([]vcs.FileChange, error) {
+	files := []vcs.FileChange{}
+	for page := 1; page <= a.maxPages; page++ {
+		diffs, resp, err := a.client.Commits.GetCommitDiff(repo, sha, &gitlab.GetCommitDiffOptions{
+			ListOptions: gitlab.ListOptions{Page: page, PerPage: a.pageSize},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, translateError("getting commit diff", err)
+		}
+		for _, diff := range diffs {
+			files = append(files, mapFileChange(diff.OldPath, diff.NewPath, diff.Diff, diff.NewFile, diff.RenamedFile, diff.DeletedFile))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return files, nil
+}
+
+// GetPullRequestFiles pages through the diffs of a merge request
+func (a *Adapter) GetPullRequestFiles(ctx context.Context, repo string, number int) ([]vcs.FileChange, error) {
+	files := []vcs.FileChange{}
+	for page := 1; page <= a.maxPages; page++ {
+		diffs, resp, err := a.client.MergeRequests.ListMergeRequestDiffs(repo, number, &gitlab.ListMergeRequestDiffsOptions{
+			ListOptions: gitlab.ListOptions{Page: page, PerPage: a.pageSize},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, translateError("listing merge request diffs", err)
+		}
+		for _, diff := range diffs {
+			files = append(files, mapFileChange(diff.OldPath, diff.NewPath, diff.Diff, diff.NewFile, diff.RenamedFile, diff.DeletedFile))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return files, nil
+}
+
+// countDiffLines counts the added and deleted lines of a diff body, which
+// GitLab sends without file headers. The diff of very large files is left
+// empty, so they count as 0.
+func countDiffLines(diff string) (additions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
@@ -4,6 +4,7 @@ import (
 	"devmetrics/internal/domain/vcs"
 	"github.com/xanzy/go-gitlab"
 	"strconv"
+	"time"
 )
 
 func (a *Adapter) mapCommit(glCommit *gitlab.Commit, repoID string) vcs.Commit {
@@ -17,10 +18,10 @@ func (a *Adapter) mapCommit(glCommit *gitlab.Commit, repoID string) vcs.Commit {
 		AuthorName:   glCommit.AuthorName,
 		AuthorEmail:  glCommit.AuthorEmail,
 		CommittedAt:  glCommit.CommittedDate.UTC(),
-		ChangedFiles: glCommit.Stats.Total,
 		Additions:    glCommit.Stats.Additions,
 		Deletions:    glCommit.Stats.Deletions,
 		RepositoryID: repoID,
+		Merge:        len(glCommit.ParentIDs) > 1,
 	}
 }
 
@@ -30,16 +31,22 @@ func (a *Adapter) mapPullRequest(mr *gitlab.MergeRequest, repoID string) vcs.Pul
 	}
 
 	changesCount, _ := strconv.Atoi(mr.ChangesCount)
+	var author string
+	if mr.Author != nil {
+		author = mr.Author.Username
+	}
 
-	return vcs.PullRequest{
+	mapped := vcs.PullRequest{
 		Number:       mr.IID,
 		Title:        mr.Title,
 		State:        mr.State,
+		BaseBranch:   mr.TargetBranch,
 		CreatedAt:    mr.CreatedAt.UTC(),
 		UpdatedAt:    mr.UpdatedAt.UTC(),
 		ClosedAt:     mr.ClosedAt,
 		MergedAt:     mr.MergedAt,
-		AuthorName:   mr.Author.Username,
+		AuthorName:   author,
+		AuthorLogin:  author,
 		ReviewCount:  mr.UserNotesCount,
 		CommitCount:  mr.DivergedCommitsCount,
 		ChangedFiles: changesCount,
@@ -47,6 +54,27 @@ func (a *Adapter) mapPullRequest(mr *gitlab.MergeRequest, repoID string) vcs.Pul
 		Deletions:    0,
 		RepositoryID: repoID,
 	}
+	for _, reviewer := range mr.Reviewers {
+		mapped.RequestedReviewers = append(mapped.RequestedReviewers, vcs.Reviewer{Login: reviewer.Username})
+	}
+	return mapped
+}
+
+// mapFileChange maps a commit or merge request diff; GitLab reports no line
+// counts, so they are counted from the diff
+func mapFileChange(oldPath, newPath, diff string, added, renamed, deleted bool) vcs.FileChange {
+	change := vcs.FileChange{Path: newPath, Status: vcs.FileModified}
+	change.Additions, change.Deletions = countDiffLines(diff)
+	switch {
+	case added:
+		change.Status = vcs.FileAdded
+	case deleted:
+		change.Status = vcs.FileRemoved
+	case renamed:
+		change.Status = vcs.FileRenamed
+		change.PreviousPath = oldPath
+	}
+	return change
 }
 
 func (a *Adapter) mapRepository(project *gitlab.Project) *vcs.Repository {
@@ -55,14 +83,27 @@ func (a *Adapter) mapRepository(project *gitlab.Project) *vcs.Repository {
 	}
 
 	return &vcs.Repository{
-		ID:            strconv.Itoa(project.ID),
-		Name:          project.Name,
-		FullName:      project.NameWithNamespace,
-		DefaultBranch: project.DefaultBranch,
-		CreatedAt:     project.CreatedAt.UTC(),
-		UpdatedAt:     project.LastActivityAt.UTC(),
-		Description:   project.Description,
-		Language:      "", // GitLab API doesn't provide primary language info
-		Private:       project.Visibility != "public",
+		ID:             strconv.Itoa(project.ID),
+		Name:           project.Name,
+		FullName:       project.NameWithNamespace,
+		DefaultBranch:  project.DefaultBranch,
+		CreatedAt:      timeValue(project.CreatedAt),
+		UpdatedAt:      timeValue(project.LastActivityAt),
+		Description:    project.Description,
+		Language:       "", // GitLab API doesn't provide primary language info
+		Private:        project.Visibility != "public",
+		Path:           project.PathWithNamespace,
+		Visibility:     string(project.Visibility),
+		Archived:       project.Archived,
+		Fork:           project.ForkedFromProject != nil,
+		Topics:         project.Topics,
+		LastActivityAt: timeValue(project.LastActivityAt),
+	}
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
 	}
+	return t.UTC()
 }
@@ -0,0 +1,95 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// ListRepositories lists the projects of a group and all its subgroups,
+// falling back to a user's projects when no such group exists. The owner is
+// a numeric ID or a full path such as "group/subgroup". Results are ordered
+// by last activity, so paging stops once projects fall outside filter.ActiveSince.
+func (a *Adapter) ListRepositories(ctx context.Context, owner string, filter vcs.RepositoryFilter) ([]vcs.Repository, error) {
+	results, err := a.listRepositories(filter, func(opts gitlab.ListOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+		return a.client.Groups.ListGroupProjects(owner, &gitlab.ListGroupProjectsOptions{
+			ListOptions:      opts,
+			IncludeSubGroups: gitlab.Ptr(true),
+			Archived:         archivedOption(filter),
+			Visibility:       visibilityOption(filter),
+			Topic:            topicOption(filter),
+			OrderBy:          gitlab.Ptr("last_activity_at"),
+			Sort:             gitlab.Ptr("desc"),
+		}, gitlab.WithContext(ctx))
+	})
+	if errors.Is(err, vcs.ErrNotFound) {
+		results, err = a.listRepositories(filter, func(opts gitlab.ListOptions) ([]*gitlab.Project, *gitlab.Response, error) {
+			return a.client.Projects.ListUserProjects(owner, &gitlab.ListProjectsOptions{
+				ListOptions: opts,
+				Archived:    archivedOption(filter),
+				Visibility:  visibilityOption(filter),
+				Topic:       topicOption(filter),
+				OrderBy:     gitlab.Ptr("last_activity_at"),
+				Sort:        gitlab.Ptr("desc"),
+			}, gitlab.WithContext(ctx))
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (a *Adapter) listRepositories(
+	filter vcs.RepositoryFilter,
+	fetch func(opts gitlab.ListOptions) ([]*gitlab.Project, *gitlab.Response, error),
+) ([]vcs.Repository, error) {
+	results := []vcs.Repository{}
+	for page := 1; page <= a.maxPages; page++ {
+		projects, resp, err := fetch(gitlab.ListOptions{Page: page, PerPage: a.pageSize})
+		if err != nil {
+			return nil, translateError("listing projects", err)
+		}
+
+		for _, project := range projects {
+			mapped := a.mapRepository(project)
+			if !filter.ActiveSince.IsZero() && mapped.LastActivityAt.Before(filter.ActiveSince) {
+				return results, nil
+			}
+			if filter.Matches(*mapped) {
+				results = append(results, *mapped)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return results, nil
+}
+
+// archivedOption only asks GitLab to drop archived projects; including them
+// means not filtering at all
+func archivedOption(filter vcs.RepositoryFilter) *bool {
+	if filter.IncludeArchived {
+		return nil
+	}
+	return gitlab.Ptr(false)
+}
+
+func visibilityOption(filter vcs.RepositoryFilter) *gitlab.VisibilityValue {
+	if filter.Visibility == "" {
+		return nil
+	}
+	return gitlab.Ptr(gitlab.VisibilityValue(filter.Visibility))
+}
+
+// topicOption narrows by the first topic server-side; Matches checks the rest
+func topicOption(filter vcs.RepositoryFilter) *string {
+	if len(filter.Topics) == 0 {
+		return nil
+	}
+	return gitlab.Ptr(filter.Topics[0])
+}
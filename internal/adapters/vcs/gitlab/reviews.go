@@ -0,0 +1,47 @@
+package gitlab
+
+import (
+	"context"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetPullRequestReviews lists the approvals of a merge request and the
+// reviewers who reviewed it without approving. GitLab doesn't report when
+// either happened.
+func (a *Adapter) GetPullRequestReviews(ctx context.Context, repo string, number int) ([]vcs.Review, error) {
+	approvals, _, err := a.client.MergeRequestApprovals.GetConfiguration(repo, number, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, translateError("getting merge request approvals", err)
+	}
+	reviewers, _, err := a.client.MergeRequests.GetMergeRequestReviewers(repo, number, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, translateError("getting merge request reviewers", err)
+	}
+
+	reviews := []vcs.Review{}
+	approved := make(map[string]bool)
+	for _, approver := range approvals.ApprovedBy {
+		if approver.User != nil {
+			approved[approver.User.Username] = true
+			reviews = append(reviews, vcs.Review{Reviewer: vcs.Reviewer{Login: approver.User.Username}, State: vcs.ReviewApproved})
+		}
+	}
+	for _, reviewer := range reviewers {
+		if reviewer.User == nil || approved[reviewer.User.Username] {
+			continue
+		}
+		var state vcs.ReviewState
+		switch reviewer.State {
+		case "reviewed":
+			state = vcs.ReviewCommented
+		case "requested_changes":
+			state = vcs.ReviewChangesRequested
+		default:
+			continue
+		}
+		reviews = append(reviews, vcs.Review{Reviewer: vcs.Reviewer{Login: reviewer.User.Username}, State: state})
+	}
+	return reviews, nil
+}
@@ -0,0 +1,50 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// GetUser finds a user by username. Only the public e-mail is visible to
+// non-admin tokens.
+func (a *Adapter) GetUser(ctx context.Context, login string) (*vcs.User, error) {
+	users, _, err := a.client.Users.ListUsers(&gitlab.ListUsersOptions{
+		Username: gitlab.Ptr(login),
+	}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, translateError("getting user", err)
+	}
+	if len(users) == 0 {
+		return nil, vcs.NewError(vcs.ErrNotFound, vcs.ProviderGitLab, "getting user", fmt.Errorf("no user %q", login))
+	}
+
+	return &vcs.User{
+		Login: users[0].Username,
+		Name:  users[0].Name,
+		Email: users[0].PublicEmail,
+	}, nil
+}
+
+// ListGroupMembers lists the members of a group, including those inherited
+// from parent groups. The group is a numeric ID or a full path.
+func (a *Adapter) ListGroupMembers(ctx context.Context, group string) ([]vcs.User, error) {
+	members := []vcs.User{}
+	for page := 1; page <= a.maxPages; page++ {
+		users, resp, err := a.client.Groups.ListAllGroupMembers(group, &gitlab.ListGroupMembersOptions{
+			ListOptions: gitlab.ListOptions{Page: page, PerPage: a.pageSize},
+		}, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, translateError("listing group members", err)
+		}
+		for _, user := range users {
+			members = append(members, vcs.User{Login: user.Username, Name: user.Name, Email: user.Email})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+	}
+	return members, nil
+}
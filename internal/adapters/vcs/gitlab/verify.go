@@ -0,0 +1,49 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Verify fetches the token's user and, where the instance supports it, the
+// token's scopes, which must include api or read_api
+func (a *Adapter) Verify(ctx context.Context) (*vcs.Verification, error) {
+	user, resp, err := a.client.Users.CurrentUser(gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, translateError("verifying credentials", err)
+	}
+
+	verification := &vcs.Verification{
+		Identity:  user.Username,
+		RateLimit: rateLimitFromHeaders(resp.Header),
+	}
+
+	// Older instances lack /personal_access_tokens/self; scopes stay unknown there
+	token, _, err := a.client.PersonalAccessTokens.GetSinglePersonalAccessToken(gitlab.WithContext(ctx))
+	if err == nil {
+		verification.Scopes = token.Scopes
+		verification.MissingScopes = vcs.MissingScopes(token.Scopes, "api", "read_api")
+	}
+	return verification, nil
+}
+
+func rateLimitFromHeaders(header http.Header) *vcs.RateLimit {
+	limit, err := strconv.Atoi(header.Get("RateLimit-Limit"))
+	if err != nil {
+		return nil
+	}
+	remaining, _ := strconv.Atoi(header.Get("RateLimit-Remaining"))
+	rateLimit := &vcs.RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+	}
+	if reset, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.ResetAt = time.Unix(reset, 0)
+	}
+	return rateLimit
+}
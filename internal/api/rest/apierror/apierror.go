@@ -0,0 +1,99 @@
+package apierror
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Stable error codes returned in API error bodies
+const (
+	CodeNotFound              = "not_found"
+	CodeUnauthorized          = "unauthorized"
+	CodeForbidden             = "forbidden"
+	CodeRateLimited           = "rate_limited"
+	CodeProviderNotConfigured = "provider_not_configured"
+	CodeUpstreamUnavailable   = "upstream_unavailable"
+	CodeInvalidInput          = "invalid_input"
+	CodeInternal              = "internal_error"
+)
+
+// ErrResponded is returned by handlers that already wrote an error response,
+// so that processing stops without the response being replaced
+var ErrResponded = errors.New("error response already sent")
+
+// Mapping is the HTTP representation of an error
+type Mapping struct {
+	Status  int
+	Code    string
+	Message string
+	// RetryAfter is when the client may retry; zero when not applicable
+	RetryAfter time.Time
+}
+
+// Resolve maps domain and Fiber errors to a status code and stable error code
+func Resolve(err error) Mapping {
+	switch {
+	case errors.Is(err, vcs.ErrNotFound):
+		return Mapping{Status: fiber.StatusNotFound, Code: CodeNotFound, Message: "Resource not found"}
+	case errors.Is(err, vcs.ErrUnauthorized):
+		return Mapping{Status: fiber.StatusUnauthorized, Code: CodeUnauthorized, Message: "Upstream provider rejected the credentials"}
+	case errors.Is(err, vcs.ErrForbidden):
+		return Mapping{Status: fiber.StatusForbidden, Code: CodeForbidden, Message: "Access to the resource is forbidden"}
+	case errors.Is(err, vcs.ErrRateLimited):
+		m := Mapping{Status: fiber.StatusTooManyRequests, Code: CodeRateLimited, Message: "Upstream rate limit exceeded"}
+		if resetAt, ok := vcs.RateLimitReset(err); ok {
+			m.RetryAfter = resetAt
+		}
+		return m
+	case errors.Is(err, vcs.ErrProviderNotConfigured):
+		return Mapping{Status: fiber.StatusNotImplemented, Code: CodeProviderNotConfigured, Message: "VCS provider is not configured"}
+	case errors.Is(err, vcs.ErrUpstreamUnavailable):
+		return Mapping{Status: fiber.StatusBadGateway, Code: CodeUpstreamUnavailable, Message: "Upstream provider is unavailable"}
+	case errors.Is(err, vcs.ErrInvalidInput):
+		return Mapping{Status: fiber.StatusBadRequest, Code: CodeInvalidInput, Message: "Invalid input"}
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return Mapping{Status: fiberErr.Code, Code: codeForStatus(fiberErr.Code), Message: fiberErr.Message}
+	}
+
+	return Mapping{Status: fiber.StatusInternalServerError, Code: CodeInternal, Message: "Internal server error"}
+}
+
+// SetRetryAfter writes the Retry-After header in seconds when the mapping has one
+func SetRetryAfter(c *fiber.Ctx, m Mapping) {
+	if m.RetryAfter.IsZero() {
+		return
+	}
+	seconds := int(time.Until(m.RetryAfter).Seconds()) + 1
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case fiber.StatusBadRequest:
+		return CodeInvalidInput
+	case fiber.StatusUnauthorized:
+		return CodeUnauthorized
+	case fiber.StatusForbidden:
+		return CodeForbidden
+	case fiber.StatusNotFound:
+		return CodeNotFound
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusTooManyRequests:
+		return CodeRateLimited
+	}
+	if status >= fiber.StatusInternalServerError {
+		return CodeInternal
+	}
+	return "http_error"
+}
@@ -0,0 +1,64 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", vcs.NewError(vcs.ErrNotFound, vcs.ProviderGitHub, "getting repository", nil), fiber.StatusNotFound, CodeNotFound},
+		{"wrapped not found", fmt.Errorf("failed to get commits: %w", vcs.NewError(vcs.ErrNotFound, vcs.ProviderGitLab, "", nil)), fiber.StatusNotFound, CodeNotFound},
+		{"unauthorized", vcs.ErrUnauthorized, fiber.StatusUnauthorized, CodeUnauthorized},
+		{"forbidden", vcs.ErrForbidden, fiber.StatusForbidden, CodeForbidden},
+		{"rate limited", vcs.ErrRateLimited, fiber.StatusTooManyRequests, CodeRateLimited},
+		{"provider not configured", vcs.ErrProviderNotConfigured, fiber.StatusNotImplemented, CodeProviderNotConfigured},
+		{"upstream unavailable", vcs.ErrUpstreamUnavailable, fiber.StatusBadGateway, CodeUpstreamUnavailable},
+		{"invalid input", vcs.ErrInvalidInput, fiber.StatusBadRequest, CodeInvalidInput},
+		{"fiber bad request", fiber.NewError(fiber.StatusBadRequest, "bad"), fiber.StatusBadRequest, CodeInvalidInput},
+		{"fiber method not allowed", fiber.ErrMethodNotAllowed, fiber.StatusMethodNotAllowed, "method_not_allowed"},
+		{"fiber conflict", fiber.ErrConflict, fiber.StatusConflict, "http_error"},
+		{"fiber service unavailable", fiber.ErrServiceUnavailable, fiber.StatusServiceUnavailable, CodeInternal},
+		{"unknown", errors.New("boom"), fiber.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Resolve(tt.err)
+			if m.Status != tt.status || m.Code != tt.code {
+				t.Errorf("Resolve = %d %s, want %d %s", m.Status, m.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestResolveKeepsFiberMessage(t *testing.T) {
+	if m := Resolve(fiber.NewError(fiber.StatusNotFound, "Unknown tenant: acme")); m.Message != "Unknown tenant: acme" {
+		t.Errorf("Message = %q, want the fiber error's", m.Message)
+	}
+	if m := Resolve(errors.New("dial tcp 10.0.0.1:443: connection refused")); m.Message != "Internal server error" {
+		t.Errorf("Message = %q, internal errors must not leak", m.Message)
+	}
+}
+
+func TestResolveRetryAfter(t *testing.T) {
+	resetAt := time.Now().Add(time.Minute)
+	limited := vcs.NewError(vcs.ErrRateLimited, vcs.ProviderGitHub, "listing commits", nil)
+	limited.ResetAt = resetAt
+
+	if m := Resolve(limited); !m.RetryAfter.Equal(resetAt) {
+		t.Errorf("RetryAfter = %v, want %v", m.RetryAfter, resetAt)
+	}
+	if m := Resolve(vcs.ErrRateLimited); !m.RetryAfter.IsZero() {
+		t.Errorf("RetryAfter = %v, want zero without a reset time", m.RetryAfter)
+	}
+}
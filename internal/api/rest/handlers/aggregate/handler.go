@@ -0,0 +1,264 @@
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/api/rest/middleware"
+	"devmetrics/internal/domain/auth"
+	teamdomain "devmetrics/internal/domain/team"
+	domain "devmetrics/internal/domain/vcs"
+	"devmetrics/internal/services/metrics"
+	"devmetrics/internal/services/team"
+	service "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves activity merged across many repositories
+type Handler struct {
+	Aggregator  *service.Aggregator
+	Teams       *team.Service
+	Sizes       *metrics.SizeClassifier
+	Knowledge   *metrics.KnowledgeAnalyzer
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(aggregator *service.Aggregator, teams *team.Service, sizes *metrics.SizeClassifier, knowledge *metrics.KnowledgeAnalyzer, log logger.Logger) *Handler {
+	return &Handler{
+		Aggregator:  aggregator,
+		Teams:       teams,
+		Sizes:       sizes,
+		Knowledge:   knowledge,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+// GetActivity returns aggregate totals and a per-repository breakdown
+func (h *Handler) GetActivity(c *fiber.Ctx) error {
+	req := new(RepositorySetRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	activity, err := h.activity(c, req, service.ActivityQuery{})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	response := newActivityResponse(activity)
+	response.Team, response.Attribution = req.Team, req.attribution()
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetCommits returns one page of the commits of all selected repositories, newest first
+func (h *Handler) GetCommits(c *fiber.Ctx) error {
+	req := new(CommitsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	activity, err := h.activity(c, &req.RepositorySetRequest, service.ActivityQuery{})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	commits := page(activity.Commits, req.GetOffset(), req.GetPerPage())
+	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), int64(len(activity.Commits)))
+	return h.BaseHandler.SendPaginatedResponse(c, ItemsResponse{
+		Items:    commits,
+		Partial:  activity.Partial(),
+		Failures: newFailuresResponse(activity.Failures),
+	}, pagination)
+}
+
+// GetPullRequests returns one page of the pull requests of all selected
+// repositories, newest first
+func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
+	req := new(PullRequestsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	activity, err := h.activity(c, &req.RepositorySetRequest, service.ActivityQuery{})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	var prs []domain.PullRequest
+	for _, pr := range activity.PullRequests {
+		if matchesState(pr, req.State) {
+			prs = append(prs, pr)
+		}
+	}
+
+	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), int64(len(prs)))
+	return h.BaseHandler.SendPaginatedResponse(c, ItemsResponse{
+		Items:    page(prs, req.GetOffset(), req.GetPerPage()),
+		Partial:  activity.Partial(),
+		Failures: newFailuresResponse(activity.Failures),
+	}, pagination)
+}
+
+// activity resolves the repository set and aggregates it; the request sets
+// the time range and author filter of the query. A nil activity with a nil
+// error means an error response was already sent.
+func (h *Handler) activity(c *fiber.Ctx, req *RepositorySetRequest, query service.ActivityQuery) (*service.Activity, error) {
+	repos, filter, err := h.selection(c, req)
+	if err != nil {
+		return nil, h.BaseHandler.HandleError(c, err)
+	}
+
+	query.Since, query.Until, query.Filter = req.GetSinceTime(), req.GetUntilTime(), filter
+	activity, err := h.Aggregator.Activity(c.UserContext(), repos, query)
+	if err != nil {
+		return nil, h.BaseHandler.HandleError(c, err)
+	}
+	return activity, nil
+}
+
+// selection resolves the repositories to aggregate and the authors whose
+// work counts. Explicitly named repositories the caller may not access are
+// rejected; those of an owner or team are left out.
+func (h *Handler) selection(c *fiber.Ctx, req *RepositorySetRequest) ([]service.RepositoryRef, domain.AuthorFilter, error) {
+	filter := req.AuthorFilter()
+	if req.Repositories != "" && req.Owner != "" {
+		return nil, filter, fiber.NewError(fiber.StatusBadRequest, "Only one of repos or owner may be set")
+	}
+	if req.Repositories == "" && req.Owner == "" && req.Team == "" {
+		return nil, filter, fiber.NewError(fiber.StatusBadRequest, "One of repos, owner or team is required")
+	}
+	if req.Team == "" {
+		if req.Attribution != "" {
+			return nil, filter, fiber.NewError(fiber.StatusBadRequest, "attribution requires team")
+		}
+		repos, err := h.repositories(c, req, nil)
+		return repos, filter, err
+	}
+
+	t, err := h.Teams.Get(c.UserContext(), req.Team)
+	if errors.Is(err, teamdomain.ErrNotFound) {
+		return nil, filter, fiber.NewError(fiber.StatusNotFound, teamdomain.ErrNotFound.Error())
+	}
+	if err != nil {
+		return nil, filter, err
+	}
+
+	owned := req.Repositories == "" && req.Owner == ""
+	attribution := req.attribution()
+	if attribution == attributionRepositories && !owned {
+		return nil, filter, fiber.NewError(fiber.StatusBadRequest, "attribution=repositories can't be combined with repos or owner")
+	}
+	if attribution == attributionMembers {
+		// A team without members matches nobody rather than everybody
+		filter.Members = append([]string{}, t.Members...)
+	}
+
+	repos, err := h.repositories(c, req, t)
+	return repos, filter, err
+}
+
+// repositories resolves the selected repository set: the named
+// repositories, else those of an owner, else those the team owns
+func (h *Handler) repositories(c *fiber.Ctx, req *RepositorySetRequest, t *teamdomain.Team) ([]service.RepositoryRef, error) {
+	allowed := middleware.RepositoryAccess(c)
+	ctx := c.UserContext()
+
+	switch {
+	case req.Repositories != "":
+		refs, err := parseRepositories(req.Repositories)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		for _, ref := range refs {
+			if !allowed(resource(ref)) {
+				return nil, fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("Access to repository %s is not permitted", ref))
+			}
+		}
+		return refs, nil
+
+	case req.Owner != "":
+		if req.Provider == "" {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "provider is required with owner")
+		}
+		providerType := domain.ProviderType(req.Provider)
+		if !allowed(auth.Resource{Provider: req.Provider, Owner: req.Owner}) {
+			return nil, fiber.NewError(fiber.StatusForbidden, "Access to this owner is not permitted")
+		}
+		filter := req.Filter()
+		filter.Allow = func(repo domain.Repository) bool {
+			return allowed(resource(service.RepositoryRef{Provider: providerType, Name: repo.Path}))
+		}
+		return h.Aggregator.OwnerRepositories(ctx, providerType, req.Owner, filter)
+
+	default:
+		var refs []service.RepositoryRef
+		for _, repo := range t.Repositories {
+			ref := service.RepositoryRef{Provider: repo.Provider, Name: repo.Name}
+			if allowed(resource(ref)) {
+				refs = append(refs, ref)
+			}
+		}
+		return refs, nil
+	}
+}
+
+// parseRepositories parses a comma-separated list of provider:name
+func parseRepositories(value string) ([]service.RepositoryRef, error) {
+	var refs []service.RepositoryRef
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		provider, name, ok := strings.Cut(item, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("repository %q must be written as provider:name", item)
+		}
+		providerType := domain.ProviderType(provider)
+		if providerType != domain.ProviderGitHub && providerType != domain.ProviderGitLab {
+			return nil, fmt.Errorf("repository %q: unsupported provider %q", item, provider)
+		}
+		refs = append(refs, service.RepositoryRef{Provider: providerType, Name: name})
+	}
+	if len(refs) == 0 {
+		return nil, errors.New("repos must name at least one repository")
+	}
+	return refs, nil
+}
+
+// resource is the RBAC resource of a repository; its owner is everything
+// before the last path segment, which covers nested GitLab groups
+func resource(ref service.RepositoryRef) auth.Resource {
+	return auth.Resource{
+		Provider: string(ref.Provider),
+		Owner:    path.Dir(ref.Name),
+		Repo:     ref.Name,
+	}
+}
+
+func matchesState(pr domain.PullRequest, state string) bool {
+	switch state {
+	case "merged":
+		return pr.MergedAt != nil
+	case "closed":
+		return pr.ClosedAt != nil && pr.MergedAt == nil
+	case "open":
+		return pr.ClosedAt == nil && pr.MergedAt == nil
+	}
+	return true
+}
+
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
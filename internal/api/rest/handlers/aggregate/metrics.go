@@ -0,0 +1,350 @@
+package aggregate
+
+import (
+	"fmt"
+
+	"devmetrics/internal/api/rest/middleware"
+	teamdomain "devmetrics/internal/domain/team"
+	domain "devmetrics/internal/domain/vcs"
+	"devmetrics/internal/services/metrics"
+	service "devmetrics/internal/services/vcs"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPullRequestMetrics returns throughput and cycle time of the pull
+// requests created in the time range, optionally broken down by group
+func (h *Handler) GetPullRequestMetrics(c *fiber.Ctx) error {
+	req := new(PullRequestMetricsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	groupBy, err := h.pullRequestGroups(c, req.GroupBy)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	activity, err := h.activity(c, &req.RepositorySetRequest, service.ActivityQuery{})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	report, err := metrics.PullRequests(activity.PullRequests, metrics.PullRequestQuery{
+		Since:    activity.Since,
+		Until:    activity.Until,
+		Interval: req.interval(),
+		GroupBy:  groupBy,
+	})
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := newPullRequestMetricsResponse(report)
+	response.GroupBy = req.GroupBy
+	response.Team, response.Attribution = req.Team, req.attribution()
+	response.Partial, response.Failures = activity.Partial(), newFailuresResponse(activity.Failures)
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// pullRequestGroups returns the grouping function for group_by, or nil
+func (h *Handler) pullRequestGroups(c *fiber.Ctx, groupBy string) (func(domain.PullRequest) []metrics.GroupKey, error) {
+	switch groupBy {
+	case groupByRepository:
+		return func(pr domain.PullRequest) []metrics.GroupKey {
+			return []metrics.GroupKey{{Key: pr.RepositoryID, Name: pr.RepositoryID}}
+		}, nil
+
+	case groupByAuthor:
+		return func(pr domain.PullRequest) []metrics.GroupKey {
+			if pr.Contributor != nil {
+				return []metrics.GroupKey{{Key: pr.Contributor.ID, Name: pr.Contributor.Name}}
+			}
+			return []metrics.GroupKey{{Key: pr.AuthorLogin, Name: pr.AuthorName}}
+		}, nil
+
+	case groupByTeam:
+		// Authors outside every team are left out of the breakdown
+		memberships, err := h.Teams.Memberships(c.UserContext())
+		if err != nil {
+			return nil, err
+		}
+		return func(pr domain.PullRequest) []metrics.GroupKey {
+			if pr.Contributor == nil {
+				return nil
+			}
+			var keys []metrics.GroupKey
+			for _, t := range memberships[pr.Contributor.ID] {
+				keys = append(keys, metrics.GroupKey{Key: t.ID, Name: t.Name})
+			}
+			return keys
+		}, nil
+
+	case groupByBranch:
+		return func(pr domain.PullRequest) []metrics.GroupKey {
+			return []metrics.GroupKey{{Key: pr.BaseBranch, Name: pr.BaseBranch}}
+		}, nil
+	}
+	return nil, nil
+}
+
+// GetPullRequestSizes classifies the pull requests created in the time
+// range by size and lists the largest of those still open. With files set,
+// lines of generated files and lockfiles don't count; with reviews set, size
+// is related to the time to first review.
+func (h *Handler) GetPullRequestSizes(c *fiber.Ctx) error {
+	req := new(PullRequestSizesRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	activity, err := h.activity(c, &req.RepositorySetRequest, service.ActivityQuery{PullRequestFiles: req.Files, PullRequestReviews: req.Reviews})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	response := newPullRequestSizesResponse(h.Sizes.PullRequestSizes(activity.PullRequests, req.largest()))
+	if req.Files {
+		response.Excluded = h.Sizes.Excluded()
+	}
+	response.Since, response.Until = activity.Since, activity.Until
+	response.Team, response.Attribution = req.Team, req.attribution()
+	response.Partial, response.Failures = activity.Partial(), newFailuresResponse(activity.Failures)
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetHotspots ranks the files and directories of one repository by how
+// often and how heavily its commits changed them
+func (h *Handler) GetHotspots(c *fiber.Ctx) error {
+	req := new(HotspotsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	ref, err := h.repository(c, req.Repository)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	query, err := req.query()
+	if err != nil {
+		return h.BaseHandler.HandleError(c, fiber.NewError(fiber.StatusBadRequest, err.Error()))
+	}
+
+	activity, err := h.Aggregator.Activity(c.UserContext(), []service.RepositoryRef{ref}, service.ActivityQuery{
+		Since:            req.GetSinceTime(),
+		Until:            req.GetUntilTime(),
+		Filter:           req.AuthorFilter(),
+		CommitFiles:      true,
+		SkipPullRequests: true,
+	})
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := newHotspotsResponse(metrics.Hotspots(activity.Commits, query))
+	response.Repository = ref.String()
+	response.Since, response.Until = activity.Since, activity.Until
+	response.ReworkDays = req.reworkDays()
+	response.Truncated = activity.Repositories[0].Truncated
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetBusFactor reports how the knowledge of one repository is spread across
+// its authors: ownership shares, the bus factor of every path and the files
+// whose main author is no longer active
+func (h *Handler) GetBusFactor(c *fiber.Ctx) error {
+	req := new(BusFactorRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	ref, err := h.repository(c, req.Repository)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	until := req.GetUntilTime()
+	query, err := req.query(h.Knowledge.Defaults(), until)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, fiber.NewError(fiber.StatusBadRequest, err.Error()))
+	}
+
+	activity, err := h.Aggregator.Activity(c.UserContext(), []service.RepositoryRef{ref}, service.ActivityQuery{
+		Since:            req.since(until),
+		Until:            until,
+		Filter:           req.AuthorFilter(),
+		CommitFiles:      true,
+		SkipPullRequests: true,
+	})
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := newBusFactorResponse(h.Knowledge.Knowledge(activity.Commits, query), query)
+	response.Repository = ref.String()
+	response.Since, response.Until = activity.Since, activity.Until
+	response.Truncated = activity.Repositories[0].Truncated
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetCodeOwnership reports how CODEOWNERS covers every selected repository,
+// which paths have no owner and whether the pull requests created in the
+// time range were reviewed by the owners of the files they changed
+func (h *Handler) GetCodeOwnership(c *fiber.Ctx) error {
+	req := new(CodeOwnershipRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	repos, filter, err := h.selection(c, &req.RepositorySetRequest)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	ownership, failures, err := h.Aggregator.Ownership(c.UserContext(), repos)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	owned := make([]service.RepositoryRef, 0, len(ownership))
+	for _, repo := range ownership {
+		owned = append(owned, repo.Repository)
+	}
+	activity, err := h.Aggregator.Activity(c.UserContext(), owned, service.ActivityQuery{
+		Since:              req.GetSinceTime(),
+		Until:              req.GetUntilTime(),
+		Filter:             filter,
+		PullRequestFiles:   true,
+		PullRequestReviews: true,
+		SkipCommits:        true,
+	})
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	failures = append(failures, activity.Failures...)
+
+	response := CodeOwnershipResponse{
+		Since:        activity.Since,
+		Until:        activity.Until,
+		Team:         req.Team,
+		Attribution:  req.attribution(),
+		Repositories: make([]RepositoryOwnershipResponse, 0, len(activity.Repositories)),
+	}
+	for _, repo := range ownership {
+		queried := false
+		for _, repoActivity := range activity.Repositories {
+			queried = queried || repoActivity.Repository == repo.Repository
+		}
+		if !queried {
+			continue
+		}
+
+		source := metrics.OwnershipSource{Paths: repo.Tree.Paths, IsOwner: repo.IsOwner, Limit: req.limit()}
+		if repo.CodeOwners != nil {
+			source.CodeOwners = repo.CodeOwners.File
+		}
+		// Pull requests carry the repository name only
+		for _, pr := range activity.PullRequests {
+			if pr.RepositoryID == repo.Repository.Name {
+				source.PullRequests = append(source.PullRequests, pr)
+			}
+		}
+		response.Repositories = append(response.Repositories, newRepositoryOwnershipResponse(repo, metrics.Ownership(source)))
+	}
+	response.Totals = newCodeOwnershipTotals(response.Repositories)
+	response.Partial, response.Failures = len(failures) > 0, newFailuresResponse(failures)
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetReviewMetrics reports who carries the review load of the pull requests
+// created in the time range. Review concentration is measured for the
+// selected team, else for every team of the tenant.
+func (h *Handler) GetReviewMetrics(c *fiber.Ctx) error {
+	req := new(ReviewMetricsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	activity, err := h.activity(c, &req.RepositorySetRequest, service.ActivityQuery{PullRequestReviews: true, SkipCommits: true})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	teams, err := h.reviewTeams(c, req.Team)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := newReviewMetricsResponse(metrics.Reviews(activity.PullRequests, metrics.ReviewQuery{
+		IncludeBots: req.IncludeBots,
+		Teams:       teams,
+		Limit:       req.limit(),
+	}))
+	response.Since, response.Until = activity.Since, activity.Until
+	response.Team, response.Attribution = req.Team, req.attribution()
+	response.Partial, response.Failures = activity.Partial(), newFailuresResponse(activity.Failures)
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetCommitMetrics classifies the commits of the time range by their
+// messages and credits them to their authors and co-authors
+func (h *Handler) GetCommitMetrics(c *fiber.Ctx) error {
+	req := new(CommitMetricsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	activity, err := h.activity(c, &req.RepositorySetRequest, service.ActivityQuery{SkipPullRequests: true})
+	if err != nil || activity == nil {
+		return err
+	}
+
+	response := newCommitMetricsResponse(metrics.Commits(activity.Commits, metrics.CommitQuery{Limit: req.limit()}))
+	response.Since, response.Until = activity.Since, activity.Until
+	response.Team, response.Attribution = req.Team, req.attribution()
+	response.Partial, response.Failures = activity.Partial(), newFailuresResponse(activity.Failures)
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// reviewTeams resolves the members of the named team, or of every team, to people
+func (h *Handler) reviewTeams(c *fiber.Ctx, id string) ([]metrics.TeamMembers, error) {
+	var teams []teamdomain.Team
+	if id != "" {
+		t, err := h.Teams.Get(c.UserContext(), id)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, *t)
+	} else {
+		var err error
+		if teams, err = h.Teams.List(c.UserContext()); err != nil {
+			return nil, err
+		}
+	}
+
+	members := make([]metrics.TeamMembers, 0, len(teams))
+	for i := range teams {
+		people, err := h.Teams.People(c.UserContext(), &teams[i])
+		if err != nil {
+			return nil, err
+		}
+		t := metrics.TeamMembers{ID: teams[i].ID, Name: teams[i].Name}
+		for _, person := range people {
+			t.Members = append(t.Members, person.ID)
+		}
+		members = append(members, t)
+	}
+	return members, nil
+}
+
+// repository parses a single provider:name repository the caller may access
+func (h *Handler) repository(c *fiber.Ctx, value string) (service.RepositoryRef, error) {
+	refs, err := parseRepositories(value)
+	if err != nil {
+		return service.RepositoryRef{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if len(refs) != 1 {
+		return service.RepositoryRef{}, fiber.NewError(fiber.StatusBadRequest, "repo must name exactly one repository")
+	}
+	if !middleware.RepositoryAccess(c)(resource(refs[0])) {
+		return service.RepositoryRef{}, fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("Access to repository %s is not permitted", refs[0]))
+	}
+	return refs[0], nil
+}
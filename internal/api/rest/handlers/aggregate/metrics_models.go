@@ -0,0 +1,1007 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/services/metrics"
+	service "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/glob"
+)
+
+const (
+	groupByRepository = "repo"
+	groupByAuthor     = "author"
+	groupByTeam       = "team"
+	groupByBranch     = "branch"
+)
+
+type PullRequestMetricsRequest struct {
+	RepositorySetRequest
+	Interval string `query:"interval" validate:"omitempty,oneof=day week month"`
+	GroupBy  string `query:"group_by" validate:"omitempty,oneof=repo author team branch"`
+}
+
+func (r *PullRequestMetricsRequest) interval() metrics.Interval {
+	if r.Interval == "" {
+		return metrics.IntervalWeek
+	}
+	return metrics.Interval(r.Interval)
+}
+
+type PullRequestMetricsResponse struct {
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	Interval    string    `json:"interval"`
+	GroupBy     string    `json:"group_by,omitempty"`
+	Team        string    `json:"team,omitempty"`
+	Attribution string    `json:"attribution,omitempty"`
+	Partial     bool      `json:"partial"`
+	PullRequestSeriesResponse
+	Groups   []PullRequestGroupResponse `json:"groups,omitempty"`
+	Failures []FailureResponse          `json:"failures"`
+}
+
+type PullRequestSeriesResponse struct {
+	Summary    PullRequestStatsResponse    `json:"summary"`
+	Histogram  []HistogramBinResponse      `json:"time_to_merge_histogram"`
+	TimeSeries []PullRequestBucketResponse `json:"time_series"`
+}
+
+type PullRequestGroupResponse struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	PullRequestSeriesResponse
+}
+
+type PullRequestStatsResponse struct {
+	Opened         int                   `json:"opened"`
+	Merged         int                   `json:"merged"`
+	Closed         int                   `json:"closed_unmerged"`
+	OpenCloseRatio float64               `json:"open_close_ratio"`
+	AbandonedRate  float64               `json:"abandoned_rate"`
+	TimeToMerge    DurationStatsResponse `json:"time_to_merge"`
+}
+
+type PullRequestBucketResponse struct {
+	Start time.Time `json:"start"`
+	PullRequestStatsResponse
+}
+
+// DurationStatsResponse reports durations in hours
+type DurationStatsResponse struct {
+	Count       int     `json:"count"`
+	MedianHours float64 `json:"median_hours"`
+	P75Hours    float64 `json:"p75_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+}
+
+// HistogramBinResponse counts durations in [min_hours, max_hours); the last bin has no max_hours
+type HistogramBinResponse struct {
+	Label    string   `json:"label"`
+	MinHours float64  `json:"min_hours"`
+	MaxHours *float64 `json:"max_hours,omitempty"`
+	Count    int      `json:"count"`
+}
+
+func newPullRequestMetricsResponse(r *metrics.PullRequestReport) PullRequestMetricsResponse {
+	response := PullRequestMetricsResponse{
+		Since:                     r.Since,
+		Until:                     r.Until,
+		Interval:                  string(r.Interval),
+		PullRequestSeriesResponse: newPullRequestSeriesResponse(r.PullRequestSeries),
+	}
+	for _, group := range r.Groups {
+		response.Groups = append(response.Groups, PullRequestGroupResponse{
+			Key:                       group.Key,
+			Name:                      group.Name,
+			PullRequestSeriesResponse: newPullRequestSeriesResponse(group.PullRequestSeries),
+		})
+	}
+	return response
+}
+
+func newPullRequestSeriesResponse(s metrics.PullRequestSeries) PullRequestSeriesResponse {
+	response := PullRequestSeriesResponse{
+		Summary:    newPullRequestStatsResponse(s.Summary),
+		Histogram:  newHistogramResponse(s.Histogram),
+		TimeSeries: make([]PullRequestBucketResponse, 0, len(s.TimeSeries)),
+	}
+	for _, bucket := range s.TimeSeries {
+		response.TimeSeries = append(response.TimeSeries, PullRequestBucketResponse{
+			Start:                    bucket.Start,
+			PullRequestStatsResponse: newPullRequestStatsResponse(bucket.PullRequestStats),
+		})
+	}
+	return response
+}
+
+func newPullRequestStatsResponse(s metrics.PullRequestStats) PullRequestStatsResponse {
+	return PullRequestStatsResponse{
+		Opened:         s.Opened,
+		Merged:         s.Merged,
+		Closed:         s.Closed,
+		OpenCloseRatio: round(s.OpenCloseRatio),
+		AbandonedRate:  round(s.AbandonedRate),
+		TimeToMerge:    newDurationStatsResponse(s.TimeToMerge),
+	}
+}
+
+func newDurationStatsResponse(s metrics.DurationStats) DurationStatsResponse {
+	return DurationStatsResponse{
+		Count:       s.Count,
+		MedianHours: hours(s.Median),
+		P75Hours:    hours(s.P75),
+		P90Hours:    hours(s.P90),
+	}
+}
+
+func newHistogramResponse(bins []metrics.HistogramBin) []HistogramBinResponse {
+	response := make([]HistogramBinResponse, 0, len(bins))
+	for _, bin := range bins {
+		binResponse := HistogramBinResponse{
+			Label:    bin.Label,
+			MinHours: hours(bin.Min),
+			Count:    bin.Count,
+		}
+		if bin.Max > 0 {
+			max := hours(bin.Max)
+			binResponse.MaxHours = &max
+		}
+		response = append(response, binResponse)
+	}
+	return response
+}
+
+func hours(d time.Duration) float64 {
+	return round(d.Hours())
+}
+
+// round keeps two decimals
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// defaultLargest is how many open pull requests per repository a size report lists
+const defaultLargest = 5
+
+type PullRequestSizesRequest struct {
+	RepositorySetRequest
+	// Largest is the number of open pull requests listed per repository
+	Largest int `query:"largest" validate:"omitempty,min=1,max=50"`
+	// Files loads the files of every pull request, one upstream request
+	// each, so that excluded paths are left out of sizes
+	Files bool `query:"files"`
+	// Reviews loads the reviews of every pull request, one upstream request
+	// each, to relate size to the time to first review
+	Reviews bool `query:"reviews"`
+}
+
+func (r *PullRequestSizesRequest) largest() int {
+	if r.Largest == 0 {
+		return defaultLargest
+	}
+	return r.Largest
+}
+
+type PullRequestSizesResponse struct {
+	Since       time.Time               `json:"since"`
+	Until       time.Time               `json:"until"`
+	Team        string                  `json:"team,omitempty"`
+	Attribution string                  `json:"attribution,omitempty"`
+	Partial     bool                    `json:"partial"`
+	Thresholds  []SizeThresholdResponse `json:"thresholds"`
+	// Excluded lists the patterns of files left out of sizes; empty unless files were loaded
+	Excluded    []string                      `json:"excluded,omitempty"`
+	Sizes       []SizeStatsResponse           `json:"sizes"`
+	Correlation SizeCorrelationResponse       `json:"correlation"`
+	LargestOpen []RepositoryOversizedResponse `json:"largest_open"`
+	Failures    []FailureResponse             `json:"failures"`
+}
+
+// SizeThresholdResponse is the largest changed line count of a size class
+type SizeThresholdResponse struct {
+	Size     string `json:"size"`
+	MaxLines int    `json:"max_lines"`
+}
+
+type SizeStatsResponse struct {
+	Size        string                `json:"size"`
+	Count       int                   `json:"count"`
+	Merged      int                   `json:"merged"`
+	TimeToMerge DurationStatsResponse `json:"time_to_merge"`
+	// TimeToFirstReview is empty unless reviews were loaded
+	TimeToFirstReview DurationStatsResponse `json:"time_to_first_review"`
+	// MedianReviews is the median number of reviews, not their timing
+	MedianReviews float64 `json:"median_reviews"`
+}
+
+// SizeCorrelationResponse holds rank correlations of changed lines, null
+// when there were too few pull requests. TimeToFirstReview is null unless
+// reviews were loaded.
+type SizeCorrelationResponse struct {
+	TimeToMerge       *float64 `json:"time_to_merge"`
+	TimeToFirstReview *float64 `json:"time_to_first_review"`
+	// Untimed counts the reviewed pull requests left out of
+	// TimeToFirstReview because their provider reports no review times, as
+	// with GitLab approvals
+	Untimed int `json:"untimed"`
+}
+
+type RepositoryOversizedResponse struct {
+	Repository   string                         `json:"repository"`
+	PullRequests []OversizedPullRequestResponse `json:"pull_requests"`
+}
+
+type OversizedPullRequestResponse struct {
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	Author       string    `json:"author"`
+	Size         string    `json:"size"`
+	Lines        int       `json:"lines"`
+	Additions    int       `json:"additions"`
+	Deletions    int       `json:"deletions"`
+	ChangedFiles int       `json:"changed_files"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newPullRequestSizesResponse(r *metrics.SizeReport) PullRequestSizesResponse {
+	response := PullRequestSizesResponse{
+		Thresholds: make([]SizeThresholdResponse, 0, len(r.Thresholds)),
+		Sizes:      make([]SizeStatsResponse, 0, len(r.Sizes)),
+		Correlation: SizeCorrelationResponse{
+			TimeToMerge:       roundPtr(r.Correlation.TimeToMerge),
+			TimeToFirstReview: roundPtr(r.Correlation.TimeToFirstReview),
+			Untimed:           r.Correlation.Untimed,
+		},
+		LargestOpen: make([]RepositoryOversizedResponse, 0, len(r.LargestOpen)),
+	}
+	for _, threshold := range r.Thresholds {
+		response.Thresholds = append(response.Thresholds, SizeThresholdResponse{Size: string(threshold.Size), MaxLines: threshold.MaxLines})
+	}
+	for _, size := range r.Sizes {
+		response.Sizes = append(response.Sizes, SizeStatsResponse{
+			Size:              string(size.Size),
+			Count:             size.Count,
+			Merged:            size.Merged,
+			TimeToMerge:       newDurationStatsResponse(size.TimeToMerge),
+			TimeToFirstReview: newDurationStatsResponse(size.TimeToFirstReview),
+			MedianReviews:     size.MedianReviews,
+		})
+	}
+	for _, repo := range r.LargestOpen {
+		repoResponse := RepositoryOversizedResponse{Repository: repo.RepositoryID}
+		for _, pr := range repo.PullRequests {
+			repoResponse.PullRequests = append(repoResponse.PullRequests, OversizedPullRequestResponse{
+				Number:       pr.Number,
+				Title:        pr.Title,
+				Author:       pr.AuthorLogin,
+				Size:         string(pr.Size),
+				Lines:        pr.Lines,
+				Additions:    pr.Additions,
+				Deletions:    pr.Deletions,
+				ChangedFiles: pr.ChangedFiles,
+				CreatedAt:    pr.CreatedAt,
+			})
+		}
+		response.LargestOpen = append(response.LargestOpen, repoResponse)
+	}
+	return response
+}
+
+func roundPtr(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	rounded := round(*f)
+	return &rounded
+}
+
+const (
+	defaultReworkDays   = 21
+	defaultHotspotLimit = 20
+	defaultHotspotDepth = 2
+)
+
+type HotspotsRequest struct {
+	// Repository is written as provider:name, e.g. "github:acme/api"
+	Repository string `query:"repo" validate:"required"`
+	// Include and Exclude are comma-separated path globs, e.g. "src/**,*.go"
+	Include string `query:"include"`
+	Exclude string `query:"exclude"`
+	// ReworkDays is how soon after a file's previous change its changed lines count as rework
+	ReworkDays int `query:"rework_days" validate:"omitempty,min=1,max=365"`
+	Limit      int `query:"limit" validate:"omitempty,min=1,max=200"`
+	// Depth is how many directory levels the tree shows below the root
+	Depth int `query:"depth" validate:"omitempty,min=1,max=10"`
+	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
+}
+
+func (r *HotspotsRequest) reworkDays() int {
+	if r.ReworkDays == 0 {
+		return defaultReworkDays
+	}
+	return r.ReworkDays
+}
+
+func (r *HotspotsRequest) query() (metrics.HotspotQuery, error) {
+	query := metrics.HotspotQuery{
+		ReworkWindow: time.Duration(r.reworkDays()) * 24 * time.Hour,
+		Limit:        r.Limit,
+		Depth:        r.Depth,
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultHotspotLimit
+	}
+	if query.Depth == 0 {
+		query.Depth = defaultHotspotDepth
+	}
+
+	var err error
+	if query.Include, err = compileGlobs(r.Include); err != nil {
+		return query, fmt.Errorf("include: %w", err)
+	}
+	if query.Exclude, err = compileGlobs(r.Exclude); err != nil {
+		return query, fmt.Errorf("exclude: %w", err)
+	}
+	return query, nil
+}
+
+// compileGlobs compiles a comma-separated list of path globs
+func compileGlobs(value string) (glob.Set, error) {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return glob.CompileSet(patterns)
+}
+
+type HotspotsResponse struct {
+	Repository string    `json:"repository"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	ReworkDays int       `json:"rework_days"`
+	// Truncated is set when the repository had more commits than are read per query
+	Truncated    bool                   `json:"truncated"`
+	Commits      int                    `json:"commits"`
+	ChangedFiles int                    `json:"changed_files"`
+	Files        []FileHotspotResponse  `json:"files"`
+	Directories  []PathStatsResponse    `json:"directories"`
+	Tree         *DirectoryNodeResponse `json:"tree"`
+}
+
+type PathStatsResponse struct {
+	Path        string  `json:"path"`
+	Churn       int     `json:"churn"`
+	Additions   int     `json:"additions"`
+	Deletions   int     `json:"deletions"`
+	Changes     int     `json:"changes"`
+	Authors     int     `json:"authors"`
+	ReworkLines int     `json:"rework_lines"`
+	ReworkRate  float64 `json:"rework_rate"`
+	Score       float64 `json:"score"`
+}
+
+type FileHotspotResponse struct {
+	PathStatsResponse
+	Removed bool `json:"removed,omitempty"`
+}
+
+type DirectoryNodeResponse struct {
+	PathStatsResponse
+	Files    int                      `json:"files"`
+	Children []*DirectoryNodeResponse `json:"children,omitempty"`
+}
+
+func newHotspotsResponse(r *metrics.HotspotReport) HotspotsResponse {
+	response := HotspotsResponse{
+		Commits:      r.Commits,
+		ChangedFiles: r.ChangedFiles,
+		Files:        make([]FileHotspotResponse, 0, len(r.Files)),
+		Directories:  make([]PathStatsResponse, 0, len(r.Directories)),
+		Tree:         newDirectoryNodeResponse(r.Tree),
+	}
+	for _, file := range r.Files {
+		response.Files = append(response.Files, FileHotspotResponse{PathStatsResponse: newPathStatsResponse(file.PathStats), Removed: file.Removed})
+	}
+	for _, dir := range r.Directories {
+		response.Directories = append(response.Directories, newPathStatsResponse(dir))
+	}
+	return response
+}
+
+func newPathStatsResponse(s metrics.PathStats) PathStatsResponse {
+	return PathStatsResponse{
+		Path:        s.Path,
+		Churn:       s.Churn(),
+		Additions:   s.Additions,
+		Deletions:   s.Deletions,
+		Changes:     s.Changes,
+		Authors:     s.Authors,
+		ReworkLines: s.ReworkLines,
+		ReworkRate:  round(s.ReworkRate()),
+		Score:       round(s.Score),
+	}
+}
+
+func newDirectoryNodeResponse(node *metrics.DirectoryNode) *DirectoryNodeResponse {
+	if node == nil {
+		return nil
+	}
+	response := &DirectoryNodeResponse{PathStatsResponse: newPathStatsResponse(node.PathStats), Files: node.Files}
+	for _, child := range node.Children {
+		response.Children = append(response.Children, newDirectoryNodeResponse(child))
+	}
+	return response
+}
+
+const (
+	defaultBusFactorLimit = 20
+	defaultBusFactorDepth = 2
+)
+
+type BusFactorRequest struct {
+	// Repository is written as provider:name, e.g. "github:acme/api"
+	Repository string `query:"repo" validate:"required"`
+	// Include and Exclude are comma-separated path globs, e.g. "src/**,*.go"
+	Include string `query:"include"`
+	Exclude string `query:"exclude"`
+	// The thresholds below default to the metrics.bus_factor configuration
+	HalfLifeDays    int `query:"half_life_days" validate:"omitempty,min=1,max=3650"`
+	CoveragePercent int `query:"coverage_percent" validate:"omitempty,min=1,max=100"`
+	InactiveDays    int `query:"inactive_days" validate:"omitempty,min=1,max=3650"`
+	RiskThreshold   int `query:"risk_threshold" validate:"omitempty,min=1,max=100"`
+	Limit           int `query:"limit" validate:"omitempty,min=1,max=200"`
+	// Depth is how many directory levels the tree shows below the root
+	Depth int `query:"depth" validate:"omitempty,min=1,max=10"`
+	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
+}
+
+// since defaults to a year before until: ownership needs a longer history
+// than the month other reports default to
+func (r *BusFactorRequest) since(until time.Time) time.Time {
+	if r.Since != nil {
+		return *r.Since
+	}
+	return until.AddDate(-1, 0, 0)
+}
+
+func (r *BusFactorRequest) query(defaults metrics.KnowledgeThresholds, until time.Time) (metrics.KnowledgeQuery, error) {
+	query := metrics.KnowledgeQuery{
+		KnowledgeThresholds: defaults,
+		Until:               until,
+		Limit:               r.Limit,
+		Depth:               r.Depth,
+	}
+	if r.HalfLifeDays > 0 {
+		query.HalfLife = time.Duration(r.HalfLifeDays) * 24 * time.Hour
+	}
+	if r.CoveragePercent > 0 {
+		query.Coverage = float64(r.CoveragePercent) / 100
+	}
+	if r.InactiveDays > 0 {
+		query.InactiveAfter = time.Duration(r.InactiveDays) * 24 * time.Hour
+	}
+	if r.RiskThreshold > 0 {
+		query.RiskThreshold = r.RiskThreshold
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultBusFactorLimit
+	}
+	if query.Depth == 0 {
+		query.Depth = defaultBusFactorDepth
+	}
+
+	var err error
+	if query.Include, err = compileGlobs(r.Include); err != nil {
+		return query, fmt.Errorf("include: %w", err)
+	}
+	if query.Exclude, err = compileGlobs(r.Exclude); err != nil {
+		return query, fmt.Errorf("exclude: %w", err)
+	}
+	return query, nil
+}
+
+type BusFactorResponse struct {
+	Repository string    `json:"repository"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	// Truncated is set when the repository had more commits than are read per query
+	Truncated  bool                        `json:"truncated"`
+	Thresholds KnowledgeThresholdsResponse `json:"thresholds"`
+	Commits    int                         `json:"commits"`
+	Files      int                         `json:"files"`
+	// Summary holds the ownership of the whole repository
+	Summary           PathKnowledgeResponse   `json:"summary"`
+	AtRiskFileCount   int                     `json:"at_risk_file_count"`
+	AtRiskFiles       []PathKnowledgeResponse `json:"at_risk_files"`
+	AtRiskDirectories []PathKnowledgeResponse `json:"at_risk_directories"`
+	OrphanedFileCount int                     `json:"orphaned_file_count"`
+	OrphanedFiles     []PathKnowledgeResponse `json:"orphaned_files"`
+	Tree              *KnowledgeNodeResponse  `json:"tree"`
+}
+
+type KnowledgeThresholdsResponse struct {
+	HalfLifeDays    int `json:"half_life_days"`
+	CoveragePercent int `json:"coverage_percent"`
+	InactiveDays    int `json:"inactive_days"`
+	RiskThreshold   int `json:"risk_threshold"`
+}
+
+type PathKnowledgeResponse struct {
+	Path      string          `json:"path"`
+	Files     int             `json:"files"`
+	BusFactor int             `json:"bus_factor"`
+	AtRisk    bool            `json:"at_risk"`
+	Owners    []OwnerResponse `json:"owners"`
+}
+
+type OwnerResponse struct {
+	Author       string    `json:"author"`
+	Name         string    `json:"name,omitempty"`
+	Email        string    `json:"email,omitempty"`
+	Share        float64   `json:"share"`
+	LastCommitAt time.Time `json:"last_commit_at"`
+	Inactive     bool      `json:"inactive,omitempty"`
+}
+
+type KnowledgeNodeResponse struct {
+	PathKnowledgeResponse
+	Children []*KnowledgeNodeResponse `json:"children,omitempty"`
+}
+
+func newBusFactorResponse(r *metrics.KnowledgeReport, query metrics.KnowledgeQuery) BusFactorResponse {
+	return BusFactorResponse{
+		Thresholds: KnowledgeThresholdsResponse{
+			HalfLifeDays:    int(query.HalfLife / (24 * time.Hour)),
+			CoveragePercent: int(math.Round(query.Coverage * 100)),
+			InactiveDays:    int(query.InactiveAfter / (24 * time.Hour)),
+			RiskThreshold:   query.RiskThreshold,
+		},
+		Commits:           r.Commits,
+		Files:             r.Files,
+		Summary:           newPathKnowledgeResponse(r.Repository),
+		AtRiskFileCount:   r.AtRiskFileCount,
+		AtRiskFiles:       newPathKnowledgeResponses(r.AtRiskFiles),
+		AtRiskDirectories: newPathKnowledgeResponses(r.AtRiskDirectories),
+		OrphanedFileCount: r.OrphanedCount,
+		OrphanedFiles:     newPathKnowledgeResponses(r.Orphaned),
+		Tree:              newKnowledgeNodeResponse(r.Tree),
+	}
+}
+
+func newPathKnowledgeResponses(items []metrics.PathKnowledge) []PathKnowledgeResponse {
+	responses := make([]PathKnowledgeResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, newPathKnowledgeResponse(item))
+	}
+	return responses
+}
+
+func newPathKnowledgeResponse(k metrics.PathKnowledge) PathKnowledgeResponse {
+	response := PathKnowledgeResponse{
+		Path:      k.Path,
+		Files:     k.Files,
+		BusFactor: k.BusFactor,
+		AtRisk:    k.AtRisk,
+		Owners:    make([]OwnerResponse, 0, len(k.Owners)),
+	}
+	for _, owner := range k.Owners {
+		response.Owners = append(response.Owners, OwnerResponse{
+			Author:       owner.Key,
+			Name:         owner.Name,
+			Email:        owner.Email,
+			Share:        round(owner.Share),
+			LastCommitAt: owner.LastCommitAt,
+			Inactive:     owner.Inactive,
+		})
+	}
+	return response
+}
+
+func newKnowledgeNodeResponse(node *metrics.KnowledgeNode) *KnowledgeNodeResponse {
+	if node == nil {
+		return nil
+	}
+	response := &KnowledgeNodeResponse{PathKnowledgeResponse: newPathKnowledgeResponse(node.PathKnowledge)}
+	for _, child := range node.Children {
+		response.Children = append(response.Children, newKnowledgeNodeResponse(child))
+	}
+	return response
+}
+
+const defaultCodeOwnershipLimit = 50
+
+type CodeOwnershipRequest struct {
+	RepositorySetRequest
+	// Limit caps the unowned paths and pull requests listed per repository
+	Limit int `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+func (r *CodeOwnershipRequest) limit() int {
+	if r.Limit == 0 {
+		return defaultCodeOwnershipLimit
+	}
+	return r.Limit
+}
+
+type CodeOwnershipResponse struct {
+	Since        time.Time                     `json:"since"`
+	Until        time.Time                     `json:"until"`
+	Team         string                        `json:"team,omitempty"`
+	Attribution  string                        `json:"attribution,omitempty"`
+	Partial      bool                          `json:"partial"`
+	Totals       CodeOwnershipTotalsResponse   `json:"totals"`
+	Repositories []RepositoryOwnershipResponse `json:"repositories"`
+	Failures     []FailureResponse             `json:"failures"`
+}
+
+type CodeOwnershipTotalsResponse struct {
+	Repositories int `json:"repositories"`
+	// WithCodeOwners counts the repositories that have a CODEOWNERS file
+	WithCodeOwners  int                  `json:"with_codeowners"`
+	Files           int                  `json:"files"`
+	OwnedFiles      int                  `json:"owned_files"`
+	CoveragePercent float64              `json:"coverage_percent"`
+	PullRequests    OwnerReviewsResponse `json:"pull_requests"`
+}
+
+type RepositoryOwnershipResponse struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	// CodeOwnersPath is where the CODEOWNERS file was found; empty without one
+	CodeOwnersPath string               `json:"codeowners_path,omitempty"`
+	Errors         []ParseErrorResponse `json:"errors,omitempty"`
+	// TreeTruncated is set when the provider listed only part of the files
+	TreeTruncated   bool    `json:"tree_truncated"`
+	Files           int     `json:"files"`
+	OwnedFiles      int     `json:"owned_files"`
+	CoveragePercent float64 `json:"coverage_percent"`
+	// ChangedFiles are the distinct files changed by the pull requests
+	ChangedFiles           int                            `json:"changed_files"`
+	OwnedChangedFiles      int                            `json:"owned_changed_files"`
+	ChangedCoveragePercent float64                        `json:"changed_coverage_percent"`
+	UnownedPaths           []PathCountResponse            `json:"unowned_paths"`
+	Owners                 []OwnerFilesResponse           `json:"owners"`
+	Reviews                OwnerReviewsResponse           `json:"reviews"`
+	PullRequests           []PullRequestOwnershipResponse `json:"pull_requests"`
+}
+
+type ParseErrorResponse struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+type PathCountResponse struct {
+	Path  string `json:"path"`
+	Files int    `json:"files"`
+}
+
+type OwnerFilesResponse struct {
+	Owner string `json:"owner"`
+	Files int    `json:"files"`
+}
+
+// OwnerReviewsResponse counts pull requests by whether code owners reviewed them
+type OwnerReviewsResponse struct {
+	Total       int `json:"total"`
+	Reviewed    int `json:"reviewed"`
+	Partial     int `json:"partial"`
+	NotReviewed int `json:"not_reviewed"`
+	// Unowned pull requests changed no file that requires an owner's review
+	Unowned int `json:"unowned"`
+	// ReviewedPercent is the share of pull requests needing an owner's review that fully got one
+	ReviewedPercent float64 `json:"reviewed_percent"`
+}
+
+type PullRequestOwnershipResponse struct {
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	Author         string    `json:"author"`
+	State          string    `json:"state"`
+	CreatedAt      time.Time `json:"created_at"`
+	Status         string    `json:"status"`
+	Owners         []string  `json:"owners"`
+	OwnerReviewers []string  `json:"owner_reviewers"`
+	ChangedFiles   int       `json:"changed_files"`
+	UnownedFiles   int       `json:"unowned_files"`
+}
+
+func newRepositoryOwnershipResponse(repo service.RepositoryOwnership, r *metrics.OwnershipReport) RepositoryOwnershipResponse {
+	response := RepositoryOwnershipResponse{
+		Provider:               string(repo.Repository.Provider),
+		Name:                   repo.Repository.Name,
+		TreeTruncated:          repo.Tree.Truncated,
+		Files:                  r.Files,
+		OwnedFiles:             r.OwnedFiles,
+		CoveragePercent:        round(r.Coverage() * 100),
+		ChangedFiles:           r.ChangedFiles,
+		OwnedChangedFiles:      r.OwnedChangedFiles,
+		ChangedCoveragePercent: round(r.ChangedCoverage() * 100),
+		UnownedPaths:           make([]PathCountResponse, 0, len(r.UnownedPaths)),
+		Owners:                 make([]OwnerFilesResponse, 0, len(r.Owners)),
+		Reviews: newOwnerReviewsResponse(
+			r.Statuses[metrics.OwnerReviewed], r.Statuses[metrics.OwnerReviewPartial],
+			r.Statuses[metrics.OwnerNotReviewed], r.Statuses[metrics.OwnerReviewUnowned],
+		),
+		PullRequests: make([]PullRequestOwnershipResponse, 0, len(r.PullRequests)),
+	}
+	if repo.CodeOwners != nil {
+		response.CodeOwnersPath = repo.CodeOwners.Path
+		for _, parseErr := range repo.CodeOwners.File.Errors {
+			response.Errors = append(response.Errors, ParseErrorResponse{Line: parseErr.Line, Message: parseErr.Message})
+		}
+	}
+	for _, unowned := range r.UnownedPaths {
+		response.UnownedPaths = append(response.UnownedPaths, PathCountResponse{Path: unowned.Path, Files: unowned.Files})
+	}
+	for _, owner := range r.Owners {
+		response.Owners = append(response.Owners, OwnerFilesResponse{Owner: owner.Owner, Files: owner.Files})
+	}
+	for _, pr := range r.PullRequests {
+		response.PullRequests = append(response.PullRequests, PullRequestOwnershipResponse{
+			Number:         pr.Number,
+			Title:          pr.Title,
+			Author:         pr.AuthorLogin,
+			State:          pr.State,
+			CreatedAt:      pr.CreatedAt,
+			Status:         string(pr.Status),
+			Owners:         append([]string{}, pr.Owners...),
+			OwnerReviewers: append([]string{}, pr.Reviewers...),
+			ChangedFiles:   len(pr.Files),
+			UnownedFiles:   pr.UnownedFiles,
+		})
+	}
+	return response
+}
+
+func newOwnerReviewsResponse(reviewed, partial, notReviewed, unowned int) OwnerReviewsResponse {
+	response := OwnerReviewsResponse{
+		Total:       reviewed + partial + notReviewed + unowned,
+		Reviewed:    reviewed,
+		Partial:     partial,
+		NotReviewed: notReviewed,
+		Unowned:     unowned,
+	}
+	if needed := reviewed + partial + notReviewed; needed > 0 {
+		response.ReviewedPercent = round(float64(reviewed) / float64(needed) * 100)
+	}
+	return response
+}
+
+func newCodeOwnershipTotals(repos []RepositoryOwnershipResponse) CodeOwnershipTotalsResponse {
+	totals := CodeOwnershipTotalsResponse{Repositories: len(repos)}
+	var reviewed, partial, notReviewed, unowned int
+	for _, repo := range repos {
+		if repo.CodeOwnersPath != "" {
+			totals.WithCodeOwners++
+		}
+		totals.Files += repo.Files
+		totals.OwnedFiles += repo.OwnedFiles
+		reviewed += repo.Reviews.Reviewed
+		partial += repo.Reviews.Partial
+		notReviewed += repo.Reviews.NotReviewed
+		unowned += repo.Reviews.Unowned
+	}
+	if totals.Files > 0 {
+		totals.CoveragePercent = round(float64(totals.OwnedFiles) / float64(totals.Files) * 100)
+	}
+	totals.PullRequests = newOwnerReviewsResponse(reviewed, partial, notReviewed, unowned)
+	return totals
+}
+
+const defaultReviewPairLimit = 50
+
+type ReviewMetricsRequest struct {
+	RepositorySetRequest
+	// Limit caps the reviewer-author pairs
+	Limit int `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+func (r *ReviewMetricsRequest) limit() int {
+	if r.Limit == 0 {
+		return defaultReviewPairLimit
+	}
+	return r.Limit
+}
+
+type ReviewMetricsResponse struct {
+	Since        time.Time `json:"since"`
+	Until        time.Time `json:"until"`
+	Team         string    `json:"team,omitempty"`
+	Attribution  string    `json:"attribution,omitempty"`
+	Partial      bool      `json:"partial"`
+	PullRequests int       `json:"pull_requests"`
+	// Reviewed counts the pull requests someone other than the author reviewed
+	Reviewed          int                         `json:"reviewed"`
+	Reviews           int                         `json:"reviews"`
+	TimeToFirstReview DurationStatsResponse       `json:"time_to_first_review"`
+	Gini              *float64                    `json:"gini"`
+	Reviewers         []ReviewerStatsResponse     `json:"reviewers"`
+	Teams             []TeamConcentrationResponse `json:"teams"`
+	// Pairs is the reviewer-author matrix, listing the pairs with reviews
+	Pairs    []ReviewPairResponse `json:"pairs"`
+	Failures []FailureResponse    `json:"failures"`
+}
+
+type PersonResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ReviewerStatsResponse struct {
+	PersonResponse
+	Reviews          int `json:"reviews"`
+	Approvals        int `json:"approvals"`
+	ChangesRequested int `json:"changes_requested"`
+	Comments         int `json:"comments"`
+	PullRequests     int `json:"pull_requests"`
+	// Requested counts the pull requests the reviewer was asked to review or reviewed
+	Requested         int                   `json:"requested"`
+	CompletionRate    float64               `json:"completion_rate"`
+	TimeToFirstReview DurationStatsResponse `json:"time_to_first_review"`
+}
+
+type TeamConcentrationResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Members   int      `json:"members"`
+	Reviewers int      `json:"reviewers"`
+	Reviews   int      `json:"reviews"`
+	Gini      *float64 `json:"gini"`
+	TopShare  float64  `json:"top_share"`
+}
+
+type ReviewPairResponse struct {
+	Reviewer     PersonResponse `json:"reviewer"`
+	Author       PersonResponse `json:"author"`
+	PullRequests int            `json:"pull_requests"`
+}
+
+func newReviewMetricsResponse(r *metrics.ReviewReport) ReviewMetricsResponse {
+	response := ReviewMetricsResponse{
+		PullRequests:      r.PullRequests,
+		Reviewed:          r.Reviewed,
+		Reviews:           r.Reviews,
+		TimeToFirstReview: newDurationStatsResponse(r.TimeToFirstReview),
+		Gini:              roundPtr(r.Gini),
+		Reviewers:         make([]ReviewerStatsResponse, 0, len(r.Reviewers)),
+		Teams:             make([]TeamConcentrationResponse, 0, len(r.Teams)),
+		Pairs:             make([]ReviewPairResponse, 0, len(r.Pairs)),
+	}
+	for _, reviewer := range r.Reviewers {
+		response.Reviewers = append(response.Reviewers, ReviewerStatsResponse{
+			PersonResponse:    newPersonResponse(reviewer.Person),
+			Reviews:           reviewer.Reviews,
+			Approvals:         reviewer.Approvals,
+			ChangesRequested:  reviewer.ChangesRequested,
+			Comments:          reviewer.Comments,
+			PullRequests:      reviewer.PullRequests,
+			Requested:         reviewer.Requested,
+			CompletionRate:    round(reviewer.CompletionRate()),
+			TimeToFirstReview: newDurationStatsResponse(reviewer.TimeToFirstReview),
+		})
+	}
+	for _, t := range r.Teams {
+		response.Teams = append(response.Teams, TeamConcentrationResponse{
+			ID:        t.ID,
+			Name:      t.Name,
+			Members:   t.Members,
+			Reviewers: t.Reviewers,
+			Reviews:   t.Reviews,
+			Gini:      roundPtr(t.Gini),
+			TopShare:  round(t.TopShare),
+		})
+	}
+	for _, pair := range r.Pairs {
+		response.Pairs = append(response.Pairs, ReviewPairResponse{
+			Reviewer:     newPersonResponse(pair.Reviewer),
+			Author:       newPersonResponse(pair.Author),
+			PullRequests: pair.PullRequests,
+		})
+	}
+	return response
+}
+
+func newPersonResponse(p metrics.Person) PersonResponse {
+	return PersonResponse{ID: p.Key, Name: p.Name}
+}
+
+const defaultCommitAuthorLimit = 50
+
+type CommitMetricsRequest struct {
+	RepositorySetRequest
+	// Limit caps the listed authors
+	Limit int `query:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+func (r *CommitMetricsRequest) limit() int {
+	if r.Limit == 0 {
+		return defaultCommitAuthorLimit
+	}
+	return r.Limit
+}
+
+type CommitMetricsResponse struct {
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	Team        string    `json:"team,omitempty"`
+	Attribution string    `json:"attribution,omitempty"`
+	Partial     bool      `json:"partial"`
+	Commits     int       `json:"commits"`
+	// ConventionalRate is the share of commits other than merges following Conventional Commits
+	Conventional     int                       `json:"conventional"`
+	ConventionalRate float64                   `json:"conventional_rate"`
+	Breaking         int                       `json:"breaking"`
+	CoAuthored       int                       `json:"co_authored"`
+	SignedOff        int                       `json:"signed_off"`
+	Categories       []CommitCategoryResponse  `json:"categories"`
+	Types            []CommitTypeCountResponse `json:"types"`
+	Authors          []AuthorCommitsResponse   `json:"authors"`
+	Failures         []FailureResponse         `json:"failures"`
+}
+
+type CommitCategoryResponse struct {
+	Category string  `json:"category"`
+	Commits  int     `json:"commits"`
+	Share    float64 `json:"share"`
+}
+
+type CommitTypeCountResponse struct {
+	Type    string `json:"type"`
+	Commits int    `json:"commits"`
+}
+
+type AuthorCommitsResponse struct {
+	PersonResponse
+	Commits    int `json:"commits"`
+	CoAuthored int `json:"co_authored"`
+	// Credited counts authored and co-authored commits
+	Credited   int            `json:"credited"`
+	Categories map[string]int `json:"categories"`
+}
+
+func newCommitMetricsResponse(r *metrics.CommitReport) CommitMetricsResponse {
+	response := CommitMetricsResponse{
+		Commits:          r.Commits,
+		Conventional:     r.Conventional,
+		ConventionalRate: round(r.ConventionalRate()),
+		Breaking:         r.Breaking,
+		CoAuthored:       r.CoAuthored,
+		SignedOff:        r.SignedOff,
+		Categories:       make([]CommitCategoryResponse, 0, len(metrics.CommitCategories)),
+		Types:            make([]CommitTypeCountResponse, 0, len(r.Types)),
+		Authors:          make([]AuthorCommitsResponse, 0, len(r.Authors)),
+	}
+	for _, category := range metrics.CommitCategories {
+		response.Categories = append(response.Categories, CommitCategoryResponse{
+			Category: string(category),
+			Commits:  r.Categories[category],
+			Share:    round(r.Share(category)),
+		})
+	}
+	for _, t := range r.Types {
+		response.Types = append(response.Types, CommitTypeCountResponse{Type: t.Type, Commits: t.Commits})
+	}
+	for _, a := range r.Authors {
+		categories := make(map[string]int, len(a.Categories))
+		for category, count := range a.Categories {
+			categories[string(category)] = count
+		}
+		response.Authors = append(response.Authors, AuthorCommitsResponse{
+			PersonResponse: newPersonResponse(a.Person),
+			Commits:        a.Commits,
+			CoAuthored:     a.CoAuthored,
+			Credited:       a.Credited(),
+			Categories:     categories,
+		})
+	}
+	return response
+}
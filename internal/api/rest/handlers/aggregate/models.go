@@ -0,0 +1,149 @@
+package aggregate
+
+import (
+	"time"
+
+	"devmetrics/internal/api/rest/apierror"
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/secrets"
+	service "devmetrics/internal/services/vcs"
+)
+
+// RepositorySetRequest selects the repositories to aggregate: either
+// Repositories or Owner, a Team, or both.
+type RepositorySetRequest struct {
+	// Repositories is a comma-separated list of provider:name, e.g. "github:acme/api,gitlab:platform/web"
+	Repositories string `query:"repos"`
+	// Owner selects every repository of an organization, user or group on Provider
+	Owner    string `query:"owner"`
+	Provider string `query:"provider" validate:"omitempty,oneof=github gitlab"`
+	// Team attributes work to one of the tenant's teams. On its own it
+	// selects the team's repositories; with repos or owner it keeps the work
+	// of its members.
+	Team string `query:"team"`
+	// Attribution is "repositories", counting all work in the team's
+	// repositories, or "members", counting only the work of its members
+	Attribution string `query:"attribution" validate:"omitempty,oneof=members repositories"`
+	shared.RepositoryFilterRequest
+	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
+}
+
+type CommitsRequest struct {
+	RepositorySetRequest
+	shared.PaginationRequest
+}
+
+type PullRequestsRequest struct {
+	RepositorySetRequest
+	shared.PaginationRequest
+	State string `query:"state" validate:"omitempty,oneof=all open closed merged"`
+}
+
+type ActivityResponse struct {
+	Since        time.Time                    `json:"since"`
+	Until        time.Time                    `json:"until"`
+	Team         string                       `json:"team,omitempty"`
+	Attribution  string                       `json:"attribution,omitempty"`
+	Partial      bool                         `json:"partial"`
+	Totals       TotalsResponse               `json:"totals"`
+	Repositories []RepositoryActivityResponse `json:"repositories"`
+	Failures     []FailureResponse            `json:"failures"`
+}
+
+type TotalsResponse struct {
+	Repositories       int `json:"repositories,omitempty"`
+	Commits            int `json:"commits"`
+	PullRequests       int `json:"pull_requests"`
+	MergedPullRequests int `json:"merged_pull_requests"`
+	Additions          int `json:"additions"`
+	Deletions          int `json:"deletions"`
+	Contributors       int `json:"contributors"`
+}
+
+type RepositoryActivityResponse struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	TotalsResponse
+	Truncated bool `json:"truncated"`
+}
+
+// FailureResponse describes a repository left out of an aggregate
+type FailureResponse struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	Code     string `json:"code"`
+	Error    string `json:"error"`
+}
+
+// ItemsResponse is one page of merged commits or pull requests
+type ItemsResponse struct {
+	Items    interface{}       `json:"items"`
+	Partial  bool              `json:"partial"`
+	Failures []FailureResponse `json:"failures"`
+}
+
+// attribution returns how work is attributed to the team, if any: by its
+// members when other repositories are selected, else by ownership
+func (r *RepositorySetRequest) attribution() string {
+	switch {
+	case r.Team == "":
+		return ""
+	case r.Attribution != "":
+		return r.Attribution
+	case r.Repositories != "" || r.Owner != "":
+		return attributionMembers
+	default:
+		return attributionRepositories
+	}
+}
+
+const (
+	attributionMembers      = "members"
+	attributionRepositories = "repositories"
+)
+
+func newActivityResponse(a *service.Activity) ActivityResponse {
+	response := ActivityResponse{
+		Since:        a.Since,
+		Until:        a.Until,
+		Partial:      a.Partial(),
+		Totals:       newTotalsResponse(a.Totals, len(a.Repositories)),
+		Repositories: make([]RepositoryActivityResponse, 0, len(a.Repositories)),
+		Failures:     newFailuresResponse(a.Failures),
+	}
+	for _, repo := range a.Repositories {
+		response.Repositories = append(response.Repositories, RepositoryActivityResponse{
+			Provider:       string(repo.Repository.Provider),
+			Name:           repo.Repository.Name,
+			TotalsResponse: newTotalsResponse(repo.ActivityTotals, 0),
+			Truncated:      repo.Truncated,
+		})
+	}
+	return response
+}
+
+func newTotalsResponse(t service.ActivityTotals, repositories int) TotalsResponse {
+	return TotalsResponse{
+		Repositories:       repositories,
+		Commits:            t.Commits,
+		PullRequests:       t.PullRequests,
+		MergedPullRequests: t.MergedPullRequests,
+		Additions:          t.Additions,
+		Deletions:          t.Deletions,
+		Contributors:       t.Contributors,
+	}
+}
+
+func newFailuresResponse(failures []service.RepositoryFailure) []FailureResponse {
+	response := make([]FailureResponse, 0, len(failures))
+	for _, failure := range failures {
+		response = append(response, FailureResponse{
+			Provider: string(failure.Repository.Provider),
+			Name:     failure.Repository.Name,
+			Code:     apierror.Resolve(failure.Err).Code,
+			Error:    secrets.Redact(failure.Err.Error()),
+		})
+	}
+	return response
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	domain "devmetrics/internal/domain/auth"
+	"devmetrics/internal/domain/tenant"
+	service "devmetrics/internal/services/auth"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the API key management endpoints
+type Handler struct {
+	Service     *service.Service
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
+	return &Handler{
+		Service:     service,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+func (h *Handler) ListKeys(c *fiber.Ctx) error {
+	keys, err := h.Service.ListKeys(c.UserContext(), tenant.IDFromContext(c.UserContext()))
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := make([]KeyResponse, 0, len(keys))
+	for _, key := range keys {
+		response = append(response, newKeyResponse(key))
+	}
+
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+func (h *Handler) CreateKey(c *fiber.Ctx) error {
+	req := new(CreateKeyRequest)
+	if err := h.BaseHandler.ParseBodyAndValidate(c, req); err != nil {
+		return err
+	}
+
+	scopes, err := service.ParseScopes(req.Scopes)
+	if err != nil {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Invalid scopes", err.Error())
+	}
+
+	secret, key, err := h.Service.CreateKey(c.UserContext(), tenant.IDFromContext(c.UserContext()), req.Name, scopes, req.RateLimit)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendCreatedResponse(c, CreatedKeyResponse{
+		KeyResponse: newKeyResponse(key),
+		Key:         secret,
+	})
+}
+
+func (h *Handler) RevokeKey(c *fiber.Ctx) error {
+	req := new(KeyRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	key, err := h.Service.RevokeKey(c.UserContext(), tenant.IDFromContext(c.UserContext()), req.ID)
+	switch {
+	case errors.Is(err, domain.ErrKeyNotFound):
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotFound, "not_found", "API key not found", req.ID)
+	case errors.Is(err, service.ErrStaticKey):
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusConflict, "static_key", "API key is defined in configuration", err.Error())
+	case err != nil:
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, newKeyResponse(key))
+}
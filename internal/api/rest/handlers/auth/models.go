@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"time"
+
+	domain "devmetrics/internal/domain/auth"
+)
+
+type CreateKeyRequest struct {
+	Name      string   `json:"name" validate:"required,max=100"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,dive,oneof=read:vcs read:metrics admin"`
+	RateLimit int      `json:"rate_limit" validate:"omitempty,min=1"`
+}
+
+type KeyRequest struct {
+	ID string `params:"id" validate:"required"`
+}
+
+// KeyResponse describes an API key without its hash
+type KeyResponse struct {
+	ID         string         `json:"id"`
+	TenantID   string         `json:"tenant_id"`
+	Name       string         `json:"name"`
+	Scopes     []domain.Scope `json:"scopes"`
+	RateLimit  int            `json:"rate_limit,omitempty"`
+	Static     bool           `json:"static"`
+	CreatedAt  time.Time      `json:"created_at"`
+	LastUsedAt *time.Time     `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time     `json:"revoked_at,omitempty"`
+}
+
+// CreatedKeyResponse includes the key secret, which is only ever shown once
+type CreatedKeyResponse struct {
+	KeyResponse
+	Key string `json:"key"`
+}
+
+func newKeyResponse(key *domain.APIKey) KeyResponse {
+	return KeyResponse{
+		ID:         key.ID,
+		TenantID:   key.TenantID,
+		Name:       key.Name,
+		Scopes:     key.Scopes,
+		RateLimit:  key.RateLimit,
+		Static:     key.Static,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"time"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/health"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the liveness and readiness probes
+type Handler struct {
+	Registry    *health.Registry
+	BaseHandler shared.BaseHandler
+	startedAt   time.Time
+}
+
+func NewHandler(registry *health.Registry, log logger.Logger) *Handler {
+	return &Handler{
+		Registry:    registry,
+		BaseHandler: shared.NewBaseHandler(log),
+		startedAt:   time.Now(),
+	}
+}
+
+// Live reports that the process is up and serving HTTP; it checks no dependencies
+func (h *Handler) Live(c *fiber.Ctx) error {
+	return c.JSON(LivenessResponse{
+		Status:    string(health.StatusOK),
+		Time:      time.Now(),
+		UptimeSec: int64(time.Since(h.startedAt).Seconds()),
+	})
+}
+
+// Ready runs the dependency checks and fails with 503 when a critical one is down
+func (h *Handler) Ready(c *fiber.Ctx) error {
+	report := h.Registry.Check(c.UserContext())
+	if report.Status == health.StatusDown {
+		h.BaseHandler.Log(c).Warn("Readiness check failed", logger.Any("checks", failedChecks(report)))
+		c.Status(fiber.StatusServiceUnavailable)
+	}
+	return c.JSON(newReadinessResponse(report))
+}
+
+// GetReport runs the dependency checks and returns their messages and the
+// provider breakdown of the caller's tenant
+func (h *Handler) GetReport(c *fiber.Ctx) error {
+	report := h.Registry.Check(c.UserContext())
+	return h.BaseHandler.SendResponse(c, newHealthReportResponse(report, tenant.IDFromContext(c.UserContext())))
+}
+
+func failedChecks(report health.Report) []string {
+	var failed []string
+	for _, check := range report.Checks {
+		if check.Result.Status != health.StatusOK {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
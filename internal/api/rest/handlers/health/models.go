@@ -0,0 +1,93 @@
+package health
+
+import (
+	"time"
+
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/health"
+)
+
+type LivenessResponse struct {
+	Status    string    `json:"status"`
+	Time      time.Time `json:"time"`
+	UptimeSec int64     `json:"uptime_sec"`
+}
+
+// ReadinessResponse is served to unauthenticated probes, so it names the
+// checks but leaves out their messages and details
+type ReadinessResponse struct {
+	Status string                `json:"status"`
+	Checks []CheckStatusResponse `json:"checks"`
+}
+
+type CheckStatusResponse struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+}
+
+type HealthReportResponse struct {
+	Status string          `json:"status"`
+	Checks []CheckResponse `json:"checks"`
+}
+
+type CheckResponse struct {
+	Name       string      `json:"name"`
+	Status     string      `json:"status"`
+	Critical   bool        `json:"critical"`
+	Message    string      `json:"message,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
+	CheckedAt  time.Time   `json:"checked_at"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+func newReadinessResponse(report health.Report) ReadinessResponse {
+	checks := make([]CheckStatusResponse, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, CheckStatusResponse{
+			Name:     check.Name,
+			Status:   string(check.Result.Status),
+			Critical: check.Critical,
+		})
+	}
+
+	return ReadinessResponse{
+		Status: string(report.Status),
+		Checks: checks,
+	}
+}
+
+// newHealthReportResponse shows a tenant the details of its own providers.
+// Messages and details of the checks shared by every tenant are only shown
+// to the default tenant, which administers the process.
+func newHealthReportResponse(report health.Report, tenantID string) HealthReportResponse {
+	checks := make([]CheckResponse, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		response := CheckResponse{
+			Name:       check.Name,
+			Status:     string(check.Result.Status),
+			Critical:   check.Critical,
+			CheckedAt:  check.CheckedAt,
+			DurationMs: check.Duration.Milliseconds(),
+		}
+		if providers, ok := check.Result.Details.([]health.ProviderDetail); ok {
+			own := make([]health.ProviderDetail, 0, len(providers))
+			for _, provider := range providers {
+				if provider.Tenant == tenantID {
+					own = append(own, provider)
+				}
+			}
+			response.Details = own
+		}
+		if tenantID == tenant.DefaultID {
+			response.Message = check.Result.Message
+			response.Details = check.Result.Details
+		}
+		checks = append(checks, response)
+	}
+
+	return HealthReportResponse{
+		Status: string(report.Status),
+		Checks: checks,
+	}
+}
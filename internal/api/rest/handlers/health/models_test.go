@@ -0,0 +1,68 @@
+package health
+
+import (
+	"reflect"
+	"testing"
+
+	"devmetrics/internal/health"
+)
+
+func testReport() health.Report {
+	return health.Report{
+		Status: health.StatusDegraded,
+		Checks: []health.CheckReport{
+			{Name: "secret_store", Result: health.Result{Status: health.StatusDown, Message: "open /data/secrets.json: permission denied"}},
+			{Name: "vcs_providers", Critical: true, Result: health.Result{
+				Status:  health.StatusDegraded,
+				Message: "1 of 2 providers degraded or unavailable",
+				Details: []health.ProviderDetail{
+					{Tenant: "acme", Provider: "gitlab", State: "unavailable", Error: "401 Unauthorized"},
+					{Tenant: "default", Provider: "github", State: "ok", Identity: "bot"},
+				},
+			}},
+		},
+	}
+}
+
+func TestReadinessResponse(t *testing.T) {
+	want := ReadinessResponse{
+		Status: "degraded",
+		Checks: []CheckStatusResponse{
+			{Name: "secret_store", Status: "down"},
+			{Name: "vcs_providers", Status: "degraded", Critical: true},
+		},
+	}
+	if got := newReadinessResponse(testReport()); !reflect.DeepEqual(got, want) {
+		t.Errorf("newReadinessResponse = %+v, want %+v", got, want)
+	}
+}
+
+func TestHealthReportTenantScope(t *testing.T) {
+	tests := []struct {
+		tenant        string
+		wantProviders []string
+		wantMessages  bool
+	}{
+		{"default", []string{"acme", "default"}, true},
+		{"acme", []string{"acme"}, false},
+		{"other", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenant, func(t *testing.T) {
+			report := newHealthReportResponse(testReport(), tt.tenant)
+
+			secretStore, providers := report.Checks[0], report.Checks[1]
+			if hasMessage := secretStore.Message != ""; hasMessage != tt.wantMessages {
+				t.Errorf("secret_store message %q shown = %v, want %v", secretStore.Message, hasMessage, tt.wantMessages)
+			}
+
+			var tenants []string
+			for _, detail := range providers.Details.([]health.ProviderDetail) {
+				tenants = append(tenants, detail.Tenant)
+			}
+			if !reflect.DeepEqual(tenants, tt.wantProviders) {
+				t.Errorf("provider tenants = %v, want %v", tenants, tt.wantProviders)
+			}
+		})
+	}
+}
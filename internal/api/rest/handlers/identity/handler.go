@@ -0,0 +1,127 @@
+package identity
+
+import (
+	"errors"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	domain "devmetrics/internal/domain/identity"
+	service "devmetrics/internal/services/identity"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves canonical people and the admin endpoints that shape them
+type Handler struct {
+	Service     *service.Service
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
+	return &Handler{
+		Service:     service,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+// ListPeople lists the people seen so far in the tenant's data
+func (h *Handler) ListPeople(c *fiber.Ctx) error {
+	people, err := h.Service.People(c.UserContext())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := make([]PersonResponse, 0, len(people))
+	for _, person := range people {
+		response = append(response, newPersonResponse(person))
+	}
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+func (h *Handler) GetPerson(c *fiber.Ctx) error {
+	req := new(PersonRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	person, err := h.Service.Person(c.UserContext(), req.ID)
+	if errors.Is(err, service.ErrPersonNotFound) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotFound, "not_found", "Person not found", req.ID)
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, newPersonResponse(*person))
+}
+
+func (h *Handler) ListOverrides(c *fiber.Ctx) error {
+	overrides, err := h.Service.ListOverrides(c.UserContext())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := make([]OverrideResponse, 0, len(overrides))
+	for _, override := range overrides {
+		response = append(response, newOverrideResponse(override))
+	}
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// PutOverride links the given e-mails and logins into one person
+func (h *Handler) PutOverride(c *fiber.Ctx) error {
+	req := new(PutOverrideRequest)
+	if err := h.BaseHandler.ParseBodyAndValidate(c, req); err != nil {
+		return err
+	}
+
+	override, err := h.Service.PutOverride(c.UserContext(), req.override())
+	if errors.Is(err, service.ErrInvalidOverride) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Invalid identity override", err.Error())
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, newOverrideResponse(override))
+}
+
+func (h *Handler) DeleteOverride(c *fiber.Ctx) error {
+	req := new(OverrideRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	err := h.Service.DeleteOverride(c.UserContext(), req.ID)
+	if errors.Is(err, domain.ErrOverrideNotFound) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotFound, "not_found", "Identity override not found", req.ID)
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) GetMailmap(c *fiber.Ctx) error {
+	content, err := h.Service.Mailmap(c.UserContext())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	entries, _ := domain.ParseMailmap(content)
+	return h.BaseHandler.SendResponse(c, MailmapResponse{Content: content, Entries: len(entries)})
+}
+
+// PutMailmap replaces the tenant's .mailmap with the raw request body
+func (h *Handler) PutMailmap(c *fiber.Ctx) error {
+	content := string(c.Body())
+	entries, err := h.Service.PutMailmap(c.UserContext(), content)
+	if errors.Is(err, service.ErrInvalidMailmap) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Invalid mailmap", err.Error())
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, MailmapResponse{Content: content, Entries: len(entries)})
+}
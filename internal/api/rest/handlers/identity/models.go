@@ -0,0 +1,106 @@
+package identity
+
+import (
+	"strings"
+	"time"
+
+	domain "devmetrics/internal/domain/identity"
+	"devmetrics/internal/domain/vcs"
+)
+
+type PersonRequest struct {
+	ID string `params:"id" validate:"required"`
+}
+
+type OverrideRequest struct {
+	ID string `params:"id" validate:"required,max=100,hostname_rfc1123"`
+}
+
+type PutOverrideRequest struct {
+	OverrideRequest
+	Name   string           `json:"name" validate:"max=200"`
+	Emails []string         `json:"emails" validate:"dive,email"`
+	Logins []AccountRequest `json:"logins" validate:"dive"`
+}
+
+type AccountRequest struct {
+	Provider string `json:"provider" validate:"required,oneof=github gitlab bitbucket"`
+	Login    string `json:"login" validate:"required"`
+}
+
+type PersonResponse struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Emails []string          `json:"emails"`
+	Logins []AccountResponse `json:"logins"`
+	Names  []string          `json:"names"`
+}
+
+type AccountResponse struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+}
+
+type OverrideResponse struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Emails    []string          `json:"emails"`
+	Logins    []AccountResponse `json:"logins"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type MailmapResponse struct {
+	Content string `json:"content"`
+	Entries int    `json:"entries"`
+}
+
+func newPersonResponse(p domain.Person) PersonResponse {
+	return PersonResponse{
+		ID:     p.ID,
+		Name:   p.Name,
+		Emails: nonNil(p.Emails),
+		Logins: newAccountsResponse(p.Logins),
+		Names:  nonNil(p.Names),
+	}
+}
+
+func newOverrideResponse(o domain.Override) OverrideResponse {
+	return OverrideResponse{
+		ID:        o.ID,
+		Name:      o.Name,
+		Emails:    nonNil(o.Emails),
+		Logins:    newAccountsResponse(o.Logins),
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+func newAccountsResponse(accounts []domain.Account) []AccountResponse {
+	response := make([]AccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		response = append(response, AccountResponse{Provider: string(account.Provider), Login: account.Login})
+	}
+	return response
+}
+
+func (r *PutOverrideRequest) override() domain.Override {
+	override := domain.Override{
+		// Route parameters point into Fiber's reused buffers; the ID outlives the request
+		ID:     strings.Clone(r.ID),
+		Name:   r.Name,
+		Emails: r.Emails,
+	}
+	for _, account := range r.Logins {
+		override.Logins = append(override.Logins, domain.Account{
+			Provider: vcs.ProviderType(account.Provider),
+			Login:    account.Login,
+		})
+	}
+	return override
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,51 @@
+package reload
+
+import (
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/domain/tenant"
+	service "devmetrics/internal/services/reload"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler reports and triggers configuration reloads. Reloading affects every
+// tenant, so it is limited to admins of the default tenant.
+type Handler struct {
+	Service     *service.Service
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
+	return &Handler{
+		Service:     service,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+func (h *Handler) GetStatus(c *fiber.Ctx) error {
+	if !isDefaultTenant(c) {
+		return errNotDefaultTenant
+	}
+
+	return h.BaseHandler.SendResponse(c, newStatusResponse(h.Service.Status()))
+}
+
+func (h *Handler) Reload(c *fiber.Ctx) error {
+	if !isDefaultTenant(c) {
+		return errNotDefaultTenant
+	}
+
+	status, err := h.Service.Reload(c.UserContext(), service.TriggerAPI)
+	if err != nil {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusUnprocessableEntity, "reload_failed", "Configuration reload failed, the previous configuration is still active", err.Error())
+	}
+
+	return h.BaseHandler.SendResponse(c, newStatusResponse(status))
+}
+
+var errNotDefaultTenant = fiber.NewError(fiber.StatusForbidden, "Configuration reload is limited to the default tenant")
+
+// isDefaultTenant reports whether the request is scoped to the default tenant
+func isDefaultTenant(c *fiber.Ctx) bool {
+	return tenant.IDFromContext(c.UserContext()) == tenant.DefaultID
+}
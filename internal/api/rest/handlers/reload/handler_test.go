@@ -0,0 +1,58 @@
+package reload
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"devmetrics/internal/api/rest/middleware"
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/secrets"
+	service "devmetrics/internal/services/reload"
+	tenantservice "devmetrics/internal/services/tenant"
+	vcsservice "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestTenantRestriction(t *testing.T) {
+	cfg := &config.Config{Tenants: []config.TenantConfig{{ID: config.DefaultTenantID}, {ID: "acme"}}}
+	reloads := service.NewService("", cfg, nil,
+		vcsservice.NewService(vcsservice.Providers{}, nil, nil, logger.NewNop()),
+		tenantservice.NewService(cfg),
+		secrets.NewResolver(0),
+		logger.NewNop(),
+	)
+	h := NewHandler(reloads, logger.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(tenant.WithID(c.UserContext(), c.Get("X-Tenant-ID")))
+		return c.Next()
+	})
+	app.Get("/reload", h.GetStatus)
+	app.Post("/reload", h.Reload)
+
+	tests := []struct {
+		method string
+		tenant string
+		want   int
+	}{
+		{fiber.MethodGet, "acme", fiber.StatusForbidden},
+		{fiber.MethodGet, tenant.DefaultID, fiber.StatusOK},
+		{fiber.MethodPost, "acme", fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.tenant, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/reload", nil)
+			req.Header.Set("X-Tenant-ID", tt.tenant)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
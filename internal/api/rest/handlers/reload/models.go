@@ -0,0 +1,44 @@
+package reload
+
+import (
+	"time"
+
+	service "devmetrics/internal/services/reload"
+)
+
+type StatusResponse struct {
+	ConfigFile      string     `json:"config_file,omitempty"`
+	Generation      int        `json:"generation"`
+	LastAttempt     *time.Time `json:"last_attempt,omitempty"`
+	LastSuccess     *time.Time `json:"last_success,omitempty"`
+	LastTrigger     string     `json:"last_trigger,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	Succeeded       bool       `json:"succeeded"`
+	RebuiltTenants  []string   `json:"rebuilt_tenants"`
+	RestartRequired []string   `json:"restart_required"`
+}
+
+func newStatusResponse(s service.Status) StatusResponse {
+	response := StatusResponse{
+		ConfigFile:      s.ConfigFile,
+		Generation:      s.Generation,
+		LastTrigger:     string(s.LastTrigger),
+		LastError:       s.LastError,
+		Succeeded:       s.LastError == "",
+		RebuiltTenants:  s.Rebuilt,
+		RestartRequired: s.RestartRequired,
+	}
+	if !s.LastAttempt.IsZero() {
+		response.LastAttempt = &s.LastAttempt
+	}
+	if !s.LastSuccess.IsZero() {
+		response.LastSuccess = &s.LastSuccess
+	}
+	if response.RebuiltTenants == nil {
+		response.RebuiltTenants = []string{}
+	}
+	if response.RestartRequired == nil {
+		response.RestartRequired = []string{}
+	}
+	return response
+}
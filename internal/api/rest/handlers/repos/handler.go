@@ -0,0 +1,197 @@
+package repos
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/domain/auth"
+	service "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// repositoryLocalsKey holds the repository resolved by Resolve
+const repositoryLocalsKey = "repository"
+
+// Handler serves the provider-agnostic repository routes. Every provider
+// returns the same response shapes.
+type Handler struct {
+	Service     *service.Service
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
+	return &Handler{
+		Service:     service,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+// Resolve determines the repository a request targets, either from the
+// :provider and wildcard path segments or from the url query parameter,
+// and stores it for Resource and the handlers
+func (h *Handler) Resolve(c *fiber.Ctx) error {
+	var (
+		ref service.RepositoryRef
+		err error
+	)
+	if provider := c.Params("provider"); provider != "" {
+		repoPath, unescapeErr := url.PathUnescape(c.Params("*"))
+		if unescapeErr != nil {
+			return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "invalid_parameters", "Invalid repository path", unescapeErr.Error())
+		}
+		ref, err = h.Service.Repository(provider, repoPath)
+	} else {
+		req := new(RepositoryRequest)
+		if err := c.QueryParser(req); err != nil {
+			return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "invalid_query", "Failed to parse query parameters", err.Error())
+		}
+		if req.URL == "" {
+			return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Request validation failed", "url is required")
+		}
+		ref, err = h.Service.ResolveRepository(c.UserContext(), req.URL)
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	c.Locals(repositoryLocalsKey, ref)
+	return c.Next()
+}
+
+// Resource is the RBAC resource of the repository found by Resolve. The
+// owner of a GitLab project is its full group path; numeric project IDs
+// have none.
+func Resource(c *fiber.Ctx) auth.Resource {
+	ref := repository(c)
+	resource := auth.Resource{
+		Provider: string(ref.Provider),
+		Repo:     ref.Name,
+	}
+	if strings.Contains(ref.Name, "/") {
+		resource.Owner = path.Dir(ref.Name)
+	}
+	return resource
+}
+
+func repository(c *fiber.Ctx) service.RepositoryRef {
+	ref, _ := c.Locals(repositoryLocalsKey).(service.RepositoryRef)
+	return ref
+}
+
+func (h *Handler) GetRepository(c *fiber.Ctx) error {
+	ref := repository(c)
+	repo, err := h.Service.GetRepository(c.UserContext(), ref.Provider, ref.Name)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, repo)
+}
+
+func (h *Handler) GetCommits(c *fiber.Ctx) error {
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
+	defer cancel()
+
+	req := new(CommitsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	ref := repository(c)
+	commits, total, filtered, err := h.Service.GetCommits(
+		ctx,
+		ref.Provider,
+		ref.Name,
+		req.GetSinceTime(),
+		req.GetUntilTime(),
+		req.AuthorFilter(),
+		req.GetOffset(),
+		req.GetPerPage(),
+	)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	if req.IncludeFiles {
+		if err := h.Service.LoadCommitFiles(ctx, ref.Provider, ref.Name, commits); err != nil {
+			return h.BaseHandler.HandleError(c, err)
+		}
+	}
+
+	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	pagination.Filtered = filtered
+	return h.BaseHandler.SendPaginatedResponse(c, commits, pagination)
+}
+
+// GetPullRequests serves GitHub pull requests and GitLab merge requests alike
+func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
+	defer cancel()
+
+	req := new(PullRequestsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	ref := repository(c)
+	prs, total, filtered, err := h.Service.GetPullRequests(
+		ctx,
+		ref.Provider,
+		ref.Name,
+		req.GetSinceTime(),
+		req.GetUntilTime(),
+		req.AuthorFilter(),
+		req.GetOffset(),
+		req.GetPerPage(),
+	)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	if req.IncludeFiles {
+		if err := h.Service.LoadPullRequestFiles(ctx, ref.Provider, ref.Name, prs); err != nil {
+			return h.BaseHandler.HandleError(c, err)
+		}
+	}
+
+	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	pagination.Filtered = filtered
+	return h.BaseHandler.SendPaginatedResponse(c, prs, pagination)
+}
+
+// GetCommitFiles returns every file changed by a commit
+func (h *Handler) GetCommitFiles(c *fiber.Ctx) error {
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
+	defer cancel()
+
+	req := new(CommitFilesRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	ref := repository(c)
+	files, err := h.Service.CommitFiles(ctx, ref.Provider, ref.Name, req.SHA)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	return h.BaseHandler.SendResponse(c, files)
+}
+
+// GetPullRequestFiles returns every file changed by a pull request or merge request
+func (h *Handler) GetPullRequestFiles(c *fiber.Ctx) error {
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
+	defer cancel()
+
+	req := new(PullRequestFilesRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	ref := repository(c)
+	files, err := h.Service.PullRequestFiles(ctx, ref.Provider, ref.Name, req.Number)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+	return h.BaseHandler.SendResponse(c, files)
+}
@@ -0,0 +1,35 @@
+package repos
+
+import (
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+)
+
+// RepositoryRequest names the repository by clone URL, web URL or
+// provider:path slug on the routes without a provider segment
+type RepositoryRequest struct {
+	URL string `query:"url"`
+}
+
+type CommitsRequest struct {
+	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
+	shared.PaginationRequest
+	// IncludeFiles loads the changed files of every commit on the page, one upstream request each
+	IncludeFiles bool `query:"include_files"`
+}
+
+type PullRequestsRequest struct {
+	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
+	shared.PaginationRequest
+	// IncludeFiles loads the changed files of every pull request on the page, one upstream request each
+	IncludeFiles bool `query:"include_files"`
+}
+
+type CommitFilesRequest struct {
+	SHA string `params:"sha" validate:"required,hexadecimal"`
+}
+
+type PullRequestFilesRequest struct {
+	Number int `params:"number" validate:"required,min=1"`
+}
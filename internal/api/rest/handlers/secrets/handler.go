@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"errors"
+	"strings"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/secrets"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler manages the encrypted secrets of the caller's tenant. Secrets are
+// namespaced by tenant, so a tenant admin can only see and rotate its own.
+type Handler struct {
+	Manager     *secrets.Manager
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(manager *secrets.Manager, log logger.Logger) *Handler {
+	return &Handler{
+		Manager:     manager,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+func (h *Handler) ListSecrets(c *fiber.Ctx) error {
+	rows, err := h.Manager.List(c.UserContext())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	prefix := tenant.IDFromContext(c.UserContext()) + "/"
+	response := make([]SecretResponse, 0, len(rows))
+	for _, row := range rows {
+		if strings.HasPrefix(row.Name, prefix) {
+			response = append(response, newSecretResponse(row, strings.TrimPrefix(row.Name, prefix)))
+		}
+	}
+
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// PutSecret creates or rotates a secret
+func (h *Handler) PutSecret(c *fiber.Ctx) error {
+	req := new(PutSecretRequest)
+	if err := h.BaseHandler.ParseBodyAndValidate(c, req); err != nil {
+		return err
+	}
+
+	row, err := h.Manager.Put(c.UserContext(), h.storedName(c, req.Name), req.Value)
+	if errors.Is(err, secrets.ErrNoMasterKey) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotImplemented, "secret_store_disabled", "Secret store has no master key configured", "")
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	h.BaseHandler.Log(c).Info("Secret stored",
+		logger.String("secret", row.Name),
+		logger.Int("version", row.Version),
+	)
+
+	return h.BaseHandler.SendResponse(c, newSecretResponse(row, req.Name))
+}
+
+func (h *Handler) DeleteSecret(c *fiber.Ctx) error {
+	req := new(SecretRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	err := h.Manager.Delete(c.UserContext(), h.storedName(c, req.Name))
+	if errors.Is(err, secrets.ErrNotFound) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotFound, "not_found", "Secret not found", req.Name)
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *Handler) storedName(c *fiber.Ctx, name string) string {
+	return tenant.IDFromContext(c.UserContext()) + "/" + name
+}
+
+func newSecretResponse(row *secrets.Row, name string) SecretResponse {
+	return SecretResponse{
+		Name:      name,
+		Reference: secrets.Reference{Backend: secrets.StoreBackendName, Name: row.Name}.String(),
+		Version:   row.Version,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package secrets
+
+import "time"
+
+type SecretRequest struct {
+	Name string `params:"name" validate:"required,max=100,hostname_rfc1123"`
+}
+
+type PutSecretRequest struct {
+	SecretRequest
+	Value string `json:"value" validate:"required"`
+}
+
+// SecretResponse describes a stored secret; the value is never returned
+type SecretResponse struct {
+	Name      string    `json:"name"`
+	Reference string    `json:"reference"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,110 @@
+package team
+
+import (
+	"errors"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	domain "devmetrics/internal/domain/team"
+	service "devmetrics/internal/services/team"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves team definitions and the admin endpoints that manage them
+type Handler struct {
+	Service     *service.Service
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
+	return &Handler{
+		Service:     service,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+func (h *Handler) ListTeams(c *fiber.Ctx) error {
+	teams, err := h.Service.List(c.UserContext())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	response := make([]TeamResponse, 0, len(teams))
+	for _, t := range teams {
+		response = append(response, newTeamResponse(t, nil))
+	}
+	return h.BaseHandler.SendResponse(c, response)
+}
+
+// GetTeam returns a team with its members resolved to people
+func (h *Handler) GetTeam(c *fiber.Ctx) error {
+	req := new(TeamRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	t, err := h.Service.Get(c.UserContext(), req.ID)
+	if err != nil {
+		return h.handleError(c, err, req.ID)
+	}
+	people, err := h.Service.People(c.UserContext(), t)
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, newTeamResponse(*t, people))
+}
+
+func (h *Handler) PutTeam(c *fiber.Ctx) error {
+	req := new(PutTeamRequest)
+	if err := h.BaseHandler.ParseBodyAndValidate(c, req); err != nil {
+		return err
+	}
+
+	t, err := h.Service.Put(c.UserContext(), req.team())
+	if err != nil {
+		return h.handleError(c, err, req.ID)
+	}
+
+	return h.BaseHandler.SendResponse(c, newTeamResponse(t, nil))
+}
+
+func (h *Handler) DeleteTeam(c *fiber.Ctx) error {
+	req := new(TeamRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	if err := h.Service.Delete(c.UserContext(), req.ID); err != nil {
+		return h.handleError(c, err, req.ID)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SyncTeam replaces a team's members with those of its GitHub team or GitLab group
+func (h *Handler) SyncTeam(c *fiber.Ctx) error {
+	req := new(TeamRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	t, err := h.Service.Sync(c.UserContext(), req.ID)
+	if err != nil {
+		return h.handleError(c, err, req.ID)
+	}
+
+	return h.BaseHandler.SendResponse(c, newTeamResponse(*t, nil))
+}
+
+func (h *Handler) handleError(c *fiber.Ctx, err error, id string) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotFound, "not_found", "Team not found", id)
+	case errors.Is(err, domain.ErrReadOnly):
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusConflict, "read_only", "Team is declared in the configuration and cannot be changed", id)
+	case errors.Is(err, service.ErrInvalidTeam):
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Invalid team", err.Error())
+	}
+	return h.BaseHandler.HandleError(c, err)
+}
@@ -0,0 +1,109 @@
+package team
+
+import (
+	"strings"
+	"time"
+
+	"devmetrics/internal/domain/identity"
+	domain "devmetrics/internal/domain/team"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+)
+
+type TeamRequest struct {
+	ID string `params:"id" json:"-" validate:"required"`
+}
+
+type PutTeamRequest struct {
+	ID   string `params:"id" json:"-" validate:"required,max=100,hostname_rfc1123"`
+	Name string `json:"name" validate:"max=200"`
+	// Members are person IDs, e-mail addresses, provider:login accounts or logins
+	Members      []string            `json:"members" validate:"dive,required,max=320"`
+	Repositories []RepositoryRequest `json:"repositories" validate:"dive"`
+	Sync         *SyncRequest        `json:"sync"`
+}
+
+type RepositoryRequest struct {
+	Provider string `json:"provider" validate:"required,oneof=github gitlab bitbucket"`
+	Name     string `json:"name" validate:"required"`
+}
+
+// SyncRequest names a GitHub team as "org/team-slug" or a GitLab group by ID or full path
+type SyncRequest struct {
+	Provider string `json:"provider" validate:"required,oneof=github gitlab"`
+	Group    string `json:"group" validate:"required"`
+}
+
+type TeamResponse struct {
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	Source       string               `json:"source"`
+	Members      []string             `json:"members"`
+	People       []PersonResponse     `json:"people,omitempty"`
+	Repositories []RepositoryResponse `json:"repositories"`
+	Sync         *SyncResponse        `json:"sync,omitempty"`
+	SyncedAt     *time.Time           `json:"synced_at,omitempty"`
+	UpdatedAt    *time.Time           `json:"updated_at,omitempty"`
+}
+
+// PersonResponse is a member resolved to a canonical person
+type PersonResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type RepositoryResponse struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+}
+
+type SyncResponse struct {
+	Provider string `json:"provider"`
+	Group    string `json:"group"`
+}
+
+func newTeamResponse(t domain.Team, people []identity.Person) TeamResponse {
+	response := TeamResponse{
+		ID:           t.ID,
+		Name:         t.Name,
+		Source:       string(t.Source),
+		Members:      t.Members,
+		Repositories: make([]RepositoryResponse, 0, len(t.Repositories)),
+		SyncedAt:     t.SyncedAt,
+	}
+	if response.Members == nil {
+		response.Members = []string{}
+	}
+	for _, person := range people {
+		response.People = append(response.People, PersonResponse{ID: person.ID, Name: person.Name})
+	}
+	for _, repo := range t.Repositories {
+		response.Repositories = append(response.Repositories, RepositoryResponse{Provider: string(repo.Provider), Name: repo.Name})
+	}
+	if t.Sync != nil {
+		response.Sync = &SyncResponse{Provider: string(t.Sync.Provider), Group: t.Sync.Group}
+	}
+	if !t.UpdatedAt.IsZero() {
+		response.UpdatedAt = &t.UpdatedAt
+	}
+	return response
+}
+
+func (r *PutTeamRequest) team() domain.Team {
+	t := domain.Team{
+		// Route parameters point into Fiber's reused buffers; the ID outlives the request
+		ID:      strings.Clone(r.ID),
+		Name:    r.Name,
+		Members: r.Members,
+	}
+	for _, repo := range r.Repositories {
+		t.Repositories = append(t.Repositories, tenant.Repository{
+			Provider: vcs.ProviderType(repo.Provider),
+			Name:     repo.Name,
+		})
+	}
+	if r.Sync != nil {
+		t.Sync = &domain.Sync{Provider: vcs.ProviderType(r.Sync.Provider), Group: r.Sync.Group}
+	}
+	return t
+}
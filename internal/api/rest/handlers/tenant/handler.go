@@ -0,0 +1,36 @@
+package tenant
+
+import (
+	"errors"
+
+	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	domain "devmetrics/internal/domain/tenant"
+	service "devmetrics/internal/services/tenant"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves information about the caller's tenant
+type Handler struct {
+	Service     *service.Service
+	BaseHandler shared.BaseHandler
+}
+
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
+	return &Handler{
+		Service:     service,
+		BaseHandler: shared.NewBaseHandler(log),
+	}
+}
+
+func (h *Handler) GetCurrent(c *fiber.Ctx) error {
+	t, err := h.Service.Current(c.UserContext())
+	if errors.Is(err, domain.ErrNotFound) {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusNotFound, "not_found", "Tenant not found", domain.IDFromContext(c.UserContext()))
+	}
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	return h.BaseHandler.SendResponse(c, newTenantResponse(t))
+}
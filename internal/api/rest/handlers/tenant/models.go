@@ -0,0 +1,32 @@
+package tenant
+
+import (
+	domain "devmetrics/internal/domain/tenant"
+)
+
+type TenantResponse struct {
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	Repositories []RepositoryResponse `json:"repositories"`
+}
+
+type RepositoryResponse struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+}
+
+func newTenantResponse(t *domain.Tenant) TenantResponse {
+	repositories := make([]RepositoryResponse, 0, len(t.Repositories))
+	for _, repo := range t.Repositories {
+		repositories = append(repositories, RepositoryResponse{
+			Provider: string(repo.Provider),
+			Name:     repo.Name,
+		})
+	}
+
+	return TenantResponse{
+		ID:           t.ID,
+		Name:         t.Name,
+		Repositories: repositories,
+	}
+}
@@ -2,8 +2,11 @@ package github
 
 import (
 	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/api/rest/middleware"
+	"devmetrics/internal/domain/auth"
 	domain "devmetrics/internal/domain/vcs"
 	service "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,10 +16,10 @@ type Handler struct {
 	BaseHandler shared.BaseHandler
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
 	return &Handler{
 		Service:     service,
-		BaseHandler: shared.NewBaseHandler(),
+		BaseHandler: shared.NewBaseHandler(log),
 	}
 }
 
@@ -27,7 +30,7 @@ func (h *Handler) GetRepository(c *fiber.Ctx) error {
 	}
 
 	repo, err := h.Service.GetRepository(
-		c.Context(),
+		c.UserContext(),
 		domain.ProviderGitHub,
 		fmt.Sprintf("%s/%s", req.Owner, req.Name),
 	)
@@ -39,7 +42,7 @@ func (h *Handler) GetRepository(c *fiber.Ctx) error {
 }
 
 func (h *Handler) GetCommits(c *fiber.Ctx) error {
-	ctx, cancel := shared.NewTimeoutContext(c.Context(), shared.DefaultTimeout)
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
 	defer cancel()
 
 	req := new(CommitsRequest)
@@ -47,12 +50,13 @@ func (h *Handler) GetCommits(c *fiber.Ctx) error {
 		return err
 	}
 
-	commits, total, err := h.Service.GetCommits(
+	commits, total, filtered, err := h.Service.GetCommits(
 		ctx,
 		domain.ProviderGitHub,
 		fmt.Sprintf("%s/%s", req.Owner, req.Name),
 		req.GetSinceTime(),
 		req.GetUntilTime(),
+		req.AuthorFilter(),
 		req.GetOffset(),
 		req.GetPerPage(),
 	)
@@ -61,11 +65,12 @@ func (h *Handler) GetCommits(c *fiber.Ctx) error {
 	}
 
 	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	pagination.Filtered = filtered
 	return h.BaseHandler.SendPaginatedResponse(c, commits, pagination)
 }
 
 func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
-	ctx, cancel := shared.NewTimeoutContext(c.Context(), shared.DefaultTimeout)
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
 	defer cancel()
 
 	req := new(PullRequestsRequest)
@@ -73,12 +78,13 @@ func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
 		return err
 	}
 
-	prs, total, err := h.Service.GetPullRequests(
+	prs, total, filtered, err := h.Service.GetPullRequests(
 		ctx,
 		domain.ProviderGitHub,
 		fmt.Sprintf("%s/%s", req.Owner, req.Name),
 		req.GetSinceTime(),
 		req.GetUntilTime(),
+		req.AuthorFilter(),
 		req.GetOffset(),
 		req.GetPerPage(),
 	)
@@ -87,5 +93,31 @@ func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
 	}
 
 	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	pagination.Filtered = filtered
 	return h.BaseHandler.SendPaginatedResponse(c, prs, pagination)
 }
+
+// ListRepositories lists the repositories of an organization or user
+func (h *Handler) ListRepositories(c *fiber.Ctx) error {
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
+	defer cancel()
+
+	req := new(ListRepositoriesRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	filter := req.Filter()
+	allowed := middleware.RepositoryAccess(c)
+	filter.Allow = func(repo domain.Repository) bool {
+		return allowed(auth.Resource{Provider: string(domain.ProviderGitHub), Owner: req.Owner, Repo: repo.Path})
+	}
+
+	repos, total, err := h.Service.ListRepositories(ctx, domain.ProviderGitHub, req.Owner, filter, req.GetOffset(), req.GetPerPage())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	return h.BaseHandler.SendPaginatedResponse(c, repos, pagination)
+}
@@ -12,12 +12,20 @@ type RepositoryRequest struct {
 type CommitsRequest struct {
 	RepositoryRequest
 	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
 	shared.PaginationRequest
 }
 
 type PullRequestsRequest struct {
 	RepositoryRequest
 	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
 	shared.PaginationRequest
 	Status string `query:"status" validate:"omitempty,oneof=open closed merged all"`
 }
+
+type ListRepositoriesRequest struct {
+	Owner string `params:"owner" validate:"required"`
+	shared.RepositoryFilterRequest
+	shared.PaginationRequest
+}
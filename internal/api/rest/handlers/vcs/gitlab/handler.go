@@ -2,10 +2,14 @@ package gitlab
 
 import (
 	"devmetrics/internal/api/rest/handlers/vcs/shared"
+	"devmetrics/internal/api/rest/middleware"
+	"devmetrics/internal/domain/auth"
 	domain "devmetrics/internal/domain/vcs"
 	service "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"net/url"
 )
 
 type Handler struct {
@@ -13,10 +17,10 @@ type Handler struct {
 	BaseHandler shared.BaseHandler
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(service *service.Service, log logger.Logger) *Handler {
 	return &Handler{
 		Service:     service,
-		BaseHandler: shared.NewBaseHandler(),
+		BaseHandler: shared.NewBaseHandler(log),
 	}
 }
 
@@ -27,7 +31,7 @@ func (h *Handler) GetRepository(c *fiber.Ctx) error {
 	}
 
 	repo, err := h.Service.GetRepository(
-		c.Context(),
+		c.UserContext(),
 		domain.ProviderGitLab,
 		fmt.Sprint(req.ProjectID),
 	)
@@ -39,7 +43,7 @@ func (h *Handler) GetRepository(c *fiber.Ctx) error {
 }
 
 func (h *Handler) GetCommits(c *fiber.Ctx) error {
-	ctx, cancel := shared.NewTimeoutContext(c.Context(), shared.DefaultTimeout)
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
 	defer cancel()
 
 	req := new(CommitsRequest)
@@ -47,12 +51,13 @@ func (h *Handler) GetCommits(c *fiber.Ctx) error {
 		return err
 	}
 
-	commits, total, err := h.Service.GetCommits(
+	commits, total, filtered, err := h.Service.GetCommits(
 		ctx,
 		domain.ProviderGitLab,
 		fmt.Sprint(req.ProjectID),
 		req.GetSinceTime(),
 		req.GetUntilTime(),
+		req.AuthorFilter(),
 		req.GetOffset(),
 		req.GetPerPage(),
 	)
@@ -61,11 +66,12 @@ func (h *Handler) GetCommits(c *fiber.Ctx) error {
 	}
 
 	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	pagination.Filtered = filtered
 	return h.BaseHandler.SendPaginatedResponse(c, commits, pagination)
 }
 
 func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
-	ctx, cancel := shared.NewTimeoutContext(c.Context(), shared.DefaultTimeout)
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
 	defer cancel()
 
 	req := new(PullRequestsRequest)
@@ -73,12 +79,13 @@ func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
 		return err
 	}
 
-	prs, total, err := h.Service.GetPullRequests(
+	prs, total, filtered, err := h.Service.GetPullRequests(
 		ctx,
 		domain.ProviderGitLab,
 		fmt.Sprint(req.ProjectID),
 		req.GetSinceTime(),
 		req.GetUntilTime(),
+		req.AuthorFilter(),
 		req.GetOffset(),
 		req.GetPerPage(),
 	)
@@ -87,5 +94,36 @@ func (h *Handler) GetPullRequests(c *fiber.Ctx) error {
 	}
 
 	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	pagination.Filtered = filtered
 	return h.BaseHandler.SendPaginatedResponse(c, prs, pagination)
 }
+
+// ListProjects lists the repositories of a group and its subgroups, or of a user
+func (h *Handler) ListProjects(c *fiber.Ctx) error {
+	ctx, cancel := shared.NewTimeoutContext(c.UserContext(), shared.DefaultTimeout)
+	defer cancel()
+
+	req := new(ListProjectsRequest)
+	if err := h.BaseHandler.ParseAndValidate(c, req); err != nil {
+		return err
+	}
+
+	group, err := url.PathUnescape(req.Group)
+	if err != nil {
+		return h.BaseHandler.ErrorResponse(c, fiber.StatusBadRequest, "invalid_parameters", "Invalid group path", err.Error())
+	}
+
+	filter := req.Filter()
+	allowed := middleware.RepositoryAccess(c)
+	filter.Allow = func(repo domain.Repository) bool {
+		return allowed(auth.Resource{Provider: string(domain.ProviderGitLab), Owner: group, Repo: repo.Path})
+	}
+
+	repos, total, err := h.Service.ListRepositories(ctx, domain.ProviderGitLab, group, filter, req.GetOffset(), req.GetPerPage())
+	if err != nil {
+		return h.BaseHandler.HandleError(c, err)
+	}
+
+	pagination := shared.NewPaginationMeta(req.GetPage(), req.GetPerPage(), total)
+	return h.BaseHandler.SendPaginatedResponse(c, repos, pagination)
+}
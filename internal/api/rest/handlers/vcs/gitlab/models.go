@@ -11,12 +11,21 @@ type RepositoryRequest struct {
 type CommitsRequest struct {
 	RepositoryRequest
 	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
 	shared.PaginationRequest
 }
 
 type PullRequestsRequest struct {
 	RepositoryRequest
 	shared.TimeRangeRequest
+	shared.AuthorFilterRequest
 	shared.PaginationRequest
 	Status string `query:"status" validate:"omitempty,oneof=all open closed merged" default:"all"`
 }
+
+type ListProjectsRequest struct {
+	// Group is a numeric ID or a URL-encoded full path such as "group%2Fsubgroup"
+	Group string `params:"group" validate:"required"`
+	shared.RepositoryFilterRequest
+	shared.PaginationRequest
+}
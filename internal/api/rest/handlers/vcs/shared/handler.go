@@ -1,40 +1,88 @@
 package shared
 
 import (
+	"devmetrics/internal/api/rest/apierror"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/internal/secrets"
+	"devmetrics/pkg/logger"
+	"devmetrics/pkg/requestid"
 	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"log"
+	"strings"
 	"time"
 )
 
 type BaseHandler struct {
 	Validator *validator.Validate
+	Logger    logger.Logger
 }
 
-func NewBaseHandler() BaseHandler {
+func NewBaseHandler(log logger.Logger) BaseHandler {
+	v := validator.New()
+	v.RegisterStructValidation(validateTimeRange, TimeRangeRequest{})
 	return BaseHandler{
-		Validator: validator.New(),
+		Validator: v,
+		Logger:    log,
 	}
 }
 
-// ParseAndValidate parses and validates request data
+// validateTimeRange compares since and until only when both are set
+func validateTimeRange(sl validator.StructLevel) {
+	r := sl.Current().Interface().(TimeRangeRequest)
+	if r.Since != nil && r.Until != nil && r.Since.After(*r.Until) {
+		sl.ReportError(r.Since, "Since", "since", "ltefield", "Until")
+	}
+}
+
+// Log returns the request-scoped logger, falling back to the handler logger
+func (h *BaseHandler) Log(c *fiber.Ctx) logger.Logger {
+	return logger.FromFiber(c, h.Logger)
+}
+
+// ParseAndValidate parses and validates request data. When the request is
+// invalid a 400 response is sent and apierror.ErrResponded returned.
 func (h *BaseHandler) ParseAndValidate(c *fiber.Ctx, req interface{}) error {
 	if err := c.ParamsParser(req); err != nil {
-		return h.ErrorResponse(c, fiber.StatusBadRequest, "invalid_parameters", "Failed to parse parameters", err.Error())
+		return h.reject(c, fiber.StatusBadRequest, "invalid_parameters", "Failed to parse parameters", err.Error())
 	}
 
 	if err := c.QueryParser(req); err != nil {
-		return h.ErrorResponse(c, fiber.StatusBadRequest, "invalid_query", "Failed to parse query parameters", err.Error())
+		return h.reject(c, fiber.StatusBadRequest, "invalid_query", "Failed to parse query parameters", err.Error())
+	}
+
+	if err := h.Validator.Struct(req); err != nil {
+		return h.reject(c, fiber.StatusBadRequest, "validation_failed", "Request validation failed", err.Error())
+	}
+
+	return nil
+}
+
+// ParseBodyAndValidate parses the route parameters and JSON body and validates them
+func (h *BaseHandler) ParseBodyAndValidate(c *fiber.Ctx, req interface{}) error {
+	if err := c.ParamsParser(req); err != nil {
+		return h.reject(c, fiber.StatusBadRequest, "invalid_parameters", "Failed to parse parameters", err.Error())
+	}
+
+	if err := c.BodyParser(req); err != nil {
+		return h.reject(c, fiber.StatusBadRequest, "invalid_body", "Failed to parse request body", err.Error())
 	}
 
 	if err := h.Validator.Struct(req); err != nil {
-		return h.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Request validation failed", err.Error())
+		return h.reject(c, fiber.StatusBadRequest, "validation_failed", "Request validation failed", err.Error())
 	}
 
 	return nil
 }
 
+// reject sends an error response and stops the handler chain
+func (h *BaseHandler) reject(c *fiber.Ctx, status int, code, message, details string) error {
+	if err := h.ErrorResponse(c, status, code, message, details); err != nil {
+		return err
+	}
+	return apierror.ErrResponded
+}
+
 // SendResponse sends a successful response
 func (h *BaseHandler) SendResponse(c *fiber.Ctx, data interface{}) error {
 	return c.JSON(Response{
@@ -42,6 +90,13 @@ func (h *BaseHandler) SendResponse(c *fiber.Ctx, data interface{}) error {
 	})
 }
 
+// SendCreatedResponse sends a 201 response
+func (h *BaseHandler) SendCreatedResponse(c *fiber.Ctx, data interface{}) error {
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Data: data,
+	})
+}
+
 func (h *BaseHandler) SendPaginatedResponse(c *fiber.Ctx, data interface{}, pagination PaginationMeta) error {
 	return c.JSON(Response{
 		Data:       data,
@@ -51,25 +106,36 @@ func (h *BaseHandler) SendPaginatedResponse(c *fiber.Ctx, data interface{}, pagi
 
 // ErrorResponse creates and sends an error response
 func (h *BaseHandler) ErrorResponse(c *fiber.Ctx, status int, code, message, details string) error {
-	log.Printf("Error response: %s - %s - %s", code, message, details)
+	details = secrets.Redact(details)
+	h.Log(c).Warn("Error response",
+		logger.String("code", code),
+		logger.String("message", message),
+		logger.String("details", details),
+	)
 	return c.Status(status).JSON(Response{
 		Error: &ErrorResponse{
-			Code:    code,
-			Message: message,
-			Details: details,
+			Code:      code,
+			Message:   message,
+			Details:   details,
+			RequestID: requestid.FromContext(c.UserContext()),
 		},
 	})
 }
 
-// HandleError handles different types of errors and returns appropriate responses
+// HandleError maps domain errors to HTTP statuses and stable error codes
 func (h *BaseHandler) HandleError(c *fiber.Ctx, err error) error {
 	var validationErrors validator.ValidationErrors
 	if errors.As(err, &validationErrors) {
 		return h.ErrorResponse(c, fiber.StatusBadRequest, "validation_failed", "Validation failed", validationErrors.Error())
 	}
 
-	log.Printf("Internal error: %v", err)
-	return h.ErrorResponse(c, fiber.StatusInternalServerError, "internal_error", "Internal server error", err.Error())
+	mapping := apierror.Resolve(err)
+	if mapping.Status >= fiber.StatusInternalServerError {
+		h.Log(c).Error("Request failed", logger.Error(err))
+	}
+
+	apierror.SetRetryAfter(c, mapping)
+	return h.ErrorResponse(c, mapping.Status, mapping.Code, mapping.Message, err.Error())
 }
 
 // GetSinceTime Time helper methods
@@ -86,3 +152,26 @@ func (r *TimeRangeRequest) GetUntilTime() time.Time {
 	}
 	return time.Now()
 }
+
+// Filter converts the query parameters into a repository filter. Topics are comma-separated.
+func (r *RepositoryFilterRequest) Filter() vcs.RepositoryFilter {
+	filter := vcs.RepositoryFilter{
+		IncludeArchived: r.IncludeArchived,
+		IncludeForks:    r.IncludeForks,
+		Visibility:      r.Visibility,
+	}
+	for _, topic := range strings.Split(r.Topics, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			filter.Topics = append(filter.Topics, topic)
+		}
+	}
+	if r.ActiveSince != nil {
+		filter.ActiveSince = *r.ActiveSince
+	}
+	return filter
+}
+
+// AuthorFilter converts the query parameters into an author filter
+func (r *AuthorFilterRequest) AuthorFilter() vcs.AuthorFilter {
+	return vcs.AuthorFilter{IncludeBots: r.IncludeBots}
+}
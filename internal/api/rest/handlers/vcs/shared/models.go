@@ -2,11 +2,27 @@ package shared
 
 import "time"
 
+// TimeRangeRequest is an optional time range; since may not be after until
 type TimeRangeRequest struct {
-	Since *time.Time `query:"since" validate:"omitempty,ltefield=Until"`
+	Since *time.Time `query:"since" validate:"omitempty"`
 	Until *time.Time `query:"until" validate:"omitempty"`
 }
 
+// RepositoryFilterRequest holds the repository discovery filters
+type RepositoryFilterRequest struct {
+	IncludeArchived bool       `query:"include_archived"`
+	IncludeForks    bool       `query:"include_forks"`
+	Visibility      string     `query:"visibility" validate:"omitempty,oneof=public private internal"`
+	Topics          string     `query:"topics"`
+	ActiveSince     *time.Time `query:"active_since" validate:"omitempty"`
+}
+
+// AuthorFilterRequest selects commits and pull requests by author. Bots are
+// left out unless include_bots is set; see PaginationMeta for how they count.
+type AuthorFilterRequest struct {
+	IncludeBots bool `query:"include_bots"`
+}
+
 type Response struct {
 	Data       interface{}     `json:"data,omitempty"`
 	Error      *ErrorResponse  `json:"error,omitempty"`
@@ -19,7 +35,8 @@ type PaginatedResponse struct {
 }
 
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   string `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
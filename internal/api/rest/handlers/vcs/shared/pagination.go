@@ -40,13 +40,17 @@ func (p *PaginationRequest) GetOffset() int {
 	return (p.GetPage() - 1) * p.GetPerPage()
 }
 
-// PaginationMeta holds metadata about the pagination
+// PaginationMeta holds metadata about the pagination. On repository
+// listings, TotalItems and TotalPages count the items upstream before the
+// author filter, so a page may hold fewer than PerPage items even when more
+// follow; Filtered is how many items the filter left out of this page.
 type PaginationMeta struct {
 	CurrentPage int   `json:"current_page"`
 	PerPage     int   `json:"per_page"`
 	TotalItems  int64 `json:"total_items"`
 	TotalPages  int   `json:"total_pages"`
 	HasMore     bool  `json:"has_more"`
+	Filtered    int   `json:"filtered"`
 }
 
 // NewPaginationMeta creates a new PaginationMeta instance
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"devmetrics/internal/api/rest/apierror"
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/auth"
+	"devmetrics/internal/domain/tenant"
+	authservice "devmetrics/internal/services/auth"
+	tenantservice "devmetrics/internal/services/tenant"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/time/rate"
+)
+
+// PrincipalLocalsKey is the fiber.Ctx locals key holding the authenticated principal
+const PrincipalLocalsKey = "principal"
+
+// APIKeyHeader is an alternative to the Authorization header for API keys
+const APIKeyHeader = "X-API-Key"
+
+// TenantHeader selects the tenant when authentication is disabled. With
+// authentication enabled the tenant always comes from the credentials.
+const TenantHeader = "X-Tenant-ID"
+
+// Authenticator authenticates requests with API keys or identity provider
+// JWTs, enforces per-key rate limits and writes an audit log entry for every
+// authenticated call
+type Authenticator struct {
+	enabled bool
+	keys    *authservice.Service
+	tokens  *authservice.TokenVerifier
+	tenants *tenantservice.Service
+	logger  logger.Logger
+
+	mu       sync.Mutex
+	limiters map[string]*keyLimiter
+}
+
+// keyLimiter is the token bucket of a key and the limit it was built for
+type keyLimiter struct {
+	*rate.Limiter
+	perMinute int
+}
+
+func NewAuthenticator(
+	cfg config.AuthConfig,
+	keys *authservice.Service,
+	tokens *authservice.TokenVerifier,
+	tenants *tenantservice.Service,
+	log logger.Logger,
+) *Authenticator {
+	if !cfg.Enabled {
+		log.Warn("Authentication is disabled, the API is open to anyone who can reach it")
+	}
+
+	a := &Authenticator{
+		enabled:  cfg.Enabled,
+		keys:     keys,
+		tokens:   tokens,
+		tenants:  tenants,
+		logger:   log,
+		limiters: make(map[string]*keyLimiter),
+	}
+	keys.OnRevoke(a.forget)
+	return a
+}
+
+// Authenticate resolves the caller of the request. When authentication is
+// disabled every request runs as an anonymous principal with all scopes.
+func (a *Authenticator) Authenticate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !a.enabled {
+			tenantID := c.Get(TenantHeader, tenant.DefaultID)
+			if !a.tenants.Exists(tenantID) {
+				return fiber.NewError(fiber.StatusNotFound, "Unknown tenant: "+tenantID)
+			}
+
+			setPrincipal(c, &auth.Principal{
+				Subject:   "anonymous",
+				TenantID:  tenantID,
+				Scopes:    auth.AllScopes,
+				Anonymous: true,
+			})
+			return c.Next()
+		}
+
+		secret := credentials(c)
+		if secret == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="devmetrics"`)
+			return fiber.NewError(fiber.StatusUnauthorized, "Missing credentials")
+		}
+
+		if a.tokens.Enabled() && authservice.LooksLikeJWT(secret) {
+			principal, err := a.tokens.Verify(c.UserContext(), secret)
+			if err != nil {
+				logger.FromFiber(c, a.logger).Info("Rejected bearer token", logger.Error(err))
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="devmetrics", error="invalid_token"`)
+				return fiber.NewError(fiber.StatusUnauthorized, "Invalid bearer token")
+			}
+			if principal.TenantID == "" {
+				// Tokens only fall back to the default tenant when it is the only one
+				if a.tenants.MultiTenant() {
+					logger.FromFiber(c, a.logger).Info("Rejected bearer token without tenant claim",
+						logger.String("subject", principal.Subject),
+					)
+					c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="devmetrics", error="invalid_token"`)
+					return fiber.NewError(fiber.StatusUnauthorized, "Bearer token has no tenant claim")
+				}
+				principal.TenantID = tenant.DefaultID
+			}
+			if !a.tenants.Exists(principal.TenantID) {
+				return fiber.NewError(fiber.StatusForbidden, "Unknown tenant: "+principal.TenantID)
+			}
+
+			setPrincipal(c, principal)
+			return a.audit(c, principal)
+		}
+
+		principal, key, err := a.keys.Authenticate(c.UserContext(), secret)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidKey) {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="devmetrics", error="invalid_token"`)
+				return fiber.NewError(fiber.StatusUnauthorized, "Invalid API key")
+			}
+			return err
+		}
+
+		if limiter := a.limiter(key); limiter != nil {
+			reservation := limiter.Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				reservation.Cancel()
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				return fiber.NewError(fiber.StatusTooManyRequests, "API key rate limit exceeded")
+			}
+		}
+
+		setPrincipal(c, principal)
+		return a.audit(c, principal)
+	}
+}
+
+// audit runs the rest of the chain and records which key made which call
+func (a *Authenticator) audit(c *fiber.Ctx, principal *auth.Principal) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil && !errors.Is(err, apierror.ErrResponded) {
+		status = apierror.Resolve(err).Status
+	}
+
+	logger.FromFiber(c, a.logger).Info("API call",
+		logger.String("audit", "api_call"),
+		logger.String("subject", principal.Subject),
+		logger.String("key_id", principal.KeyID),
+		logger.String("key_name", principal.Name),
+		logger.Any("roles", principal.Roles),
+		logger.String("route", c.Route().Path),
+		logger.Int("status", status),
+		logger.Duration("duration", time.Since(start)),
+	)
+
+	return err
+}
+
+// limiter returns the token bucket for a key, or nil when the key is
+// unlimited. The bucket is rebuilt when the key's limit has changed.
+func (a *Authenticator) limiter(key *auth.APIKey) *rate.Limiter {
+	perMinute := a.keys.RateLimit(key)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if perMinute <= 0 {
+		delete(a.limiters, key.ID)
+		return nil
+	}
+	limiter, ok := a.limiters[key.ID]
+	if !ok || limiter.perMinute != perMinute {
+		limiter = &keyLimiter{Limiter: rate.NewLimiter(rate.Limit(float64(perMinute)/60), perMinute), perMinute: perMinute}
+		a.limiters[key.ID] = limiter
+	}
+	return limiter.Limiter
+}
+
+// forget drops the token bucket of a revoked key
+func (a *Authenticator) forget(keyID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.limiters, keyID)
+}
+
+// RequireScope rejects requests whose principal lacks the given scope
+func RequireScope(scope auth.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+		}
+		if !principal.HasScope(scope) {
+			return fiber.NewError(fiber.StatusForbidden, "Missing required scope: "+string(scope))
+		}
+		return c.Next()
+	}
+}
+
+// GetPrincipal returns the principal set by Authenticate
+func GetPrincipal(c *fiber.Ctx) *auth.Principal {
+	if principal, ok := c.Locals(PrincipalLocalsKey).(*auth.Principal); ok {
+		return principal
+	}
+	principal, _ := auth.PrincipalFromContext(c.UserContext())
+	return principal
+}
+
+// setPrincipal stores the principal and scopes the request to its tenant
+func setPrincipal(c *fiber.Ctx, principal *auth.Principal) {
+	c.Locals(PrincipalLocalsKey, principal)
+
+	ctx := auth.WithPrincipal(c.UserContext(), principal)
+	ctx = tenant.WithID(ctx, principal.TenantID)
+	ctx = logger.WithContext(ctx, logger.FromFiber(c, nil).With(logger.String("tenant", principal.TenantID)))
+	c.SetUserContext(ctx)
+	c.Locals(logger.LocalsKey, logger.FromContext(ctx, nil))
+}
+
+// credentials extracts an API key or JWT from the Authorization or X-API-Key header
+func credentials(c *fiber.Ctx) string {
+	if key := c.Get(APIKeyHeader); key != "" {
+		return key
+	}
+
+	header := c.Get(fiber.HeaderAuthorization)
+	if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
@@ -0,0 +1,287 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"devmetrics/internal/adapters/storage/memory"
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/auth"
+	"devmetrics/internal/domain/tenant"
+	authservice "devmetrics/internal/services/auth"
+	tenantservice "devmetrics/internal/services/tenant"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testIssuer = "https://idp.example.com"
+
+type authApp struct {
+	*fiber.App
+	authenticator *Authenticator
+	keys          *authservice.Service
+	store         *memory.KeyStore
+	signing       *ecdsa.PrivateKey
+}
+
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
+
+// newAuthApp serves /vcs and /admin behind an Authenticator with the given
+// keys and tenants besides the default one; tokens signed by app.signing
+// verify when OIDC is enabled
+func newAuthApp(t *testing.T, authCfg config.AuthConfig, tenants ...config.TenantConfig) authApp {
+	t.Helper()
+	cfg := &config.Config{
+		Auth:    authCfg,
+		Tenants: append([]config.TenantConfig{{ID: config.DefaultTenantID}}, tenants...),
+	}
+	store := memory.NewKeyStore()
+	keys, err := authservice.NewService(cfg, store, logger.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signing, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := authservice.NewTokenVerifier(authCfg, staticKeys{"k1": &signing.PublicKey}, authservice.DefaultPolicy())
+	authenticator := NewAuthenticator(authCfg, keys, tokens, tenantservice.NewService(cfg), logger.NewNop())
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(authenticator.Authenticate())
+	ok := func(c *fiber.Ctx) error { return c.SendString(GetPrincipal(c).Subject) }
+	app.Get("/vcs", RequireScope(auth.ScopeReadVCS), ok)
+	app.Get("/metrics", RequireScope(auth.ScopeReadMetrics), ok)
+	app.Get("/admin", RequireScope(auth.ScopeAdmin), ok)
+	app.Get("/tenant", func(c *fiber.Ctx) error { return c.SendString(tenant.IDFromContext(c.UserContext())) })
+	return authApp{App: app, authenticator: authenticator, keys: keys, store: store, signing: signing}
+}
+
+// token signs a token for user u-1 with the given claims
+func (a authApp) token(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	claims["iss"] = testIssuer
+	claims["sub"] = "u-1"
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(a.signing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func staticKey(name, secret string, scopes ...string) config.APIKeyConfig {
+	return config.APIKeyConfig{Name: name, Hash: auth.HashKey(secret), Scopes: scopes}
+}
+
+func get(t *testing.T, app *fiber.App, path string, headers map[string]string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(fiber.MethodGet, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestAuthenticate(t *testing.T) {
+	app := newAuthApp(t, config.AuthConfig{
+		Enabled: true,
+		APIKeys: []config.APIKeyConfig{
+			staticKey("viewer", "dm_viewer", "read:vcs"),
+			staticKey("metrics", "dm_metrics", "read:metrics"),
+			staticKey("admin", "dm_admin", "admin"),
+		},
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{"missing credentials", "/vcs", nil, fiber.StatusUnauthorized},
+		{"unknown key", "/vcs", map[string]string{"Authorization": "Bearer dm_other"}, fiber.StatusUnauthorized},
+		{"bearer key", "/vcs", map[string]string{"Authorization": "Bearer dm_viewer"}, fiber.StatusOK},
+		{"bearer scheme is case-insensitive", "/vcs", map[string]string{"Authorization": "bearer dm_viewer"}, fiber.StatusOK},
+		{"X-API-Key header", "/vcs", map[string]string{APIKeyHeader: "dm_viewer"}, fiber.StatusOK},
+		{"basic scheme", "/vcs", map[string]string{"Authorization": "Basic dm_viewer"}, fiber.StatusUnauthorized},
+		{"missing scope", "/admin", map[string]string{APIKeyHeader: "dm_viewer"}, fiber.StatusForbidden},
+		{"read:vcs doesn't grant metrics", "/metrics", map[string]string{APIKeyHeader: "dm_viewer"}, fiber.StatusForbidden},
+		{"read:metrics key", "/metrics", map[string]string{APIKeyHeader: "dm_metrics"}, fiber.StatusOK},
+		{"admin implies every scope", "/vcs", map[string]string{APIKeyHeader: "dm_admin"}, fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := get(t, app.App, tt.path, tt.headers).StatusCode; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateToken(t *testing.T) {
+	app := newAuthApp(t, config.AuthConfig{
+		Enabled: true,
+		OIDC:    config.OIDCConfig{Enabled: true, Issuer: testIssuer, RolesClaim: "roles"},
+	})
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"viewer token", "/vcs", app.token(t, jwt.MapClaims{"roles": "viewer"}), fiber.StatusOK},
+		{"viewer token on admin route", "/admin", app.token(t, jwt.MapClaims{"roles": "viewer"}), fiber.StatusForbidden},
+		{"admin token", "/admin", app.token(t, jwt.MapClaims{"roles": "admin"}), fiber.StatusOK},
+		{"token without roles", "/vcs", app.token(t, jwt.MapClaims{}), fiber.StatusForbidden},
+		{"tampered token", "/vcs", app.token(t, jwt.MapClaims{"roles": "viewer"}) + "x", fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"Authorization": "Bearer " + tt.token}
+			if got := get(t, app.App, tt.path, headers).StatusCode; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateTokenTenant(t *testing.T) {
+	authCfg := config.AuthConfig{
+		Enabled: true,
+		OIDC:    config.OIDCConfig{Enabled: true, Issuer: testIssuer, RolesClaim: "roles", TenantClaim: "tenant"},
+	}
+	single := newAuthApp(t, authCfg)
+	multi := newAuthApp(t, authCfg, config.TenantConfig{ID: "acme"})
+
+	tests := []struct {
+		name   string
+		app    authApp
+		claims jwt.MapClaims
+		want   int
+	}{
+		{"single tenant, no claim uses the default tenant", single, jwt.MapClaims{"roles": "viewer"}, fiber.StatusOK},
+		{"single tenant, default claim", single, jwt.MapClaims{"roles": "viewer", "tenant": "default"}, fiber.StatusOK},
+		{"single tenant, unknown tenant", single, jwt.MapClaims{"roles": "viewer", "tenant": "acme"}, fiber.StatusForbidden},
+		{"multi tenant, no claim is rejected", multi, jwt.MapClaims{"roles": "viewer"}, fiber.StatusUnauthorized},
+		{"multi tenant, non-string claim is rejected", multi, jwt.MapClaims{"roles": "viewer", "tenant": 1}, fiber.StatusUnauthorized},
+		{"multi tenant, tenant claim", multi, jwt.MapClaims{"roles": "viewer", "tenant": "acme"}, fiber.StatusOK},
+		{"multi tenant, default claim", multi, jwt.MapClaims{"roles": "viewer", "tenant": "default"}, fiber.StatusOK},
+		{"multi tenant, unknown tenant", multi, jwt.MapClaims{"roles": "viewer", "tenant": "other"}, fiber.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"Authorization": "Bearer " + tt.app.token(t, tt.claims)}
+			if got := get(t, tt.app.App, "/vcs", headers).StatusCode; got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateTenantScope(t *testing.T) {
+	acmeKey := staticKey("ci", "dm_acme", "read:vcs")
+	enabled := newAuthApp(t,
+		config.AuthConfig{Enabled: true, APIKeys: []config.APIKeyConfig{staticKey("ci", "dm_default", "read:vcs")}},
+		config.TenantConfig{ID: "acme", APIKeys: []config.APIKeyConfig{acmeKey}},
+	)
+	disabled := newAuthApp(t, config.AuthConfig{}, config.TenantConfig{ID: "acme"})
+
+	tests := []struct {
+		name       string
+		app        authApp
+		headers    map[string]string
+		wantStatus int
+		wantTenant string
+	}{
+		{"default key", enabled, map[string]string{APIKeyHeader: "dm_default"}, fiber.StatusOK, "default"},
+		{"tenant key", enabled, map[string]string{APIKeyHeader: "dm_acme"}, fiber.StatusOK, "acme"},
+		{"header can't switch tenants", enabled, map[string]string{APIKeyHeader: "dm_default", TenantHeader: "acme"}, fiber.StatusOK, "default"},
+		{"disabled, no header", disabled, nil, fiber.StatusOK, "default"},
+		{"disabled, header", disabled, map[string]string{TenantHeader: "acme"}, fiber.StatusOK, "acme"},
+		{"disabled, unknown tenant", disabled, map[string]string{TenantHeader: "other"}, fiber.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, tt.app.App, "/tenant", tt.headers)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantTenant == "" {
+				return
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.wantTenant {
+				t.Errorf("tenant = %q, want %q", body, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestAuthenticateDisabled(t *testing.T) {
+	app := newAuthApp(t, config.AuthConfig{})
+	if got := get(t, app.App, "/admin", nil).StatusCode; got != fiber.StatusOK {
+		t.Errorf("status = %d, want %d", got, fiber.StatusOK)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	app := newAuthApp(t, config.AuthConfig{Enabled: true, DefaultRateLimit: 600})
+	ctx := context.Background()
+	secret, key, err := app.keys.CreateKey(ctx, config.DefaultTenantID, "ci", []auth.Scope{auth.ScopeReadVCS}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]string{APIKeyHeader: secret}
+
+	if got := get(t, app.App, "/vcs", headers).StatusCode; got != fiber.StatusOK {
+		t.Fatalf("first request: status = %d, want %d", got, fiber.StatusOK)
+	}
+	if resp := get(t, app.App, "/vcs", headers); resp.StatusCode != fiber.StatusTooManyRequests || resp.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Fatalf("second request: status = %d, Retry-After %q, want 429 with Retry-After", resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter))
+	}
+
+	// A changed limit replaces the exhausted bucket
+	key.RateLimit = 120
+	if err := app.store.Update(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, app.App, "/vcs", headers).StatusCode; got != fiber.StatusOK {
+		t.Errorf("after raising the limit: status = %d, want %d", got, fiber.StatusOK)
+	}
+
+	if _, err := app.keys.RevokeKey(ctx, config.DefaultTenantID, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := app.authenticator.limiters[key.ID]; ok {
+		t.Error("the bucket of a revoked key was kept")
+	}
+	if got := get(t, app.App, "/vcs", headers).StatusCode; got != fiber.StatusUnauthorized {
+		t.Errorf("revoked key: status = %d, want %d", got, fiber.StatusUnauthorized)
+	}
+}
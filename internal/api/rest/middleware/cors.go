@@ -5,10 +5,11 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func Cors() fiber.Handler {
+func Cors(allowOrigins string) fiber.Handler {
 	return cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+		AllowOrigins:  allowOrigins,
+		AllowMethods:  "GET,POST,PUT,DELETE,OPTIONS",
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,X-Request-ID,X-API-Key,X-Tenant-ID",
+		ExposeHeaders: "X-Request-ID",
 	})
 }
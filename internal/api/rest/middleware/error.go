@@ -2,18 +2,28 @@ package middleware
 
 import (
 	"errors"
+
+	"devmetrics/internal/api/rest/apierror"
+	"devmetrics/internal/secrets"
 	"github.com/gofiber/fiber/v2"
 )
 
+// ErrorHandler is the Fiber fallback error handler. It renders errors that
+// escaped the handlers in the same shape as shared.ErrorResponse.
 func ErrorHandler(c *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-
-	var e *fiber.Error
-	if errors.As(err, &e) {
-		code = e.Code
+	if errors.Is(err, apierror.ErrResponded) {
+		return nil
 	}
 
-	return c.Status(code).JSON(fiber.Map{
-		"error": err.Error(),
+	mapping := apierror.Resolve(err)
+	apierror.SetRetryAfter(c, mapping)
+
+	return c.Status(mapping.Status).JSON(fiber.Map{
+		"error": fiber.Map{
+			"code":       mapping.Code,
+			"message":    mapping.Message,
+			"details":    secrets.Redact(err.Error()),
+			"request_id": GetRequestID(c),
+		},
 	})
 }
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"devmetrics/internal/domain/auth"
+	"devmetrics/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// repositoryAccessLocalsKey holds the RBAC check listing handlers apply to their results
+const repositoryAccessLocalsKey = "repository_access"
+
+// ResourceFunc extracts the repository targeted by a request from its route parameters
+type ResourceFunc func(c *fiber.Ctx) auth.Resource
+
+// Authorize enforces the RBAC policy for principals that carry roles. API key
+// and anonymous principals are governed by scopes alone. It must be attached
+// to routes rather than groups so that route parameters are available.
+func Authorize(policy *auth.Policy, resource ResourceFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+		}
+		if len(principal.Roles) == 0 {
+			return c.Next()
+		}
+
+		target := resource(c)
+		if !policy.Allows(principal.Roles, target) {
+			logger.FromFiber(c, nil).Info("RBAC policy denied request",
+				logger.String("subject", principal.Subject),
+				logger.Any("roles", principal.Roles),
+				logger.String("provider", target.Provider),
+				logger.String("owner", target.Owner),
+				logger.String("repo", target.Repo),
+			)
+			return fiber.NewError(fiber.StatusForbidden, "Access to this repository is not permitted")
+		}
+
+		setRepositoryAccess(c, policy, principal)
+		return c.Next()
+	}
+}
+
+// AuthorizeRepositories attaches the RBAC check to routes that span many
+// repositories without denying the request itself. Handlers must apply
+// RepositoryAccess to every repository they read.
+func AuthorizeRepositories(policy *auth.Policy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+		}
+		if len(principal.Roles) > 0 {
+			setRepositoryAccess(c, policy, principal)
+		}
+		return c.Next()
+	}
+}
+
+func setRepositoryAccess(c *fiber.Ctx, policy *auth.Policy, principal *auth.Principal) {
+	c.Locals(repositoryAccessLocalsKey, func(resource auth.Resource) bool {
+		return policy.Allows(principal.Roles, resource)
+	})
+}
+
+// RepositoryAccess returns the check a listing must apply to each repository
+// it returns. Principals without roles may see everything.
+func RepositoryAccess(c *fiber.Ctx) func(auth.Resource) bool {
+	if allowed, ok := c.Locals(repositoryAccessLocalsKey).(func(auth.Resource) bool); ok {
+		return allowed
+	}
+	return func(auth.Resource) bool { return true }
+}
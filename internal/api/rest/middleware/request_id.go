@@ -1,17 +1,35 @@
 package middleware
 
 import (
+	"devmetrics/pkg/requestid"
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
 )
 
+// RequestIDLocalsKey is the fiber.Ctx locals key holding the request ID
+const RequestIDLocalsKey = "request_id"
+
+// RequestID accepts a well-formed client-provided X-Request-ID or generates a
+// new one, echoes it on the response and stores it in the request context so
+// logs, error bodies and upstream calls can carry it
 func RequestID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		requestID := c.Get("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-			c.Set("X-Request-ID", requestID)
+		requestID := c.Get(requestid.Header)
+		if !requestid.IsValid(requestID) {
+			requestID = requestid.New()
 		}
+
+		c.Set(requestid.Header, requestID)
+		c.Locals(RequestIDLocalsKey, requestID)
+		c.SetUserContext(requestid.NewContext(c.UserContext(), requestID))
+
 		return c.Next()
 	}
 }
+
+// GetRequestID returns the request ID assigned by the RequestID middleware
+func GetRequestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals(RequestIDLocalsKey).(string); ok {
+		return id
+	}
+	return requestid.FromContext(c.UserContext())
+}
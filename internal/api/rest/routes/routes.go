@@ -2,50 +2,228 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"time"
+	"net/url"
 
+	"devmetrics/internal/api/rest/handlers/aggregate"
+	"devmetrics/internal/api/rest/handlers/auth"
+	"devmetrics/internal/api/rest/handlers/health"
+	"devmetrics/internal/api/rest/handlers/identity"
+	"devmetrics/internal/api/rest/handlers/reload"
+	"devmetrics/internal/api/rest/handlers/repos"
+	"devmetrics/internal/api/rest/handlers/secrets"
+	"devmetrics/internal/api/rest/handlers/team"
+	"devmetrics/internal/api/rest/handlers/tenant"
 	"devmetrics/internal/api/rest/handlers/vcs/github"
 	"devmetrics/internal/api/rest/handlers/vcs/gitlab"
+	"devmetrics/internal/api/rest/middleware"
+	domain "devmetrics/internal/domain/auth"
+	"devmetrics/internal/domain/vcs"
 )
 
 type Routes struct {
-	githubHandler *github.Handler
-	gitlabHandler *gitlab.Handler
+	githubHandler    *github.Handler
+	gitlabHandler    *gitlab.Handler
+	aggregateHandler *aggregate.Handler
+	reposHandler     *repos.Handler
+	identityHandler  *identity.Handler
+	teamHandler      *team.Handler
+	authHandler      *auth.Handler
+	tenantHandler    *tenant.Handler
+	secretHandler    *secrets.Handler
+	reloadHandler    *reload.Handler
+	healthHandler    *health.Handler
+	authenticator    *middleware.Authenticator
+	policy           *domain.Policy
 }
 
-func NewRoutes(githubHandler *github.Handler, gitlabHandler *gitlab.Handler) *Routes {
+func NewRoutes(
+	githubHandler *github.Handler,
+	gitlabHandler *gitlab.Handler,
+	aggregateHandler *aggregate.Handler,
+	reposHandler *repos.Handler,
+	identityHandler *identity.Handler,
+	teamHandler *team.Handler,
+	authHandler *auth.Handler,
+	tenantHandler *tenant.Handler,
+	secretHandler *secrets.Handler,
+	reloadHandler *reload.Handler,
+	healthHandler *health.Handler,
+	authenticator *middleware.Authenticator,
+	policy *domain.Policy,
+) *Routes {
 	return &Routes{
-		githubHandler: githubHandler,
-		gitlabHandler: gitlabHandler,
+		githubHandler:    githubHandler,
+		gitlabHandler:    gitlabHandler,
+		aggregateHandler: aggregateHandler,
+		reposHandler:     reposHandler,
+		identityHandler:  identityHandler,
+		teamHandler:      teamHandler,
+		authHandler:      authHandler,
+		tenantHandler:    tenantHandler,
+		secretHandler:    secretHandler,
+		reloadHandler:    reloadHandler,
+		healthHandler:    healthHandler,
+		authenticator:    authenticator,
+		policy:           policy,
 	}
 }
 
 func (r *Routes) Setup(app *fiber.App) {
+	app.Get("/livez", r.healthHandler.Live)
+	app.Get("/readyz", r.healthHandler.Ready)
+
 	api := app.Group("/api/v1")
+	api.Get("/health", r.healthHandler.Live)
+
+	authenticated := api.Group("", r.authenticator.Authenticate())
+	authenticated.Get("/tenant", r.tenantHandler.GetCurrent)
+	r.setupVCSRoutes(authenticated)
+	r.setupRepositoryRoutes(authenticated)
+	r.setupAggregateRoutes(authenticated)
 
-	r.setupVCSRoutes(api)
-	r.setupHealthRoutes(api)
+	peopleGroup := authenticated.Group("/people", middleware.RequireScope(domain.ScopeReadVCS))
+	peopleGroup.Get("/", r.identityHandler.ListPeople)
+	peopleGroup.Get("/:id", r.identityHandler.GetPerson)
+
+	teamsGroup := authenticated.Group("/teams", middleware.RequireScope(domain.ScopeReadVCS))
+	teamsGroup.Get("/", r.teamHandler.ListTeams)
+	teamsGroup.Get("/:id", r.teamHandler.GetTeam)
+	r.setupAdminRoutes(authenticated)
 }
 
 func (r *Routes) setupVCSRoutes(api fiber.Router) {
-	vcsGroup := api.Group("/vcs")
+	vcsGroup := api.Group("/vcs", middleware.RequireScope(domain.ScopeReadVCS))
 
+	gitlabAuthz := middleware.Authorize(r.policy, gitlabResource)
 	gitlabGroup := vcsGroup.Group("/gitlab/projects")
-	gitlabGroup.Get("/:id", r.gitlabHandler.GetRepository)
-	gitlabGroup.Get("/:id/commits", r.gitlabHandler.GetCommits)
-	gitlabGroup.Get("/:id/merge-requests", r.gitlabHandler.GetPullRequests)
+	gitlabGroup.Get("/:id", gitlabAuthz, r.gitlabHandler.GetRepository)
+	gitlabGroup.Get("/:id/commits", gitlabAuthz, r.gitlabHandler.GetCommits)
+	gitlabGroup.Get("/:id/merge-requests", gitlabAuthz, r.gitlabHandler.GetPullRequests)
+
+	vcsGroup.Get("/gitlab/groups/:group/projects", middleware.Authorize(r.policy, gitlabGroupResource), r.gitlabHandler.ListProjects)
+	vcsGroup.Get("/github/orgs/:owner/repositories", middleware.Authorize(r.policy, githubOwnerResource), r.githubHandler.ListRepositories)
 
+	githubAuthz := middleware.Authorize(r.policy, githubResource)
 	githubGroup := vcsGroup.Group("/github/repositories")
-	githubGroup.Get("/:owner/:name", r.githubHandler.GetRepository)
-	githubGroup.Get("/:owner/:name/commits", r.githubHandler.GetCommits)
-	githubGroup.Get("/:owner/:name/pull-requests", r.githubHandler.GetPullRequests)
-}
-
-func (r *Routes) setupHealthRoutes(api fiber.Router) {
-	api.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status": "ok",
-			"time":   time.Now(),
-		})
-	})
+	githubGroup.Get("/:owner/:name", githubAuthz, r.githubHandler.GetRepository)
+	githubGroup.Get("/:owner/:name/commits", githubAuthz, r.githubHandler.GetCommits)
+	githubGroup.Get("/:owner/:name/pull-requests", githubAuthz, r.githubHandler.GetPullRequests)
+}
+
+// setupRepositoryRoutes serves the provider-agnostic routes. A repository is
+// named by path, with "/-/" separating it from the resource as on GitLab,
+// e.g. /repos/gitlab/group/subgroup/project/-/commits, or by a clone URL,
+// e.g. /repos/commits?url=git@github.com:acme/api.git
+func (r *Routes) setupRepositoryRoutes(api fiber.Router) {
+	reposGroup := api.Group("/repos", middleware.RequireScope(domain.ScopeReadVCS))
+	authz := middleware.Authorize(r.policy, repos.Resource)
+	resolve := r.reposHandler.Resolve
+
+	// Static routes first so they are not taken for a provider
+	reposGroup.Get("/", resolve, authz, r.reposHandler.GetRepository)
+	reposGroup.Get("/commits", resolve, authz, r.reposHandler.GetCommits)
+	reposGroup.Get("/pull-requests", resolve, authz, r.reposHandler.GetPullRequests)
+	reposGroup.Get("/commits/:sha/files", resolve, authz, r.reposHandler.GetCommitFiles)
+	reposGroup.Get("/pull-requests/:number/files", resolve, authz, r.reposHandler.GetPullRequestFiles)
+
+	reposGroup.Get("/:provider/*/-/commits", resolve, authz, r.reposHandler.GetCommits)
+	reposGroup.Get("/:provider/*/-/pull-requests", resolve, authz, r.reposHandler.GetPullRequests)
+	reposGroup.Get("/:provider/*/-/merge-requests", resolve, authz, r.reposHandler.GetPullRequests)
+	reposGroup.Get("/:provider/*/-/commits/:sha/files", resolve, authz, r.reposHandler.GetCommitFiles)
+	reposGroup.Get("/:provider/*/-/pull-requests/:number/files", resolve, authz, r.reposHandler.GetPullRequestFiles)
+	reposGroup.Get("/:provider/*/-/merge-requests/:number/files", resolve, authz, r.reposHandler.GetPullRequestFiles)
+	reposGroup.Get("/:provider/*", resolve, authz, r.reposHandler.GetRepository)
+}
+
+// setupAggregateRoutes serves activity and metrics across many repositories;
+// RBAC is applied by the handler to each selected repository
+func (r *Routes) setupAggregateRoutes(api fiber.Router) {
+	aggregateGroup := api.Group("/aggregate",
+		middleware.RequireScope(domain.ScopeReadVCS),
+		middleware.AuthorizeRepositories(r.policy),
+	)
+	aggregateGroup.Get("/activity", r.aggregateHandler.GetActivity)
+	aggregateGroup.Get("/commits", r.aggregateHandler.GetCommits)
+	aggregateGroup.Get("/pull-requests", r.aggregateHandler.GetPullRequests)
+
+	metricsGroup := api.Group("/metrics",
+		middleware.RequireScope(domain.ScopeReadMetrics),
+		middleware.AuthorizeRepositories(r.policy),
+	)
+	metricsGroup.Get("/pull-requests", r.aggregateHandler.GetPullRequestMetrics)
+	metricsGroup.Get("/pull-request-sizes", r.aggregateHandler.GetPullRequestSizes)
+	metricsGroup.Get("/hotspots", r.aggregateHandler.GetHotspots)
+	metricsGroup.Get("/bus-factor", r.aggregateHandler.GetBusFactor)
+	metricsGroup.Get("/code-owners", r.aggregateHandler.GetCodeOwnership)
+	metricsGroup.Get("/reviews", r.aggregateHandler.GetReviewMetrics)
+	metricsGroup.Get("/commits", r.aggregateHandler.GetCommitMetrics)
+}
+
+func githubResource(c *fiber.Ctx) domain.Resource {
+	return domain.Resource{
+		Provider: string(vcs.ProviderGitHub),
+		Owner:    c.Params("owner"),
+		Repo:     c.Params("owner") + "/" + c.Params("name"),
+	}
+}
+
+// githubOwnerResource targets an organization's or user's repository listing
+func githubOwnerResource(c *fiber.Ctx) domain.Resource {
+	return domain.Resource{
+		Provider: string(vcs.ProviderGitHub),
+		Owner:    c.Params("owner"),
+	}
+}
+
+// gitlabGroupResource targets a group's project listing
+func gitlabGroupResource(c *fiber.Ctx) domain.Resource {
+	group, err := url.PathUnescape(c.Params("group"))
+	if err != nil {
+		group = c.Params("group")
+	}
+	return domain.Resource{
+		Provider: string(vcs.ProviderGitLab),
+		Owner:    group,
+	}
+}
+
+// gitlabResource can't derive the namespace from a numeric project ID, so
+// roles restricted to organizations are denied these routes
+func gitlabResource(c *fiber.Ctx) domain.Resource {
+	return domain.Resource{
+		Provider: string(vcs.ProviderGitLab),
+		Repo:     c.Params("id"),
+	}
+}
+
+func (r *Routes) setupAdminRoutes(api fiber.Router) {
+	adminGroup := api.Group("/admin", middleware.RequireScope(domain.ScopeAdmin))
+
+	keysGroup := adminGroup.Group("/keys")
+	keysGroup.Get("/", r.authHandler.ListKeys)
+	keysGroup.Post("/", r.authHandler.CreateKey)
+	keysGroup.Delete("/:id", r.authHandler.RevokeKey)
+
+	secretsGroup := adminGroup.Group("/secrets")
+	secretsGroup.Get("/", r.secretHandler.ListSecrets)
+	secretsGroup.Put("/:name", r.secretHandler.PutSecret)
+	secretsGroup.Delete("/:name", r.secretHandler.DeleteSecret)
+
+	identitiesGroup := adminGroup.Group("/identities")
+	identitiesGroup.Get("/overrides", r.identityHandler.ListOverrides)
+	identitiesGroup.Put("/overrides/:id", r.identityHandler.PutOverride)
+	identitiesGroup.Delete("/overrides/:id", r.identityHandler.DeleteOverride)
+	identitiesGroup.Get("/mailmap", r.identityHandler.GetMailmap)
+	identitiesGroup.Put("/mailmap", r.identityHandler.PutMailmap)
+
+	teamsGroup := adminGroup.Group("/teams")
+	teamsGroup.Put("/:id", r.teamHandler.PutTeam)
+	teamsGroup.Delete("/:id", r.teamHandler.DeleteTeam)
+	teamsGroup.Post("/:id/sync", r.teamHandler.SyncTeam)
+
+	adminGroup.Get("/health", r.healthHandler.GetReport)
+
+	configGroup := adminGroup.Group("/config")
+	configGroup.Get("/reload", r.reloadHandler.GetStatus)
+	configGroup.Post("/reload", r.reloadHandler.Reload)
 }
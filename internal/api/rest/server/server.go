@@ -4,46 +4,46 @@ import (
 	"context"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 
-	"devmetrics/internal/api/rest/handlers/vcs/github"
-	"devmetrics/internal/api/rest/handlers/vcs/gitlab"
 	"devmetrics/internal/api/rest/middleware"
 	"devmetrics/internal/api/rest/routes"
 	"devmetrics/internal/config"
+	"devmetrics/pkg/logger"
 )
 
 type Server struct {
 	app    *fiber.App
 	config *config.Config
 	routes *routes.Routes
+	logger logger.Logger
 	addr   string
 }
 
 func NewServer(
 	config *config.Config,
-	githubHandler *github.Handler,
-	gitlabHandler *gitlab.Handler,
+	routes *routes.Routes,
+	log logger.Logger,
 ) *Server {
 	app := fiber.New(fiber.Config{
-		ErrorHandler: middleware.ErrorHandler,
+		ErrorHandler:          middleware.ErrorHandler,
+		DisableStartupMessage: true,
 	})
 
 	addr := fmt.Sprintf("%s:%s", config.Server.Host, config.Server.Port)
-	routes := routes.NewRoutes(githubHandler, gitlabHandler)
 
 	return &Server{
 		app:    app,
 		config: config,
 		routes: routes,
+		logger: log,
 		addr:   addr,
 	}
 }
 
 func (s *Server) setupMiddleware() {
-	s.app.Use(logger.New())
-	s.app.Use(middleware.Cors())
 	s.app.Use(middleware.RequestID())
+	s.app.Use(logger.FiberMiddleware(s.logger))
+	s.app.Use(middleware.Cors(s.config.Server.CORSAllowOrigins))
 }
 
 func (s *Server) setupRoutes() {
@@ -54,6 +54,7 @@ func (s *Server) Start() error {
 	s.setupMiddleware()
 	s.setupRoutes()
 
+	s.logger.Info("HTTP server listening", logger.String("addr", s.addr))
 	return s.app.Listen(s.addr)
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"devmetrics/internal/api/rest/server"
 	"devmetrics/internal/config"
+	"devmetrics/internal/services/reload"
 	"devmetrics/internal/services/vcs"
 )
 
@@ -15,6 +16,7 @@ type Application struct {
 	cfg    *config.Config
 	server *server.Server
 	vcs    *vcs.Service
+	reload *reload.Service
 }
 
 // NewApplication creates a new application instance
@@ -22,16 +24,26 @@ func NewApplication(
 	cfg *config.Config,
 	server *server.Server,
 	vcs *vcs.Service,
+	reload *reload.Service,
 ) *Application {
 	return &Application{
 		cfg:    cfg,
 		server: server,
 		vcs:    vcs,
+		reload: reload,
 	}
 }
 
 // Start initializes and starts all application components
 func (a *Application) Start(ctx context.Context) error {
+	go a.reload.Run(ctx)
+	if interval := a.cfg.Health.ProviderCheckInterval; interval > 0 {
+		go a.vcs.MonitorProviders(ctx,
+			time.Duration(interval)*time.Second,
+			time.Duration(a.cfg.Startup.VerifyTimeout)*time.Second,
+		)
+	}
+
 	if err := a.server.Start(); err != nil {
 		return fmt.Errorf("server error: %w", err)
 	}
@@ -1,176 +1,249 @@
 package config
 
-import (
-	"fmt"
-	"github.com/joho/godotenv"
-	"os"
-	"strconv"
-	"time"
-)
-
+// Config is the application configuration. Field keys in configuration files
+// are the json tag names; every field can be overridden by an environment
+// variable named after its upper-cased key path, e.g. VCS_GITHUB_PAGE_SIZE.
 type Config struct {
-	Environment string
-	Server      ServerConfig
-	VCS         VCSConfig
-	Logger      LoggerConfig
+	Environment string            `json:"environment"`
+	Server      ServerConfig      `json:"server"`
+	VCS         VCSConfig         `json:"vcs"`
+	Logger      LoggerConfig      `json:"logger"`
+	Auth        AuthConfig        `json:"auth"`
+	Secrets     SecretsConfig     `json:"secrets"`
+	Reload      ReloadConfig      `json:"reload"`
+	Startup     StartupConfig     `json:"startup"`
+	Health      HealthConfig      `json:"health"`
+	Aggregation AggregationConfig `json:"aggregation"`
+	Bots        BotsConfig        `json:"bots"`
+	Metrics     MetricsConfig     `json:"metrics"`
+	// DefaultTenantName names the tenant built from the VCS settings above
+	DefaultTenantName string `json:"default_tenant_name"`
+	// TenantsFile is an additional YAML, TOML or JSON file holding a list of tenants
+	TenantsFile string `json:"tenants_file"`
+	// Tenants always contains the default tenant built from the VCS settings
+	// above, followed by the declared tenants and those from TenantsFile
+	Tenants []TenantConfig `json:"tenants"`
+	// Warnings lists problems Load worked around, such as an unreadable .env file
+	Warnings []string `json:"-"`
 }
 
 type ServerConfig struct {
-	Host            string
-	Port            string
-	ShutdownTimeout int
+	Host            string `json:"host"`
+	Port            string `json:"port"`
+	ShutdownTimeout int    `json:"shutdown_timeout"`
+	// CORSAllowOrigins is a comma-separated list of allowed origins
+	CORSAllowOrigins string `json:"cors_allow_origins"`
 }
 
 type VCSConfig struct {
-	GitHub    GitHubConfig
-	GitLab    GitLabConfig
-	BitBucket BitBucketConfig
+	GitHub    GitHubConfig    `json:"github"`
+	GitLab    GitLabConfig    `json:"gitlab"`
+	BitBucket BitBucketConfig `json:"bitbucket"`
 }
 
 type GitHubConfig struct {
-	Enabled    bool
-	Token      string
-	BaseURL    string
-	APIVersion string
-	MaxPages   int
-	PageSize   int
-	TimeoutSec int
-	RateLimit  int
-	RetryCount int
-	RetryDelay int
+	Enabled    bool   `json:"enabled"`
+	Token      string `json:"token"`
+	BaseURL    string `json:"base_url"`
+	APIVersion string `json:"api_version"`
+	MaxPages   int    `json:"max_pages"`
+	PageSize   int    `json:"page_size"`
+	TimeoutSec int    `json:"timeout_sec"`
+	RateLimit  int    `json:"rate_limit"`
+	RetryCount int    `json:"retry_count"`
+	RetryDelay int    `json:"retry_delay"`
+	// ForwardRequestID sends the incoming X-Request-ID to the upstream API
+	ForwardRequestID bool `json:"forward_request_id"`
 }
 
 type GitLabConfig struct {
-	Enabled    bool
-	Token      string
-	BaseURL    string
-	MaxPages   int
-	PageSize   int
-	TimeoutSec int
+	Enabled    bool   `json:"enabled"`
+	Token      string `json:"token"`
+	BaseURL    string `json:"base_url"`
+	MaxPages   int    `json:"max_pages"`
+	PageSize   int    `json:"page_size"`
+	TimeoutSec int    `json:"timeout_sec"`
+	// ForwardRequestID sends the incoming X-Request-ID to the upstream API
+	ForwardRequestID bool `json:"forward_request_id"`
 }
 
 type BitBucketConfig struct {
-	Enabled     bool
-	Username    string
-	AppPassword string
-	BaseURL     string
-	MaxPages    int
-	PageSize    int
-	TimeoutSec  int
+	Enabled     bool   `json:"enabled"`
+	Username    string `json:"username"`
+	AppPassword string `json:"app_password"`
+	BaseURL     string `json:"base_url"`
+	MaxPages    int    `json:"max_pages"`
+	PageSize    int    `json:"page_size"`
+	TimeoutSec  int    `json:"timeout_sec"`
+}
+
+type AuthConfig struct {
+	Enabled bool `json:"enabled"`
+	// DefaultRateLimit is the per-key requests per minute when a key sets none; 0 disables limiting
+	DefaultRateLimit int            `json:"default_rate_limit"`
+	APIKeys          []APIKeyConfig `json:"api_keys"`
+	OIDC             OIDCConfig     `json:"oidc"`
+	// RBACPolicyFile is a JSON file mapping role names to scopes and data restrictions
+	RBACPolicyFile string `json:"rbac_policy_file"`
+}
+
+// OIDCConfig configures validation of bearer JWTs issued by an identity provider
+type OIDCConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+	// JWKSURL overrides the jwks_uri discovered from the issuer
+	JWKSURL string `json:"jwks_url"`
+	// JWKSFile loads keys from a local file instead of the network, for testing
+	JWKSFile     string `json:"jwks_file"`
+	JWKSCacheTTL int    `json:"jwks_cache_ttl"`
+	// RolesClaim is a dot-separated path to the claim holding the user's roles or groups
+	RolesClaim string `json:"roles_claim"`
+	// RoleMapping maps claim values to RBAC role names; unmapped values are used as-is
+	RoleMapping map[string]string `json:"role_mapping"`
+	// TenantClaim is the claim naming the user's tenant. Tokens without it belong to
+	// the default tenant when no other tenant is configured, else are rejected.
+	TenantClaim string `json:"tenant_claim"`
+}
+
+// APIKeyConfig declares a static API key. Only the SHA-256 hex hash of the key is configured.
+type APIKeyConfig struct {
+	Name      string   `json:"name"`
+	Hash      string   `json:"hash"`
+	Scopes    []string `json:"scopes"`
+	RateLimit int      `json:"rate_limit"`
+	// Tenant defaults to the tenant the key is declared in, or the default tenant
+	Tenant string `json:"tenant"`
+}
+
+// TenantConfig declares a workspace with its own provider credentials,
+// tracked repositories and API keys
+type TenantConfig struct {
+	ID           string                    `json:"id"`
+	Name         string                    `json:"name"`
+	VCS          VCSConfig                 `json:"vcs"`
+	Repositories []TrackedRepositoryConfig `json:"repositories"`
+	Teams        []TeamConfig              `json:"teams"`
+	APIKeys      []APIKeyConfig            `json:"api_keys"`
+}
+
+// TrackedRepositoryConfig names a repository a tenant collects metrics for
+type TrackedRepositoryConfig struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+}
+
+// TeamConfig groups contributors and the repositories they own
+type TeamConfig struct {
+	Name string `json:"name"`
+	// Members are person IDs, commit e-mail addresses, provider:login
+	// accounts such as "github:octocat", or logins on any provider
+	Members []string `json:"members"`
+	// Repositories the team owns
+	Repositories []TrackedRepositoryConfig `json:"repositories"`
+}
+
+// DefaultTenantID identifies the tenant built from the environment VCS settings
+const DefaultTenantID = "default"
+
+// SecretsConfig configures resolution of secret:// references and the
+// envelope-encrypted secret store
+type SecretsConfig struct {
+	// MasterKey is a base64-encoded 32-byte key; MasterKeyFile takes precedence
+	MasterKey     string `json:"master_key"`
+	MasterKeyFile string `json:"master_key_file"`
+	// StoreFile holds encrypted secret rows; empty keeps them in memory
+	StoreFile string `json:"store_file"`
+	// FileDir is the base directory for secret://file/ references
+	FileDir string `json:"file_dir"`
+	// CacheTTL is how long resolved secrets are reused before re-reading, in seconds
+	CacheTTL int `json:"cache_ttl"`
+}
+
+// ReloadConfig controls hot reloading of the configuration file. A reload is
+// also triggered by SIGHUP or the admin API.
+type ReloadConfig struct {
+	// WatchInterval is how often the config files are checked for changes, in seconds; 0 disables watching
+	WatchInterval int `json:"watch_interval"`
+}
+
+// StartupConfig controls how enabled providers are verified at startup
+type StartupConfig struct {
+	// Strict refuses to start when any enabled provider fails verification;
+	// otherwise the server starts and reports the failures through /readyz
+	Strict bool `json:"strict"`
+	// VerifyTimeout bounds provider verification, in seconds
+	VerifyTimeout int `json:"verify_timeout"`
+}
+
+// HealthConfig tunes the readiness checks
+type HealthConfig struct {
+	// CheckTimeout bounds each dependency check, in seconds
+	CheckTimeout int `json:"check_timeout"`
+	// ProviderCheckInterval is how often providers are verified again in the
+	// background, in seconds; 0 verifies them only at startup and on reload
+	ProviderCheckInterval int `json:"provider_check_interval"`
+	// MinRateLimitHeadroom is the percentage of upstream quota below which a provider is degraded
+	MinRateLimitHeadroom int `json:"min_rate_limit_headroom"`
+}
+
+// AggregationConfig bounds cross-repository queries
+type AggregationConfig struct {
+	// Concurrency is how many repositories are queried at once
+	Concurrency int `json:"concurrency"`
+	// MaxRepositories caps the repositories a single query may span
+	MaxRepositories int `json:"max_repositories"`
+	// MaxItemsPerRepository caps the commits and pull requests read from each repository
+	MaxItemsPerRepository int `json:"max_items_per_repository"`
+}
+
+// BotsConfig classifies automation accounts, which are left out of metrics
+// unless a request sets include_bots. Accounts GitHub reports as bots and
+// logins, names or e-mails ending in "[bot]" are always classified as bots.
+type BotsConfig struct {
+	// Patterns are regular expressions matched against author logins, names and e-mails
+	Patterns []string `json:"patterns"`
+	// Accounts are provider logins or commit e-mail addresses of bots, e.g. a release bot
+	Accounts []string `json:"accounts"`
+}
+
+// MetricsConfig tunes the analytics under /api/v1/metrics
+type MetricsConfig struct {
+	PullRequestSizes PullRequestSizesConfig `json:"pull_request_sizes"`
+	BusFactor        BusFactorConfig        `json:"bus_factor"`
+}
+
+// PullRequestSizesConfig sets the largest number of changed lines, additions
+// plus deletions, of each pull request size class. Larger pull requests are XXL.
+type PullRequestSizesConfig struct {
+	XS int `json:"xs"`
+	S  int `json:"s"`
+	M  int `json:"m"`
+	L  int `json:"l"`
+	XL int `json:"xl"`
+	// Exclude are glob patterns of generated files and lockfiles whose lines
+	// don't count when pull request files are loaded; patterns without a
+	// slash match file names in any directory
+	Exclude []string `json:"exclude"`
+}
+
+// BusFactorConfig sets the default thresholds of knowledge distribution
+// reports; requests may override each of them
+type BusFactorConfig struct {
+	// HalfLifeDays is the age at which a change counts half towards ownership
+	HalfLifeDays int `json:"half_life_days"`
+	// CoveragePercent is the share of a path's ownership the authors making
+	// up its bus factor hold together
+	CoveragePercent int `json:"coverage_percent"`
+	// InactiveDays is how long after their last commit an author counts as gone
+	InactiveDays int `json:"inactive_days"`
+	// RiskThreshold flags paths whose bus factor is at most this
+	RiskThreshold int `json:"risk_threshold"`
 }
 
 type LoggerConfig struct {
-	Level      string
-	Format     string
-	Output     string
-	TimeFormat string
-}
-
-// getEnvWithDefault retrieves an environment variable with a fallback default value
-func getEnvWithDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// getEnvBoolWithDefault retrieves a boolean environment variable with a fallback default value
-func getEnvBoolWithDefault(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		boolValue, err := strconv.ParseBool(value)
-		if err == nil {
-			return boolValue
-		}
-	}
-	return defaultValue
-}
-
-// getEnvIntWithDefault retrieves an integer environment variable with a fallback default value
-func getEnvIntWithDefault(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		intValue, err := strconv.Atoi(value)
-		if err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
-func NewConfig() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		fmt.Printf("Warning: Error loading .env file: %v\n", err)
-	}
-
-	config := &Config{
-		Environment: getEnvWithDefault("ENVIRONMENT", "development"),
-		Server: ServerConfig{
-			Host:            getEnvWithDefault("SERVER_HOST", "0.0.0.0"),
-			Port:            getEnvWithDefault("SERVER_PORT", "8080"),
-			ShutdownTimeout: getEnvIntWithDefault("SERVER_SHUTDOWN_TIMEOUT", 5),
-		},
-		VCS: VCSConfig{
-			GitHub: GitHubConfig{
-				Enabled:    getEnvBoolWithDefault("VCS_GITHUB_ENABLED", false),
-				Token:      os.Getenv("VCS_GITHUB_TOKEN"),
-				BaseURL:    getEnvWithDefault("VCS_GITHUB_BASE_URL", "https://api.github.com"),
-				APIVersion: getEnvWithDefault("VCS_GITHUB_API_VERSION", "2022-11-28"),
-				MaxPages:   getEnvIntWithDefault("VCS_GITHUB_MAX_PAGES", 100),
-				PageSize:   getEnvIntWithDefault("VCS_GITHUB_PAGE_SIZE", 100),
-				TimeoutSec: getEnvIntWithDefault("VCS_GITHUB_TIMEOUT_SEC", 30),
-				RateLimit:  getEnvIntWithDefault("VCS_GITHUB_RATE_LIMIT", 5000),
-				RetryCount: getEnvIntWithDefault("VCS_GITHUB_RETRY_COUNT", 3),
-				RetryDelay: getEnvIntWithDefault("VCS_GITHUB_RETRY_DELAY", 1),
-			},
-			GitLab: GitLabConfig{
-				Enabled:    getEnvBoolWithDefault("VCS_GITLAB_ENABLED", false),
-				Token:      os.Getenv("VCS_GITLAB_TOKEN"),
-				BaseURL:    getEnvWithDefault("VCS_GITLAB_BASE_URL", "https://gitlab.com/api/v4"),
-				MaxPages:   getEnvIntWithDefault("VCS_GITLAB_MAX_PAGES", 100),
-				PageSize:   getEnvIntWithDefault("VCS_GITLAB_PAGE_SIZE", 100),
-				TimeoutSec: getEnvIntWithDefault("VCS_GITLAB_TIMEOUT_SEC", 30),
-			},
-			BitBucket: BitBucketConfig{
-				Enabled:     getEnvBoolWithDefault("VCS_BITBUCKET_ENABLED", false),
-				Username:    os.Getenv("VCS_BITBUCKET_USERNAME"),
-				AppPassword: os.Getenv("VCS_BITBUCKET_APP_PASSWORD"),
-				BaseURL:     getEnvWithDefault("VCS_BITBUCKET_BASE_URL", "https://api.bitbucket.org/2.0"),
-				MaxPages:    getEnvIntWithDefault("VCS_BITBUCKET_MAX_PAGES", 100),
-				PageSize:    getEnvIntWithDefault("VCS_BITBUCKET_PAGE_SIZE", 100),
-				TimeoutSec:  getEnvIntWithDefault("VCS_BITBUCKET_TIMEOUT_SEC", 30),
-			},
-		},
-		Logger: LoggerConfig{
-			Level:      getEnvWithDefault("LOGGER_LEVEL", "info"),
-			Format:     getEnvWithDefault("LOGGER_FORMAT", "json"),
-			Output:     getEnvWithDefault("LOGGER_OUTPUT", "stdout"),
-			TimeFormat: getEnvWithDefault("LOGGER_TIME_FORMAT", time.RFC3339),
-		},
-	}
-
-	if err := validateConfig(config); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
-	return config, nil
-}
-
-func validateConfig(cfg *Config) error {
-	if cfg.VCS.GitHub.Enabled && cfg.VCS.GitHub.Token == "" {
-		return fmt.Errorf("GitHub token is required when GitHub is enabled")
-	}
-
-	if cfg.VCS.GitLab.Enabled && cfg.VCS.GitLab.Token == "" {
-		return fmt.Errorf("GitLab token is required when GitLab is enabled")
-	}
-
-	if cfg.VCS.BitBucket.Enabled {
-		if cfg.VCS.BitBucket.Username == "" {
-			return fmt.Errorf("BitBucket username is required when BitBucket is enabled")
-		}
-		if cfg.VCS.BitBucket.AppPassword == "" {
-			return fmt.Errorf("BitBucket app password is required when BitBucket is enabled")
-		}
-	}
-
-	return nil
+	Level      string `json:"level"`
+	Format     string `json:"format"`
+	Output     string `json:"output"`
+	TimeFormat string `json:"time_format"`
 }
@@ -0,0 +1,313 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile decodes a YAML, TOML or JSON file, chosen by extension, into
+// generic maps and slices
+func readFile(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var raw interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		var doc map[string]interface{}
+		err = toml.Unmarshal(data, &doc)
+		raw = doc
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file extension %q (use .yaml, .toml or .json)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+// decoder applies generic file and environment values onto configuration
+// structs, collecting every problem instead of stopping at the first one
+type decoder struct {
+	errs FieldErrors
+	// templates hold the starting value for new slice elements of a type
+	templates map[reflect.Type]reflect.Value
+}
+
+func (d *decoder) fail(path, format string, args ...interface{}) {
+	d.errs = append(d.errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// skipKey marks fields that are not read from files or the environment
+const skipKey = "-"
+
+// fieldKey returns the configuration key of a struct field
+func fieldKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// apply decodes raw onto v. A nil raw value (e.g. an empty YAML key) keeps the current value.
+func (d *decoder) apply(path string, raw interface{}, v reflect.Value) {
+	if raw == nil {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			d.fail(path, "expected a table of fields, got %s", describe(raw))
+			return
+		}
+		fields := make(map[string]int, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if key := fieldKey(v.Type().Field(i)); key != skipKey {
+				fields[key] = i
+			}
+		}
+		for _, key := range sortedKeys(m) {
+			value := m[key]
+			i, ok := fields[key]
+			if !ok {
+				d.fail(joinPath(path, key), "unknown field")
+				continue
+			}
+			d.apply(joinPath(path, key), value, v.Field(i))
+		}
+
+	case reflect.Slice:
+		items, ok := asList(raw)
+		if !ok {
+			d.fail(path, "expected a list, got %s", describe(raw))
+			return
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			elem := slice.Index(i)
+			if tmpl, ok := d.templates[elem.Type()]; ok {
+				elem.Set(tmpl)
+			}
+			d.apply(fmt.Sprintf("%s[%d]", path, i), item, elem)
+		}
+		v.Set(slice)
+
+	case reflect.Map:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			d.fail(path, "expected a table of strings, got %s", describe(raw))
+			return
+		}
+		out := reflect.MakeMapWithSize(v.Type(), len(m))
+		for key, value := range m {
+			s, ok := value.(string)
+			if !ok {
+				d.fail(joinPath(path, key), "expected a string, got %s", describe(value))
+				continue
+			}
+			out.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(s))
+		}
+		v.Set(out)
+
+	case reflect.String:
+		switch value := raw.(type) {
+		case string:
+			v.SetString(value)
+		case int, int64, uint64, float64:
+			// Unquoted values such as port: 8080
+			v.SetString(fmt.Sprint(value))
+		default:
+			d.fail(path, "expected a string, got %s", describe(raw))
+		}
+
+	case reflect.Int:
+		n, ok := asInt(raw)
+		if !ok {
+			d.fail(path, "expected an integer, got %s", describe(raw))
+			return
+		}
+		v.SetInt(n)
+
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			d.fail(path, "expected true or false, got %s", describe(raw))
+			return
+		}
+		v.SetBool(b)
+
+	default:
+		d.fail(path, "unsupported field type %s", v.Type())
+	}
+}
+
+// applyEnv overrides v from environment variables named after its key path.
+// Scalars are parsed from their text, string lists are comma-separated, and
+// lists of tables and string maps are JSON; the elements of a list of tables
+// can be overridden individually as NAME_<index>_FIELD.
+func (d *decoder) applyEnv(name, path string, v reflect.Value) {
+	value := os.Getenv(name)
+	where := fmt.Sprintf("%s (env %s)", path, name)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			key := fieldKey(v.Type().Field(i))
+			if key == skipKey {
+				continue
+			}
+			d.applyEnv(envName(name, key), joinPath(path, key), v.Field(i))
+		}
+		return
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String {
+			if value != "" {
+				v.Set(reflect.ValueOf(splitList(value)))
+			}
+			return
+		}
+		if value != "" {
+			var raw interface{}
+			if err := json.Unmarshal([]byte(value), &raw); err != nil {
+				d.fail(where, "must be a JSON array: %v", err)
+			} else {
+				d.apply(where, raw, v)
+			}
+		}
+		for i := 0; i < v.Len(); i++ {
+			d.applyEnv(fmt.Sprintf("%s_%d", name, i), fmt.Sprintf("%s[%d]", path, i), v.Index(i))
+		}
+		return
+	}
+
+	if value == "" {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		var raw interface{}
+		if err := json.Unmarshal([]byte(value), &raw); err != nil {
+			d.fail(where, "must be a JSON object of strings: %v", err)
+			return
+		}
+		d.apply(where, raw, v)
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			d.fail(where, "must be an integer, got %q", value)
+			return
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			d.fail(where, "must be true or false, got %q", value)
+			return
+		}
+		v.SetBool(b)
+	default:
+		d.fail(where, "unsupported field type %s", v.Type())
+	}
+}
+
+func envName(prefix, key string) string {
+	key = strings.ToUpper(key)
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// asList normalises the list types produced by the different decoders
+func asList(raw interface{}) ([]interface{}, bool) {
+	switch list := raw.(type) {
+	case []interface{}:
+		return list, true
+	case []map[string]interface{}:
+		items := make([]interface{}, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func asInt(raw interface{}) (int64, bool) {
+	switch n := raw.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint64:
+		return int64(n), n <= math.MaxInt64
+	case float64:
+		return int64(n), n == math.Trunc(n) && math.Abs(n) <= math.MaxInt64
+	}
+	return 0, false
+}
+
+func describe(raw interface{}) string {
+	switch raw.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case int, int64, uint64, float64:
+		return "a number"
+	case map[string]interface{}:
+		return "a table"
+	case []interface{}, []map[string]interface{}:
+		return "a list"
+	}
+	return fmt.Sprintf("%T", raw)
+}
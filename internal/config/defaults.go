@@ -0,0 +1,110 @@
+package config
+
+import "time"
+
+// Defaults returns the configuration used before any file or environment
+// variable is applied
+func Defaults() Config {
+	return Config{
+		Environment: "development",
+		Server: ServerConfig{
+			Host:             "0.0.0.0",
+			Port:             "8080",
+			ShutdownTimeout:  5,
+			CORSAllowOrigins: "*",
+		},
+		VCS: VCSConfig{
+			GitHub: GitHubConfig{
+				BaseURL:          "https://api.github.com",
+				APIVersion:       "2022-11-28",
+				MaxPages:         100,
+				PageSize:         100,
+				TimeoutSec:       30,
+				RateLimit:        5000,
+				RetryCount:       3,
+				RetryDelay:       1,
+				ForwardRequestID: true,
+			},
+			GitLab: GitLabConfig{
+				BaseURL:          "https://gitlab.com/api/v4",
+				MaxPages:         100,
+				PageSize:         100,
+				TimeoutSec:       30,
+				ForwardRequestID: true,
+			},
+			BitBucket: BitBucketConfig{
+				BaseURL:    "https://api.bitbucket.org/2.0",
+				MaxPages:   100,
+				PageSize:   100,
+				TimeoutSec: 30,
+			},
+		},
+		Logger: LoggerConfig{
+			Level:      "info",
+			Format:     "json",
+			Output:     "stdout",
+			TimeFormat: time.RFC3339,
+		},
+		Auth: AuthConfig{
+			DefaultRateLimit: 600,
+			OIDC: OIDCConfig{
+				JWKSCacheTTL: 3600,
+				RolesClaim:   "roles",
+				TenantClaim:  "tenant",
+			},
+		},
+		Secrets: SecretsConfig{
+			FileDir:  "/run/secrets",
+			CacheTTL: 300,
+		},
+		Reload: ReloadConfig{
+			WatchInterval: 10,
+		},
+		Startup: StartupConfig{
+			VerifyTimeout: 10,
+		},
+		Health: HealthConfig{
+			CheckTimeout:          5,
+			ProviderCheckInterval: 60,
+			MinRateLimitHeadroom:  10,
+		},
+		Aggregation: AggregationConfig{
+			Concurrency:           8,
+			MaxRepositories:       100,
+			MaxItemsPerRepository: 1000,
+		},
+		Bots: BotsConfig{
+			Patterns: []string{
+				`(?i)^(dependabot|renovate|github-actions|snyk-bot|greenkeeper)\b`,
+				// GitLab project and group access tokens act as these users
+				`^(project|group)_\d+_bot`,
+			},
+		},
+		Metrics: MetricsConfig{
+			PullRequestSizes: PullRequestSizesConfig{
+				XS: 10, S: 50, M: 250, L: 500, XL: 1000,
+				Exclude: []string{
+					"package-lock.json", "yarn.lock", "pnpm-lock.yaml", "go.sum", "Cargo.lock",
+					"Gemfile.lock", "poetry.lock", "composer.lock",
+					"*.min.js", "*.pb.go", "*_generated.go", "*.snap", "vendor/",
+				},
+			},
+			BusFactor: BusFactorConfig{
+				HalfLifeDays:    180,
+				CoveragePercent: 50,
+				InactiveDays:    90,
+				RiskThreshold:   1,
+			},
+		},
+		DefaultTenantName: "Default",
+	}
+}
+
+// tenantDefaults returns the provider settings a declared tenant starts from:
+// the global settings without their credentials
+func tenantDefaults(global VCSConfig) VCSConfig {
+	global.GitHub.Enabled, global.GitHub.Token = false, ""
+	global.GitLab.Enabled, global.GitLab.Token = false, ""
+	global.BitBucket.Enabled, global.BitBucket.Username, global.BitBucket.AppPassword = false, "", ""
+	return global
+}
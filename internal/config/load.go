@@ -0,0 +1,75 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+
+	"github.com/joho/godotenv"
+)
+
+// Load builds the configuration from defaults, the optional config file at
+// path and environment variable overrides, in that order of precedence, and
+// validates the result. All problems found are returned together as FieldErrors;
+// non-fatal ones are left in Config.Warnings for the caller to log.
+func Load(path string) (*Config, error) {
+	cfg := Defaults()
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("ignoring .env file: %v", err))
+	}
+
+	d := &decoder{}
+
+	var rawTenants interface{}
+	if path != "" {
+		raw, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if doc, ok := raw.(map[string]interface{}); ok {
+			// Tenants start from the final global provider settings, so they
+			// are applied after the environment has been
+			rawTenants = doc["tenants"]
+			delete(doc, "tenants")
+		}
+		d.apply("", raw, reflect.ValueOf(&cfg).Elem())
+	}
+
+	root := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		key := fieldKey(root.Type().Field(i))
+		if key == "tenants" || key == skipKey {
+			continue
+		}
+		d.applyEnv(envName("", key), key, root.Field(i))
+	}
+
+	d.templates = map[reflect.Type]reflect.Value{
+		reflect.TypeOf(TenantConfig{}): reflect.ValueOf(TenantConfig{VCS: tenantDefaults(cfg.VCS)}),
+	}
+	d.apply("tenants", rawTenants, reflect.ValueOf(&cfg.Tenants).Elem())
+
+	if cfg.TenantsFile != "" {
+		raw, err := readFile(cfg.TenantsFile)
+		if err != nil {
+			return nil, err
+		}
+		var tenants []TenantConfig
+		d.apply(cfg.TenantsFile, raw, reflect.ValueOf(&tenants).Elem())
+		cfg.Tenants = append(cfg.Tenants, tenants...)
+	}
+	d.applyEnv("TENANTS", "tenants", reflect.ValueOf(&cfg.Tenants).Elem())
+
+	cfg.Tenants = append([]TenantConfig{{
+		ID:   DefaultTenantID,
+		Name: cfg.DefaultTenantName,
+		VCS:  cfg.VCS,
+	}}, cfg.Tenants...)
+
+	if errs := append(d.errs, validate(&cfg)...); len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &cfg, nil
+}
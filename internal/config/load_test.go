@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != "8080" || cfg.VCS.GitHub.PageSize != 100 {
+		t.Errorf("Load = port %q, page size %d, want the defaults", cfg.Server.Port, cfg.VCS.GitHub.PageSize)
+	}
+	if len(cfg.Tenants) != 1 || cfg.Tenants[0].ID != DefaultTenantID {
+		t.Errorf("Tenants = %+v, want only the default tenant", cfg.Tenants)
+	}
+	if len(cfg.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", cfg.Warnings)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"yaml", "config.yaml", "server:\n  port: 9090\nvcs:\n  github:\n    page_size: 50\n"},
+		{"toml", "config.toml", "[server]\nport = \"9090\"\n[vcs.github]\npage_size = 50\n"},
+		{"json", "config.json", `{"server": {"port": "9090"}, "vcs": {"github": {"page_size": 50}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(writeFile(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Port != "9090" || cfg.VCS.GitHub.PageSize != 50 {
+				t.Errorf("Load = port %q, page size %d, want 9090 and 50", cfg.Server.Port, cfg.VCS.GitHub.PageSize)
+			}
+			if cfg.Server.Host != "0.0.0.0" {
+				t.Errorf("Server.Host = %q, want the default kept", cfg.Server.Host)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 9090
+tenants:
+  - id: acme
+    repositories:
+      - provider: gitlab
+        name: platform/api
+`)
+	t.Setenv("SERVER_PORT", "7070")
+	t.Setenv("VCS_GITHUB_PAGE_SIZE", "25")
+	t.Setenv("AUTH_OIDC_ROLE_MAPPING", `{"engineering": "viewer"}`)
+	t.Setenv("VCS_GITLAB_BASE_URL", "https://gitlab.example.com/api/v4")
+	t.Setenv("TENANTS_0_VCS_GITLAB_TOKEN", "glpat-acme")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.Port != "7070" {
+		t.Errorf("Server.Port = %q, want the environment to win over the file", cfg.Server.Port)
+	}
+	if cfg.VCS.GitHub.PageSize != 25 {
+		t.Errorf("VCS.GitHub.PageSize = %d, want 25", cfg.VCS.GitHub.PageSize)
+	}
+	if want := map[string]string{"engineering": "viewer"}; !reflect.DeepEqual(cfg.Auth.OIDC.RoleMapping, want) {
+		t.Errorf("RoleMapping = %v, want %v", cfg.Auth.OIDC.RoleMapping, want)
+	}
+
+	acme := cfg.Tenants[1]
+	if acme.ID != "acme" || acme.VCS.GitLab.Token != "glpat-acme" {
+		t.Errorf("tenant = %q with token %q, want acme with the indexed override", acme.ID, acme.VCS.GitLab.Token)
+	}
+	if acme.VCS.GitLab.BaseURL != "https://gitlab.example.com/api/v4" {
+		t.Errorf("tenant GitLab base URL = %q, want the overridden global one inherited", acme.VCS.GitLab.BaseURL)
+	}
+}
+
+func TestLoadFieldErrors(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 70000
+  timeout: 5
+logger:
+  format: xml
+vcs:
+  github:
+    enabled: true
+    page_size: many
+tenants:
+  - id: acme
+    repositories:
+      - provider: svn
+        name: trunk
+  - id: acme
+`)
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "soon")
+
+	_, err := Load(path)
+	var errs FieldErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Load error = %v, want FieldErrors", err)
+	}
+
+	got := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		got[e.Path] = true
+	}
+	for _, path := range []string{
+		"server.timeout",
+		"vcs.github.page_size",
+		"server.shutdown_timeout (env SERVER_SHUTDOWN_TIMEOUT)",
+		"server.port",
+		"logger.format",
+		"vcs.github.token",
+		"tenants[0].repositories[0].provider",
+		"tenants[1].id",
+	} {
+		if !got[path] {
+			t.Errorf("no error reported for %s; got %v", path, errs)
+		}
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "unused")
+	if _, err := Load("../../config.example.yaml"); err != nil {
+		t.Errorf("config.example.yaml doesn't load: %v", err)
+	}
+}
+
+func TestLoadUnsupportedExtension(t *testing.T) {
+	if _, err := Load(writeFile(t, "config.ini", "port=1")); err == nil {
+		t.Error("Load accepted an .ini file")
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"devmetrics/pkg/glob"
+	"go.uber.org/zap/zapcore"
+)
+
+// validator accumulates FieldErrors for a loaded configuration
+type validator struct {
+	errs FieldErrors
+}
+
+func (v *validator) check(ok bool, path, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+func validate(cfg *Config) FieldErrors {
+	v := &validator{}
+
+	port, err := strconv.Atoi(cfg.Server.Port)
+	v.check(err == nil && port > 0 && port < 65536, "server.port", "must be a port number, got %q", cfg.Server.Port)
+	v.check(cfg.Server.ShutdownTimeout >= 0, "server.shutdown_timeout", "must not be negative")
+
+	_, err = zapcore.ParseLevel(cfg.Logger.Level)
+	v.check(err == nil, "logger.level", "unknown level %q", cfg.Logger.Level)
+	v.check(cfg.Logger.Format == "json" || cfg.Logger.Format == "console", "logger.format", "must be json or console, got %q", cfg.Logger.Format)
+
+	v.check(cfg.Auth.DefaultRateLimit >= 0, "auth.default_rate_limit", "must not be negative")
+	if oidc := cfg.Auth.OIDC; oidc.Enabled {
+		v.check(oidc.Issuer != "", "auth.oidc.issuer", "required when OIDC is enabled")
+		v.check(oidc.JWKSCacheTTL > 0, "auth.oidc.jwks_cache_ttl", "must be positive")
+		if oidc.JWKSURL != "" {
+			v.url("auth.oidc.jwks_url", oidc.JWKSURL)
+		}
+	}
+	v.check(cfg.Secrets.CacheTTL >= 0, "secrets.cache_ttl", "must not be negative")
+	v.check(cfg.Reload.WatchInterval >= 0, "reload.watch_interval", "must not be negative")
+	v.check(cfg.Startup.VerifyTimeout > 0, "startup.verify_timeout", "must be positive")
+	v.check(cfg.Health.CheckTimeout > 0, "health.check_timeout", "must be positive")
+	v.check(cfg.Health.ProviderCheckInterval >= 0, "health.provider_check_interval", "must not be negative")
+	v.check(cfg.Health.MinRateLimitHeadroom >= 0 && cfg.Health.MinRateLimitHeadroom <= 100, "health.min_rate_limit_headroom", "must be a percentage")
+	v.check(cfg.Aggregation.Concurrency > 0, "aggregation.concurrency", "must be positive")
+	v.check(cfg.Aggregation.MaxRepositories > 0, "aggregation.max_repositories", "must be positive")
+	v.check(cfg.Aggregation.MaxItemsPerRepository > 0, "aggregation.max_items_per_repository", "must be positive")
+	for i, pattern := range cfg.Bots.Patterns {
+		_, err := regexp.Compile(pattern)
+		v.check(err == nil, fmt.Sprintf("bots.patterns[%d]", i), "invalid regular expression: %v", err)
+	}
+	sizes := cfg.Metrics.PullRequestSizes
+	v.check(sizes.XS > 0, "metrics.pull_request_sizes.xs", "must be positive")
+	v.check(sizes.S > sizes.XS, "metrics.pull_request_sizes.s", "must be larger than xs")
+	v.check(sizes.M > sizes.S, "metrics.pull_request_sizes.m", "must be larger than s")
+	v.check(sizes.L > sizes.M, "metrics.pull_request_sizes.l", "must be larger than m")
+	v.check(sizes.XL > sizes.L, "metrics.pull_request_sizes.xl", "must be larger than l")
+	for i, pattern := range sizes.Exclude {
+		_, err := glob.Compile(pattern)
+		v.check(err == nil, fmt.Sprintf("metrics.pull_request_sizes.exclude[%d]", i), "invalid pattern: %v", err)
+	}
+	busFactor := cfg.Metrics.BusFactor
+	v.check(busFactor.HalfLifeDays > 0, "metrics.bus_factor.half_life_days", "must be positive")
+	v.check(busFactor.CoveragePercent > 0 && busFactor.CoveragePercent <= 100, "metrics.bus_factor.coverage_percent", "must be a percentage above 0")
+	v.check(busFactor.InactiveDays > 0, "metrics.bus_factor.inactive_days", "must be positive")
+	v.check(busFactor.RiskThreshold > 0, "metrics.bus_factor.risk_threshold", "must be positive")
+
+	tenants := make(map[string]bool, len(cfg.Tenants))
+	for i, tenant := range cfg.Tenants {
+		// The default tenant is built from the global settings and reported
+		// there; declared tenants keep the index they have in the file
+		path := ""
+		if i > 0 {
+			path = fmt.Sprintf("tenants[%d]", i-1)
+		}
+
+		v.check(tenant.ID != "", joinPath(path, "id"), "required")
+		v.check(tenant.ID == "" || !tenants[tenant.ID], joinPath(path, "id"), "duplicate tenant id %q", tenant.ID)
+		tenants[tenant.ID] = true
+
+		v.vcs(joinPath(path, "vcs"), tenant.VCS)
+
+		for j, repo := range tenant.Repositories {
+			v.repository(fmt.Sprintf("%s.repositories[%d]", path, j), repo)
+		}
+
+		teams := make(map[string]bool, len(tenant.Teams))
+		for j, team := range tenant.Teams {
+			teamPath := fmt.Sprintf("%s.teams[%d]", path, j)
+			v.check(team.Name != "", teamPath+".name", "required")
+			v.check(team.Name == "" || !teams[team.Name], teamPath+".name", "duplicate team %q", team.Name)
+			teams[team.Name] = true
+			for k, repo := range team.Repositories {
+				v.repository(fmt.Sprintf("%s.repositories[%d]", teamPath, k), repo)
+			}
+		}
+
+		for j, key := range tenant.APIKeys {
+			keyPath := fmt.Sprintf("%s.api_keys[%d]", path, j)
+			v.apiKey(keyPath, key)
+			v.check(key.Tenant == "" || key.Tenant == tenant.ID, keyPath+".tenant", "must be empty or %q", tenant.ID)
+		}
+	}
+
+	for i, key := range cfg.Auth.APIKeys {
+		path := fmt.Sprintf("auth.api_keys[%d]", i)
+		v.apiKey(path, key)
+		v.check(key.Tenant == "" || tenants[key.Tenant], path+".tenant", "unknown tenant %q", key.Tenant)
+	}
+
+	return v.errs
+}
+
+func (v *validator) vcs(path string, cfg VCSConfig) {
+	github := joinPath(path, "github")
+	if cfg.GitHub.Enabled {
+		v.check(cfg.GitHub.Token != "", github+".token", "required when GitHub is enabled")
+	}
+	v.url(github+".base_url", cfg.GitHub.BaseURL)
+	v.paging(github, cfg.GitHub.MaxPages, cfg.GitHub.PageSize, cfg.GitHub.TimeoutSec)
+	v.check(cfg.GitHub.RetryCount >= 0, github+".retry_count", "must not be negative")
+	v.check(cfg.GitHub.RetryDelay >= 0, github+".retry_delay", "must not be negative")
+
+	gitlab := joinPath(path, "gitlab")
+	if cfg.GitLab.Enabled {
+		v.check(cfg.GitLab.Token != "", gitlab+".token", "required when GitLab is enabled")
+	}
+	v.url(gitlab+".base_url", cfg.GitLab.BaseURL)
+	v.paging(gitlab, cfg.GitLab.MaxPages, cfg.GitLab.PageSize, cfg.GitLab.TimeoutSec)
+
+	bitbucket := joinPath(path, "bitbucket")
+	if cfg.BitBucket.Enabled {
+		v.check(cfg.BitBucket.Username != "", bitbucket+".username", "required when BitBucket is enabled")
+		v.check(cfg.BitBucket.AppPassword != "", bitbucket+".app_password", "required when BitBucket is enabled")
+	}
+	v.url(bitbucket+".base_url", cfg.BitBucket.BaseURL)
+	v.paging(bitbucket, cfg.BitBucket.MaxPages, cfg.BitBucket.PageSize, cfg.BitBucket.TimeoutSec)
+}
+
+func (v *validator) paging(path string, maxPages, pageSize, timeoutSec int) {
+	v.check(maxPages > 0, path+".max_pages", "must be positive")
+	v.check(pageSize > 0 && pageSize <= 100, path+".page_size", "must be between 1 and 100, got %d", pageSize)
+	v.check(timeoutSec > 0, path+".timeout_sec", "must be positive")
+}
+
+func (v *validator) url(path, value string) {
+	u, err := url.Parse(value)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", path, "must be an http(s) URL, got %q", value)
+}
+
+func (v *validator) repository(path string, repo TrackedRepositoryConfig) {
+	known := repo.Provider == "github" || repo.Provider == "gitlab" || repo.Provider == "bitbucket"
+	v.check(known, path+".provider", "must be github, gitlab or bitbucket, got %q", repo.Provider)
+	v.check(repo.Name != "", path+".name", "required")
+}
+
+func (v *validator) apiKey(path string, key APIKeyConfig) {
+	v.check(key.Name != "", path+".name", "required")
+	v.check(len(key.Hash) == 64, path+".hash", "must be a hex-encoded SHA-256 digest")
+	v.check(len(key.Scopes) > 0, path+".scopes", "at least one scope is required")
+	v.check(key.RateLimit >= 0, path+".rate_limit", "must not be negative")
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// KeyPrefix marks devmetrics API keys so they are easy to spot in secret scanners
+const KeyPrefix = "dm_"
+
+var (
+	ErrInvalidKey  = errors.New("invalid API key")
+	ErrKeyNotFound = errors.New("API key not found")
+	ErrKeyExists   = errors.New("API key already exists")
+)
+
+// APIKey is a stored API key. Only the SHA-256 hash of the secret is kept.
+type APIKey struct {
+	ID       string
+	TenantID string
+	Name     string
+	Hash     string
+	Scopes   []Scope
+	// RateLimit is the number of requests allowed per minute; 0 uses the default
+	RateLimit  int
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	// Static keys come from configuration and cannot be revoked at runtime
+	Static bool
+}
+
+// IsActive reports whether the key may be used
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil
+}
+
+// KeyStore persists API keys
+type KeyStore interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByHash(ctx context.Context, hash string) (*APIKey, error)
+	Get(ctx context.Context, id string) (*APIKey, error)
+	List(ctx context.Context) ([]*APIKey, error)
+	Update(ctx context.Context, key *APIKey) error
+}
+
+// GenerateKey returns a new random API key secret
+func GenerateKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashKey returns the hex-encoded SHA-256 hash of a key secret. Keys are
+// high-entropy random values, so a fast hash is sufficient.
+func HashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashesEqual compares two key hashes in constant time
+func HashesEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,39 @@
+package auth
+
+import "context"
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject identifies the caller, e.g. "apikey:<id>"
+	Subject string
+	Name    string
+	KeyID   string
+	// TenantID is the workspace every request of this principal is scoped to
+	TenantID string
+	Scopes   []Scope
+	// Roles are set for identity provider users and subject them to the RBAC policy
+	Roles []string
+	// Anonymous is set when authentication is disabled
+	Anonymous bool
+}
+
+// HasScope reports whether the principal was granted the scope
+func (p *Principal) HasScope(scope Scope) bool {
+	return p != nil && HasScope(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"path"
+	"strings"
+)
+
+// Role grants scopes and optionally restricts which data those scopes reach.
+// Empty restriction lists mean "no restriction". Organization and repository
+// entries are glob patterns matched case-insensitively, e.g. "acme" or "acme/api-*".
+type Role struct {
+	Scopes        []Scope  `json:"scopes"`
+	Providers     []string `json:"providers"`
+	Organizations []string `json:"organizations"`
+	Repositories  []string `json:"repositories"`
+}
+
+// Policy is the set of roles known to the RBAC layer
+type Policy struct {
+	Roles map[string]Role `json:"roles"`
+}
+
+// Resource identifies the repository a request targets. Owner is empty when
+// it cannot be derived from the request, e.g. a GitLab numeric project ID.
+// Repo is empty when listing an owner's repositories; repository
+// restrictions are then applied to the results instead.
+type Resource struct {
+	Provider string
+	Owner    string
+	Repo     string
+}
+
+// ScopesFor returns the union of scopes granted by the given roles
+func (p *Policy) ScopesFor(roles []string) []Scope {
+	seen := make(map[Scope]bool)
+	var scopes []Scope
+	for _, name := range roles {
+		role, ok := p.Roles[name]
+		if !ok {
+			continue
+		}
+		for _, scope := range role.Scopes {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// Allows reports whether any of the roles may access the resource
+func (p *Policy) Allows(roles []string, resource Resource) bool {
+	for _, name := range roles {
+		role, ok := p.Roles[name]
+		if ok && role.allows(resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Role) allows(resource Resource) bool {
+	if len(r.Providers) > 0 && !matchAny(r.Providers, resource.Provider) {
+		return false
+	}
+	if len(r.Organizations) > 0 && (resource.Owner == "" || !matchAny(r.Organizations, resource.Owner)) {
+		return false
+	}
+	if len(r.Repositories) > 0 && resource.Repo != "" && !matchAny(r.Repositories, resource.Repo) {
+		return false
+	}
+	if resource.Repo == "" && resource.Owner == "" && len(r.Repositories) > 0 {
+		return false
+	}
+	return true
+}
+
+func matchAny(patterns []string, value string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToLower(pattern), value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
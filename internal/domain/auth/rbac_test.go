@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyAllows(t *testing.T) {
+	policy := &Policy{Roles: map[string]Role{
+		"everything": {Scopes: []Scope{ScopeReadVCS}},
+		"github-acme": {
+			Providers:     []string{"github"},
+			Organizations: []string{"Acme"},
+		},
+		"api-repos":  {Repositories: []string{"acme/api-*"}},
+		"acme-teams": {Organizations: []string{"acme-*", "platform"}},
+	}}
+
+	github := func(owner, repo string) Resource {
+		return Resource{Provider: "github", Owner: owner, Repo: owner + "/" + repo}
+	}
+	tests := []struct {
+		name     string
+		roles    []string
+		resource Resource
+		want     bool
+	}{
+		{"unrestricted role", []string{"everything"}, github("other", "repo"), true},
+		{"no roles", nil, github("acme", "api"), false},
+		{"unknown role", []string{"ghost"}, github("acme", "api"), false},
+		{"provider and organization match", []string{"github-acme"}, github("acme", "web"), true},
+		{"organization matches ignoring case", []string{"github-acme"}, github("ACME", "web"), true},
+		{"other provider", []string{"github-acme"}, Resource{Provider: "gitlab", Owner: "acme", Repo: "acme/web"}, false},
+		{"other organization", []string{"github-acme"}, github("acme-labs", "web"), false},
+		{"unknown owner fails an organization restriction", []string{"github-acme"}, Resource{Provider: "github", Repo: "12345"}, false},
+		{"organization glob", []string{"acme-teams"}, github("acme-labs", "web"), true},
+		{"organization list", []string{"acme-teams"}, github("platform", "web"), true},
+		{"organization glob doesn't cross segments", []string{"acme-teams"}, github("acme", "web"), false},
+		{"repository glob", []string{"api-repos"}, github("acme", "api-gateway"), true},
+		{"repository glob stays in its segment", []string{"api-repos"}, Resource{Provider: "gitlab", Owner: "acme", Repo: "acme/api-v2/sub"}, false},
+		{"repository glob mismatch", []string{"api-repos"}, github("acme", "web"), false},
+		{"any role allowing is enough", []string{"api-repos", "github-acme"}, github("acme", "web"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Allows(tt.roles, tt.resource); got != tt.want {
+				t.Errorf("Allows(%v, %+v) = %v, want %v", tt.roles, tt.resource, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyScopesFor(t *testing.T) {
+	policy := &Policy{Roles: map[string]Role{
+		"viewer":  {Scopes: []Scope{ScopeReadVCS, ScopeReadMetrics}},
+		"metrics": {Scopes: []Scope{ScopeReadMetrics}},
+		"admin":   {Scopes: []Scope{ScopeAdmin}},
+	}}
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  []Scope
+	}{
+		{"single role", []string{"metrics"}, []Scope{ScopeReadMetrics}},
+		{"union without duplicates", []string{"metrics", "viewer"}, []Scope{ScopeReadMetrics, ScopeReadVCS}},
+		{"unknown roles grant nothing", []string{"ghost"}, nil},
+		{"admin", []string{"admin"}, []Scope{ScopeAdmin}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.ScopesFor(tt.roles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScopesFor(%v) = %v, want %v", tt.roles, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		scopes   []Scope
+		required Scope
+		want     bool
+	}{
+		{[]Scope{ScopeReadVCS}, ScopeReadVCS, true},
+		{[]Scope{ScopeReadVCS}, ScopeReadMetrics, false},
+		{[]Scope{ScopeAdmin}, ScopeReadMetrics, true},
+		{nil, ScopeReadVCS, false},
+	}
+	for _, tt := range tests {
+		if got := HasScope(tt.scopes, tt.required); got != tt.want {
+			t.Errorf("HasScope(%v, %s) = %v, want %v", tt.scopes, tt.required, got, tt.want)
+		}
+	}
+}
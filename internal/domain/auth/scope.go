@@ -0,0 +1,34 @@
+package auth
+
+// Scope is a permission granted to an API key or principal
+type Scope string
+
+const (
+	ScopeReadVCS     Scope = "read:vcs"
+	ScopeReadMetrics Scope = "read:metrics"
+	// ScopeAdmin grants every other scope as well as key management
+	ScopeAdmin Scope = "admin"
+)
+
+// AllScopes lists every known scope
+var AllScopes = []Scope{ScopeReadVCS, ScopeReadMetrics, ScopeAdmin}
+
+// IsValid reports whether s is a known scope
+func (s Scope) IsValid() bool {
+	for _, known := range AllScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether scopes grant the required scope. Admin implies all scopes.
+func HasScope(scopes []Scope, required Scope) bool {
+	for _, scope := range scopes {
+		if scope == required || scope == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+var ErrOverrideNotFound = errors.New("identity override not found")
+
+// Account is a login on a provider
+type Account struct {
+	Provider vcs.ProviderType
+	Login    string
+}
+
+// Person is a canonical contributor with every alias linked to them
+type Person struct {
+	ID     string
+	Name   string
+	Emails []string
+	Logins []Account
+	Names  []string
+}
+
+// Override manually declares a person. All of its e-mails and logins are
+// linked, and Name becomes the person's display name.
+type Override struct {
+	ID        string
+	Name      string
+	Emails    []string
+	Logins    []Account
+	UpdatedAt time.Time
+}
+
+// Store persists each tenant's overrides and .mailmap
+type Store interface {
+	ListOverrides(ctx context.Context, tenantID string) ([]Override, error)
+	PutOverride(ctx context.Context, tenantID string, override Override) error
+	DeleteOverride(ctx context.Context, tenantID, id string) error
+	GetMailmap(ctx context.Context, tenantID string) (string, error)
+	PutMailmap(ctx context.Context, tenantID, content string) error
+}
@@ -0,0 +1,53 @@
+package identity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MailmapEntry maps the name and e-mail found in commits to a proper name
+// and e-mail. Empty fields are not part of the mapping.
+type MailmapEntry struct {
+	ProperName  string
+	ProperEmail string
+	CommitName  string
+	CommitEmail string
+}
+
+// mailmapLine matches "[Proper Name] [<proper@email>] [Commit Name] <commit@email>"
+var mailmapLine = regexp.MustCompile(`^([^<]*)<([^>]*)>\s*(?:([^<]*)<([^>]*)>)?\s*$`)
+
+// ParseMailmap parses the content of a git .mailmap file
+func ParseMailmap(content string) ([]MailmapEntry, error) {
+	var entries []MailmapEntry
+	for i, line := range strings.Split(content, "\n") {
+		if j := strings.Index(line, "#"); j >= 0 {
+			line = line[:j]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		match := mailmapLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("mailmap line %d: expected [name] <email> [[name] <email>]", i+1)
+		}
+
+		entry := MailmapEntry{ProperName: strings.TrimSpace(match[1])}
+		if match[4] == "" && match[3] == "" {
+			// "Proper Name <commit@email>" only fixes the name
+			entry.CommitEmail = strings.TrimSpace(match[2])
+		} else {
+			entry.ProperEmail = strings.TrimSpace(match[2])
+			entry.CommitName = strings.TrimSpace(match[3])
+			entry.CommitEmail = strings.TrimSpace(match[4])
+		}
+		if entry.CommitEmail == "" {
+			return nil, fmt.Errorf("mailmap line %d: the commit e-mail is required", i+1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,29 @@
+package identity
+
+import (
+	"regexp"
+	"strings"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+var noreplyPatterns = []struct {
+	provider vcs.ProviderType
+	pattern  *regexp.Regexp
+}{
+	// 12345+login@users.noreply.github.com, or login@... for older accounts
+	{vcs.ProviderGitHub, regexp.MustCompile(`^(?:\d+\+)?([a-z0-9-]+)@users\.noreply\.github\.com$`)},
+	// 12345-login@users.noreply.gitlab.com
+	{vcs.ProviderGitLab, regexp.MustCompile(`^\d+-([a-z0-9_.-]+)@users\.noreply\.gitlab\.com$`)},
+}
+
+// NoreplyAccount returns the account behind a provider's private commit e-mail
+func NoreplyAccount(email string) (Account, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	for _, noreply := range noreplyPatterns {
+		if match := noreply.pattern.FindStringSubmatch(email); match != nil {
+			return Account{Provider: noreply.provider, Login: match[1]}, true
+		}
+	}
+	return Account{}, false
+}
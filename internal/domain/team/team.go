@@ -0,0 +1,55 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+)
+
+var (
+	ErrNotFound = errors.New("team not found")
+	// ErrReadOnly is returned when changing a team declared in the configuration
+	ErrReadOnly = errors.New("team is declared in the configuration")
+)
+
+// Source tells where a team is defined
+type Source string
+
+const (
+	SourceConfig Source = "config"
+	SourceAPI    Source = "api"
+)
+
+// Team groups contributors and the repositories they own
+type Team struct {
+	ID   string
+	Name string
+	// Members are aliases of canonical people: person IDs, e-mail addresses,
+	// provider:login accounts or logins on any provider. They are resolved
+	// when metrics are computed, so people linked later count as well.
+	Members      []string
+	Repositories []tenant.Repository
+	// Sync, when set, replaces Members with the accounts of a provider team or group on each sync
+	Sync      *Sync
+	SyncedAt  *time.Time
+	Source    Source
+	UpdatedAt time.Time
+}
+
+// Sync names the provider team or group a team's members are copied from
+type Sync struct {
+	Provider vcs.ProviderType
+	// Group is "org/team-slug" on GitHub and a group ID or full path on GitLab
+	Group string
+}
+
+// Store persists the teams managed through the API, per tenant
+type Store interface {
+	List(ctx context.Context, tenantID string) ([]Team, error)
+	Get(ctx context.Context, tenantID, id string) (*Team, error)
+	Put(ctx context.Context, tenantID string, team Team) error
+	Delete(ctx context.Context, tenantID, id string) error
+}
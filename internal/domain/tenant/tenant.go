@@ -0,0 +1,53 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// DefaultID identifies the tenant built from the environment VCS settings
+const DefaultID = "default"
+
+var ErrNotFound = errors.New("tenant not found")
+
+// Tenant is an isolated workspace. Each tenant has its own provider
+// credentials, tracked repositories and API keys.
+type Tenant struct {
+	ID           string
+	Name         string
+	Repositories []Repository
+	Teams        []Team
+}
+
+// Team is a team declared in the tenant's configuration
+type Team struct {
+	Name string
+	// Members are person IDs, e-mail addresses, provider:login accounts or logins
+	Members      []string
+	Repositories []Repository
+}
+
+// Repository is a repository tracked by a tenant
+type Repository struct {
+	Provider vcs.ProviderType
+	Name     string
+}
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx scoped to the given tenant
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// IDFromContext returns the tenant the request is scoped to, or the default tenant
+func IDFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+			return id
+		}
+	}
+	return DefaultID
+}
@@ -0,0 +1,11 @@
+package vcs
+
+// AuthorFilter restricts commits and pull requests by their author. The zero
+// value leaves out automation accounts, which would otherwise dominate
+// human productivity metrics.
+type AuthorFilter struct {
+	IncludeBots bool
+	// Members, when not nil, keeps only the work of the people these aliases
+	// resolve to: person IDs, e-mails, provider:login accounts or logins
+	Members []string
+}
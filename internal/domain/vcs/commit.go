@@ -3,13 +3,54 @@ package vcs
 import "time"
 
 type Commit struct {
-	SHA          string
-	Message      string
-	AuthorName   string
-	AuthorEmail  string
-	CommittedAt  time.Time
-	ChangedFiles int
+	SHA         string
+	Message     string
+	AuthorName  string
+	AuthorEmail string
+	// AuthorLogin is the provider account the commit is linked to, when the provider reports one
+	AuthorLogin string
+	// AuthorBot marks commits made by automation accounts such as Dependabot
+	AuthorBot   bool
+	CommittedAt time.Time
+	// ChangedFiles counts the changed files; nil until Files are loaded, as
+	// commit listings don't report it
+	ChangedFiles *int
 	Additions    int
 	Deletions    int
+	// Files is only set when file changes were requested
+	Files        []FileChange
 	RepositoryID string
+	// Contributor is the canonical person behind the author, set by identity resolution
+	Contributor *Contributor
+
+	// The fields below are parsed from Message by the service.
+	// Type is the lower-cased Conventional Commits type; empty when the
+	// subject doesn't follow the convention.
+	Type  string
+	Scope string
+	// Breaking is set by a "!" after the type or a BREAKING CHANGE footer
+	Breaking bool
+	// Merge is set for commits with several parents or a merge subject
+	Merge  bool
+	Revert bool
+	// RevertedSHA is the commit a revert names, when it names one
+	RevertedSHA string
+	// Trailers are the footers of the message, in order
+	Trailers    []Trailer
+	CoAuthors   []CommitPerson
+	SignedOffBy []CommitPerson
+}
+
+// Trailer is a "Token: value" footer of a commit message
+type Trailer struct {
+	Token string
+	Value string
+}
+
+// CommitPerson is someone a commit message credits, such as a co-author
+type CommitPerson struct {
+	Name  string
+	Email string
+	// Contributor is set by identity resolution for co-authors
+	Contributor *Contributor
 }
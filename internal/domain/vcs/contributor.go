@@ -0,0 +1,29 @@
+package vcs
+
+import "context"
+
+// User is an account on a provider. Name and Email are empty when the
+// account does not make them public.
+type User struct {
+	Login string
+	Name  string
+	Email string
+}
+
+// Contributor is a canonical person, linking the e-mails, logins and names
+// they use across providers
+type Contributor struct {
+	ID   string
+	Name string
+}
+
+// ContributorResolver links commit and pull request authors to canonical
+// people and sets their Contributor field. The provider is used to look up
+// accounts.
+type ContributorResolver interface {
+	ResolveCommits(ctx context.Context, providerType ProviderType, provider Provider, commits []Commit)
+	ResolvePullRequests(ctx context.Context, providerType ProviderType, provider Provider, prs []PullRequest)
+	// ContributorIDs returns the IDs of the people that person IDs, e-mails,
+	// provider:login accounts or logins resolve to
+	ContributorIDs(ctx context.Context, aliases []string) map[string]bool
+}
@@ -0,0 +1,91 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Error kinds shared by all providers. Adapters translate upstream failures
+// into one of these so callers can react without knowing the provider.
+var (
+	ErrNotFound              = errors.New("resource not found")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrForbidden             = errors.New("forbidden")
+	ErrRateLimited           = errors.New("rate limited")
+	ErrProviderNotConfigured = errors.New("provider not configured")
+	ErrUpstreamUnavailable   = errors.New("upstream unavailable")
+	ErrInvalidInput          = errors.New("invalid input")
+)
+
+// Error describes a failed provider operation. Kind is one of the Err*
+// sentinels above, so errors.Is(err, vcs.ErrNotFound) works through wrapping.
+type Error struct {
+	Kind     error
+	Provider ProviderType
+	Op       string
+	// ResetAt is when a rate limit is lifted; zero when unknown or not applicable
+	ResetAt time.Time
+	Err     error
+}
+
+// NewError creates an Error of the given kind for a provider operation
+func NewError(kind error, provider ProviderType, op string, err error) *Error {
+	return &Error{
+		Kind:     kind,
+		Provider: provider,
+		Op:       op,
+		Err:      err,
+	}
+}
+
+func (e *Error) Error() string {
+	msg := e.Kind.Error()
+	if e.Op != "" {
+		msg = fmt.Sprintf("%s: %s", e.Op, msg)
+	}
+	if e.Provider != "" {
+		msg = fmt.Sprintf("%s: %s", e.Provider, msg)
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// RateLimitReset returns the time a rate limit is lifted, if err carries one
+func RateLimitReset(err error) (time.Time, bool) {
+	var vcsErr *Error
+	if errors.As(err, &vcsErr) && errors.Is(vcsErr.Kind, ErrRateLimited) && !vcsErr.ResetAt.IsZero() {
+		return vcsErr.ResetAt, true
+	}
+	return time.Time{}, false
+}
+
+// KindForStatus maps an upstream HTTP status code to an error kind. It
+// returns nil for statuses without a dedicated kind.
+func KindForStatus(status int) error {
+	switch {
+	case status == 400 || status == 422:
+		return ErrInvalidInput
+	case status == 401:
+		return ErrUnauthorized
+	case status == 403:
+		return ErrForbidden
+	case status == 404 || status == 410:
+		return ErrNotFound
+	case status == 429:
+		return ErrRateLimited
+	case status >= 500:
+		return ErrUpstreamUnavailable
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,21 @@
+package vcs
+
+// FileStatus is how a commit or pull request changed a file
+type FileStatus string
+
+const (
+	FileAdded    FileStatus = "added"
+	FileModified FileStatus = "modified"
+	FileRemoved  FileStatus = "removed"
+	FileRenamed  FileStatus = "renamed"
+)
+
+// FileChange is one file touched by a commit or pull request
+type FileChange struct {
+	Path string
+	// PreviousPath is the path before a rename; empty otherwise
+	PreviousPath string
+	Status       FileStatus
+	Additions    int
+	Deletions    int
+}
@@ -10,11 +10,43 @@ type Provider interface {
 	// GetRepository retrieves repository information
 	GetRepository(ctx context.Context, repo string) (*Repository, error)
 
+	// ListRepositories lists the repositories of an organization, user or
+	// group (including subgroups) that pass the filter
+	ListRepositories(ctx context.Context, owner string, filter RepositoryFilter) ([]Repository, error)
+
 	// GetCommits retrieves commits for a repository within a time range
 	GetCommits(ctx context.Context, repo string, since, until time.Time, offset, limit int) ([]Commit, int64, error)
 
 	// GetPullRequests retrieves pull requests for a repository within a time range
 	GetPullRequests(ctx context.Context, repo string, since, until time.Time, offset, limit int) ([]PullRequest, int64, error)
+
+	// GetCommitFiles retrieves every file changed by a commit
+	GetCommitFiles(ctx context.Context, repo, sha string) ([]FileChange, error)
+
+	// GetPullRequestFiles retrieves every file changed by a pull request
+	GetPullRequestFiles(ctx context.Context, repo string, number int) ([]FileChange, error)
+
+	// GetPullRequestReviews retrieves the reviews submitted on a pull request
+	GetPullRequestReviews(ctx context.Context, repo string, number int) ([]Review, error)
+
+	// GetFileContent retrieves a file of the default branch; ErrNotFound when it doesn't exist
+	GetFileContent(ctx context.Context, repo, path string) ([]byte, error)
+
+	// GetTree lists the files of the default branch
+	GetTree(ctx context.Context, repo string) (*Tree, error)
+
+	// Verify checks that the provider is reachable and its credentials are valid
+	Verify(ctx context.Context) (*Verification, error)
+
+	// GetUser looks up an account by login
+	GetUser(ctx context.Context, login string) (*User, error)
+
+	// ListGroupMembers lists the accounts of a GitHub team, named
+	// "org/team-slug", or of a GitLab group including inherited members
+	ListGroupMembers(ctx context.Context, group string) ([]User, error)
+
+	// WebHost returns the host serving the provider's web pages and clone URLs
+	WebHost() string
 }
 
 // ProviderType represents the type of VCS provider (GitHub, GitLab, etc.)
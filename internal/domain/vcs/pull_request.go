@@ -3,18 +3,32 @@ package vcs
 import "time"
 
 type PullRequest struct {
-	Number       int
-	Title        string
-	State        string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	ClosedAt     *time.Time
-	MergedAt     *time.Time
-	AuthorName   string
+	Number int
+	Title  string
+	State  string
+	// BaseBranch is the branch the pull request targets
+	BaseBranch  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ClosedAt    *time.Time
+	MergedAt    *time.Time
+	AuthorName  string
+	AuthorLogin string
+	// AuthorBot marks pull requests opened by automation accounts such as Renovate
+	AuthorBot    bool
 	ReviewCount  int
 	CommitCount  int
 	ChangedFiles int
 	Additions    int
 	Deletions    int
-	RepositoryID string
+	// Files is only set when file changes were requested
+	Files []FileChange
+	// Reviews is only set when reviews were requested
+	Reviews []Review
+	// RequestedReviewers are those asked for a review: on GitHub only those
+	// who haven't reviewed since, on GitLab every assigned reviewer
+	RequestedReviewers []Reviewer
+	RepositoryID       string
+	// Contributor is the canonical person behind the author, set by identity resolution
+	Contributor *Contributor
 }
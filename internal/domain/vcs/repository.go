@@ -1,6 +1,9 @@
 package vcs
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Repository struct {
 	ID            string
@@ -12,4 +15,60 @@ type Repository struct {
 	Description   string
 	Language      string
 	Private       bool
+	// Path is the provider's path to the repository, e.g. "owner/name" or "group/subgroup/project"
+	Path string
+	// Visibility is "public", "private" or "internal"
+	Visibility string
+	Archived   bool
+	Fork       bool
+	Topics     []string
+	// LastActivityAt is the last push (GitHub) or activity (GitLab)
+	LastActivityAt time.Time
+}
+
+// RepositoryFilter narrows repository discovery. The zero value excludes
+// archived repositories and forks and applies no other restriction.
+type RepositoryFilter struct {
+	IncludeArchived bool
+	IncludeForks    bool
+	// Visibility restricts results to "public", "private" or "internal" when set
+	Visibility string
+	// Topics must all be present on a repository
+	Topics []string
+	// ActiveSince drops repositories without activity since then, when set
+	ActiveSince time.Time
+	// Allow, when set, must also accept a repository; used to apply access policies
+	Allow func(Repository) bool
+}
+
+// Matches reports whether a repository passes the filter
+func (f RepositoryFilter) Matches(repo Repository) bool {
+	if repo.Archived && !f.IncludeArchived {
+		return false
+	}
+	if repo.Fork && !f.IncludeForks {
+		return false
+	}
+	if f.Visibility != "" && !strings.EqualFold(repo.Visibility, f.Visibility) {
+		return false
+	}
+	if !f.ActiveSince.IsZero() && repo.LastActivityAt.Before(f.ActiveSince) {
+		return false
+	}
+	if f.Allow != nil && !f.Allow(repo) {
+		return false
+	}
+	for _, want := range f.Topics {
+		found := false
+		for _, topic := range repo.Topics {
+			if strings.EqualFold(topic, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
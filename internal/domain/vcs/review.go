@@ -0,0 +1,37 @@
+package vcs
+
+import "time"
+
+// ReviewState is the outcome of a pull request review
+type ReviewState string
+
+const (
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+	ReviewCommented        ReviewState = "commented"
+	ReviewDismissed        ReviewState = "dismissed"
+	ReviewPending          ReviewState = "pending"
+)
+
+// Reviewer is an account asked for or giving a review
+type Reviewer struct {
+	Login string
+	// Bot marks automation accounts such as review bots
+	Bot bool
+	// Contributor is the canonical person behind the account, set by identity resolution
+	Contributor *Contributor
+}
+
+// Review is a review submitted on a pull request. GitLab only reports
+// approvals and reviewer states, without the time they were given.
+type Review struct {
+	Reviewer
+	State ReviewState
+	// SubmittedAt is nil when the provider doesn't report it
+	SubmittedAt *time.Time
+}
+
+// Submitted reports whether the review was handed in, as opposed to a draft
+func (r Review) Submitted() bool {
+	return r.State != ReviewPending
+}
@@ -0,0 +1,8 @@
+package vcs
+
+// Tree lists the files of a repository's default branch
+type Tree struct {
+	Paths []string
+	// Truncated is set when the provider listed only part of a large repository
+	Truncated bool
+}
@@ -0,0 +1,34 @@
+package vcs
+
+import "time"
+
+// Verification describes the credentials a provider is configured with
+type Verification struct {
+	// Identity is the login of the user or bot the token belongs to
+	Identity string
+	// Scopes granted to the token; nil when the provider does not report them
+	Scopes []string
+	// MissingScopes lists required scopes the token lacks
+	MissingScopes []string
+	// RateLimit is the upstream quota at the time of verification, if reported
+	RateLimit *RateLimit
+}
+
+// RateLimit is an upstream API quota
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// MissingScopes returns the accepted scopes when the token holds none of them
+func MissingScopes(granted []string, accepted ...string) []string {
+	for _, scope := range granted {
+		for _, want := range accepted {
+			if scope == want {
+				return nil
+			}
+		}
+	}
+	return accepted
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"devmetrics/internal/secrets"
+	"devmetrics/internal/services/vcs"
+)
+
+// Pinger is a dependency that can report whether it is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck reports a dependency as down when Ping fails
+func PingCheck(p Pinger) CheckFunc {
+	return func(ctx context.Context) Result {
+		if err := p.Ping(ctx); err != nil {
+			return Result{Status: StatusDown, Message: secrets.Redact(err.Error())}
+		}
+		return Result{Status: StatusOK}
+	}
+}
+
+// ProviderDetail is the readiness breakdown of one tenant's provider
+type ProviderDetail struct {
+	Tenant        string          `json:"tenant"`
+	Provider      string          `json:"provider"`
+	State         string          `json:"state"`
+	Identity      string          `json:"identity,omitempty"`
+	MissingScopes []string        `json:"missing_scopes,omitempty"`
+	RateLimit     *RateLimitUsage `json:"rate_limit,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	CheckedAt     time.Time       `json:"checked_at"`
+}
+
+type RateLimitUsage struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+	// Low is set when less than the configured headroom is left
+	Low bool `json:"low"`
+}
+
+// ProviderCheck reports the verification state and rate-limit headroom of
+// every configured provider as of the last verification; probes never call
+// upstream APIs. The check is down only when providers are configured and
+// none of them can serve requests.
+func ProviderCheck(service *vcs.Service, minHeadroomPercent int) CheckFunc {
+	return func(ctx context.Context) Result {
+		statuses := service.ProviderStatuses()
+		if len(statuses) == 0 {
+			return Result{Status: StatusOK, Message: "no providers configured"}
+		}
+
+		result := Result{Status: StatusOK}
+		details := make([]ProviderDetail, 0, len(statuses))
+		usable, degraded := 0, 0
+		for _, status := range statuses {
+			detail := ProviderDetail{
+				Tenant:    status.TenantID,
+				Provider:  string(status.Provider),
+				State:     string(status.State),
+				Error:     secrets.Redact(status.Error),
+				CheckedAt: status.CheckedAt,
+			}
+			if v := status.Verification; v != nil {
+				detail.Identity = v.Identity
+				detail.MissingScopes = v.MissingScopes
+				if rl := v.RateLimit; rl != nil && rl.Limit > 0 {
+					detail.RateLimit = &RateLimitUsage{
+						Limit:     rl.Limit,
+						Remaining: rl.Remaining,
+						ResetAt:   rl.ResetAt,
+						Low:       rl.Remaining*100 < rl.Limit*minHeadroomPercent && (rl.ResetAt.IsZero() || time.Now().Before(rl.ResetAt)),
+					}
+				}
+			}
+			details = append(details, detail)
+
+			if status.State != vcs.ProviderStateUnavailable {
+				usable++
+			}
+			if status.State != vcs.ProviderStateOK || (detail.RateLimit != nil && detail.RateLimit.Low) {
+				degraded++
+			}
+		}
+		result.Details = details
+
+		switch {
+		case usable == 0:
+			result.Status = StatusDown
+			result.Message = "no provider is available"
+		case degraded > 0:
+			result.Status = StatusDegraded
+			result.Message = fmt.Sprintf("%d of %d providers degraded or unavailable", degraded, len(statuses))
+		}
+		return result
+	}
+}
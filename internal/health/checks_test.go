@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domain "devmetrics/internal/domain/vcs"
+	"devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// stubProvider only implements Verify
+type stubProvider struct {
+	domain.Provider
+	verification *domain.Verification
+	err          error
+}
+
+func (p stubProvider) Verify(context.Context) (*domain.Verification, error) {
+	return p.verification, p.err
+}
+
+func verified(remaining int) stubProvider {
+	return stubProvider{verification: &domain.Verification{
+		Identity:  "bot",
+		RateLimit: &domain.RateLimit{Limit: 100, Remaining: remaining, ResetAt: time.Now().Add(time.Hour)},
+	}}
+}
+
+func TestProviderCheck(t *testing.T) {
+	down := stubProvider{err: errors.New("401 Unauthorized")}
+	missingScopes := stubProvider{verification: &domain.Verification{Identity: "bot", MissingScopes: []string{"repo"}}}
+
+	tests := []struct {
+		name      string
+		providers vcs.Providers
+		want      Status
+	}{
+		{"no providers", vcs.Providers{}, StatusOK},
+		{"all verified", vcs.Providers{"default": {domain.ProviderGitHub: verified(90)}}, StatusOK},
+		{"low rate limit", vcs.Providers{"default": {domain.ProviderGitHub: verified(5)}}, StatusDegraded},
+		{"missing scopes", vcs.Providers{"default": {domain.ProviderGitHub: missingScopes}}, StatusDegraded},
+		{"one unavailable", vcs.Providers{"default": {domain.ProviderGitHub: verified(90), domain.ProviderGitLab: down}}, StatusDegraded},
+		{"all unavailable", vcs.Providers{"default": {domain.ProviderGitHub: down}, "acme": {domain.ProviderGitLab: down}}, StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := vcs.NewService(tt.providers, nil, nil, logger.NewNop())
+			service.VerifyProviders(context.Background())
+
+			result := ProviderCheck(service, 10)(context.Background())
+			if result.Status != tt.want {
+				t.Errorf("Status = %s (%s), want %s", result.Status, result.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderCheckUsesLastVerification(t *testing.T) {
+	calls := 0
+	service := vcs.NewService(vcs.Providers{"default": {domain.ProviderGitHub: countingProvider{calls: &calls}}}, nil, nil, logger.NewNop())
+	service.VerifyProviders(context.Background())
+
+	check := ProviderCheck(service, 10)
+	check(context.Background())
+	check(context.Background())
+	if calls != 1 {
+		t.Errorf("Verify called %d times, want only the explicit verification", calls)
+	}
+}
+
+type countingProvider struct {
+	domain.Provider
+	calls *int
+}
+
+func (p countingProvider) Verify(context.Context) (*domain.Verification, error) {
+	*p.calls++
+	return &domain.Verification{Identity: "bot"}, nil
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of a health check
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Result is what a check reports; Details is rendered as-is in the readiness response
+type Result struct {
+	Status  Status
+	Message string
+	Details interface{}
+}
+
+// CheckFunc inspects a single dependency
+type CheckFunc func(ctx context.Context) Result
+
+// Check is a registered dependency check. Only critical checks that are down
+// make the service unready; everything else is reported as degraded.
+type Check struct {
+	Name     string
+	Critical bool
+	Run      CheckFunc
+}
+
+// CheckReport is the latest result of one check
+type CheckReport struct {
+	Name      string
+	Critical  bool
+	Result    Result
+	CheckedAt time.Time
+	Duration  time.Duration
+}
+
+// Report aggregates the results of all checks
+type Report struct {
+	Status Status
+	Checks []CheckReport
+}
+
+// Registry runs the registered dependency checks for the readiness probe
+type Registry struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// NewRegistry creates a registry that gives each check at most timeout to complete
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+	}
+}
+
+// Register adds a check; checks are reported in name order
+func (r *Registry) Register(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+	sort.Slice(r.checks, func(i, j int) bool {
+		return r.checks[i].Name < r.checks[j].Name
+	})
+}
+
+// Check runs every check concurrently and aggregates the results
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := append([]Check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	reports := make([]CheckReport, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			reports[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: reports}
+	for _, check := range reports {
+		switch {
+		case check.Result.Status == StatusOK:
+		case check.Critical && check.Result.Status == StatusDown:
+			report.Status = StatusDown
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, check Check) CheckReport {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	report := CheckReport{
+		Name:      check.Name,
+		Critical:  check.Critical,
+		CheckedAt: time.Now(),
+	}
+
+	done := make(chan Result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- Result{Status: StatusDown, Message: fmt.Sprintf("check panicked: %v", p)}
+			}
+		}()
+		done <- check.Run(ctx)
+	}()
+
+	select {
+	case report.Result = <-done:
+	case <-ctx.Done():
+		report.Result = Result{Status: StatusDown, Message: "check timed out"}
+	}
+	report.Duration = time.Since(report.CheckedAt)
+	return report
+}
@@ -0,0 +1,79 @@
+package health
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func checkWith(name string, critical bool, status Status) Check {
+	return Check{
+		Name:     name,
+		Critical: critical,
+		Run:      func(context.Context) Result { return Result{Status: status} },
+	}
+}
+
+func TestRegistryCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   Status
+	}{
+		{"no checks", nil, StatusOK},
+		{"all ok", []Check{checkWith("a", true, StatusOK), checkWith("b", false, StatusOK)}, StatusOK},
+		{"critical degraded", []Check{checkWith("a", true, StatusDegraded)}, StatusDegraded},
+		{"non-critical down", []Check{checkWith("a", false, StatusDown)}, StatusDegraded},
+		{"critical down", []Check{checkWith("a", false, StatusDegraded), checkWith("b", true, StatusDown)}, StatusDown},
+		{"critical down first", []Check{checkWith("a", true, StatusDown), checkWith("b", false, StatusDegraded)}, StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry(time.Second)
+			for _, check := range tt.checks {
+				registry.Register(check)
+			}
+			if got := registry.Check(context.Background()).Status; got != tt.want {
+				t.Errorf("Status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryCheckOrder(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register(checkWith("secret_store", false, StatusOK))
+	registry.Register(checkWith("oidc_jwks", false, StatusOK))
+	registry.Register(checkWith("vcs_providers", true, StatusOK))
+
+	var names []string
+	for _, check := range registry.Check(context.Background()).Checks {
+		names = append(names, check.Name)
+	}
+	if want := []string{"oidc_jwks", "secret_store", "vcs_providers"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("checks = %v, want %v", names, want)
+	}
+}
+
+func TestRegistryCheckFailures(t *testing.T) {
+	registry := NewRegistry(10 * time.Millisecond)
+	registry.Register(Check{Name: "slow", Critical: true, Run: func(ctx context.Context) Result {
+		time.Sleep(time.Second)
+		return Result{Status: StatusOK}
+	}})
+	registry.Register(Check{Name: "panics", Run: func(context.Context) Result {
+		panic("boom")
+	}})
+
+	report := registry.Check(context.Background())
+	if report.Status != StatusDown {
+		t.Errorf("Status = %s, want %s", report.Status, StatusDown)
+	}
+	want := map[string]string{"panics": "check panicked: boom", "slow": "check timed out"}
+	for _, check := range report.Checks {
+		if check.Result.Status != StatusDown || check.Result.Message != want[check.Name] {
+			t.Errorf("%s = %s %q, want down %q", check.Name, check.Result.Status, check.Result.Message, want[check.Name])
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("secret not found")
+
+// Backend resolves secret names to their values
+type Backend interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// EnvBackend reads secrets from environment variables
+type EnvBackend struct{}
+
+func (EnvBackend) Get(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%w: environment variable %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// FileBackend reads secrets from files below a base directory, such as
+// mounted Kubernetes or Docker secrets. Files are re-read on every lookup so
+// rotated values are picked up without a restart.
+type FileBackend struct {
+	Dir string
+}
+
+func (b FileBackend) Get(_ context.Context, name string) (string, error) {
+	path := filepath.Join(b.Dir, filepath.Clean("/"+name))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: file %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading secret file %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// StoreBackend reads envelope-encrypted secrets from a Store
+type StoreBackend struct {
+	Store     Store
+	MasterKey *MasterKey
+}
+
+func (b StoreBackend) Get(ctx context.Context, name string) (string, error) {
+	if b.MasterKey == nil {
+		return "", fmt.Errorf("secret store requires a master key")
+	}
+
+	row, err := b.Store.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := b.MasterKey.Open(name, &row.Envelope)
+	if err != nil {
+		return "", fmt.Errorf("decrypting secret %s: %w", name, err)
+	}
+	return string(plaintext), nil
+}
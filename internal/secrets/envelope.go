@@ -0,0 +1,145 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrDecrypt = errors.New("secret decryption failed")
+
+// Envelope is a secret encrypted with its own data key, which is in turn
+// encrypted ("wrapped") with the master key identified by MasterKeyID
+type Envelope struct {
+	MasterKeyID string `json:"master_key_id"`
+	WrappedKey  []byte `json:"wrapped_key"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// MasterKey is the key-encryption key. It never encrypts secrets directly.
+type MasterKey struct {
+	id  string
+	key []byte
+}
+
+// NewMasterKey creates a master key from 32 raw bytes
+func NewMasterKey(key []byte) (*MasterKey, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(key))
+	}
+
+	sum := sha256.Sum256(key)
+	return &MasterKey{
+		id:  hex.EncodeToString(sum[:4]),
+		key: key,
+	}, nil
+}
+
+// LoadMasterKey reads a base64-encoded master key from a file or, when no
+// file is given, from the value itself (typically an environment variable)
+func LoadMasterKey(value, file string) (*MasterKey, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading master key file: %w", err)
+		}
+		value = string(data)
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("master key must be base64 encoded: %w", err)
+	}
+	return NewMasterKey(key)
+}
+
+// ID identifies the master key without revealing it
+func (m *MasterKey) ID() string {
+	return m.id
+}
+
+// Seal encrypts plaintext under a fresh data key. The ciphertext is bound to
+// name, the tenant-qualified secret name, and only opens under the same name.
+func (m *MasterKey) Seal(name string, plaintext []byte) (*Envelope, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("generating data key: %w", err)
+	}
+
+	ciphertext, err := seal(dataKey, plaintext, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := seal(m.key, dataKey, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		MasterKeyID: m.id,
+		WrappedKey:  wrappedKey,
+		Ciphertext:  ciphertext,
+	}, nil
+}
+
+// Open decrypts an envelope sealed with this master key for the same name
+func (m *MasterKey) Open(name string, envelope *Envelope) ([]byte, error) {
+	if envelope.MasterKeyID != m.id {
+		return nil, fmt.Errorf("%w: sealed with master key %s, have %s", ErrDecrypt, envelope.MasterKeyID, m.id)
+	}
+
+	dataKey, err := open(m.key, envelope.WrappedKey, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, envelope.Ciphertext, []byte(name))
+}
+
+// seal encrypts with AES-256-GCM, authenticating additional data and
+// prefixing the random nonce to the ciphertext
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, data, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func newTestMasterKey(t *testing.T, fill byte) *MasterKey {
+	t.Helper()
+	key, err := NewMasterKey(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestEnvelope(t *testing.T) {
+	masterKey := newTestMasterKey(t, 1)
+	envelope, err := masterKey.Seal("acme/github-token", []byte("ghp_secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := *envelope
+	tampered.Ciphertext = bytes.Clone(envelope.Ciphertext)
+	tampered.Ciphertext[len(tampered.Ciphertext)-1] ^= 1
+
+	truncated := *envelope
+	truncated.WrappedKey = envelope.WrappedKey[:4]
+
+	tests := []struct {
+		name      string
+		masterKey *MasterKey
+		open      string
+		envelope  *Envelope
+		wantErr   bool
+	}{
+		{"round trip", masterKey, "acme/github-token", envelope, false},
+		{"other tenant", masterKey, "default/github-token", envelope, true},
+		{"other name", masterKey, "acme/gitlab-token", envelope, true},
+		{"tampered ciphertext", masterKey, "acme/github-token", &tampered, true},
+		{"truncated wrapped key", masterKey, "acme/github-token", &truncated, true},
+		{"other master key", newTestMasterKey(t, 2), "acme/github-token", envelope, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := tt.masterKey.Open(tt.open, tt.envelope)
+			if tt.wantErr {
+				if !errors.Is(err, ErrDecrypt) {
+					t.Errorf("Open error = %v, want ErrDecrypt", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(plaintext) != "ghp_secret" {
+				t.Errorf("Open = %q, want ghp_secret", plaintext)
+			}
+		})
+	}
+}
+
+func TestSealUsesFreshDataKeys(t *testing.T) {
+	masterKey := newTestMasterKey(t, 1)
+	first, err := masterKey.Seal("acme/token", []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := masterKey.Seal("acme/token", []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(first.WrappedKey, second.WrappedKey) || bytes.Equal(first.Ciphertext, second.Ciphertext) {
+		t.Error("sealing the same value twice produced the same envelope")
+	}
+}
+
+func TestNewMasterKeyLength(t *testing.T) {
+	if _, err := NewMasterKey(make([]byte, 16)); err == nil {
+		t.Error("NewMasterKey accepted a 16-byte key")
+	}
+}
@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoMasterKey = errors.New("no master key configured")
+
+// Manager writes and rotates encrypted secrets in a Store
+type Manager struct {
+	store     Store
+	masterKey *MasterKey
+	resolver  *Resolver
+}
+
+func NewManager(store Store, masterKey *MasterKey, resolver *Resolver) *Manager {
+	return &Manager{
+		store:     store,
+		masterKey: masterKey,
+		resolver:  resolver,
+	}
+}
+
+// Put stores or rotates a secret. Cached values are invalidated so the new
+// version is used by the next upstream call.
+func (m *Manager) Put(ctx context.Context, name, value string) (*Row, error) {
+	if m.masterKey == nil {
+		return nil, ErrNoMasterKey
+	}
+
+	envelope, err := m.masterKey.Seal(name, []byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("encrypting secret: %w", err)
+	}
+
+	version := 1
+	if existing, err := m.store.Get(ctx, name); err == nil {
+		version = existing.Version + 1
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	row := &Row{
+		Name:      name,
+		Version:   version,
+		Envelope:  *envelope,
+		UpdatedAt: time.Now(),
+	}
+	if err := m.store.Put(ctx, row); err != nil {
+		return nil, err
+	}
+
+	defaultRedactor.Register(value)
+	m.resolver.Invalidate(Reference{Backend: StoreBackendName, Name: name}.String())
+	return row, nil
+}
+
+// Delete removes a secret
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	if err := m.store.Delete(ctx, name); err != nil {
+		return err
+	}
+	m.resolver.Invalidate(Reference{Backend: StoreBackendName, Name: name}.String())
+	return nil
+}
+
+// List returns stored rows; callers must not expose the envelopes
+func (m *Manager) List(ctx context.Context) ([]*Row, error) {
+	return m.store.List(ctx)
+}
+
+// Backend names used in secret:// references
+const (
+	EnvBackendName   = "env"
+	FileBackendName  = "file"
+	StoreBackendName = "store"
+)
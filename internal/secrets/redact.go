@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secret values in logs and error messages
+const Redacted = "[REDACTED]"
+
+// minRedactLength avoids redacting short values that would mangle unrelated text
+const minRedactLength = 8
+
+// tokenPatterns match well-known credential formats even if they were never
+// registered, e.g. a token pasted into a request parameter
+var tokenPatterns = regexp.MustCompile(
+	`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{20,}\b|\bgithub_pat_[A-Za-z0-9_]{20,}\b|\bglpat-[A-Za-z0-9_\-]{20,}|\bdm_[A-Za-z0-9_\-]{20,}`,
+)
+
+// Redactor removes known secret values from strings
+type Redactor struct {
+	mu     sync.RWMutex
+	values map[string]struct{}
+	sorted []string
+}
+
+func NewRedactor() *Redactor {
+	return &Redactor{
+		values: make(map[string]struct{}),
+	}
+}
+
+// Register adds a secret value to redact
+func (r *Redactor) Register(value string) {
+	if len(value) < minRedactLength {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.values[value]; ok {
+		return
+	}
+	r.values[value] = struct{}{}
+
+	// Longest first so a secret containing another is fully replaced
+	r.sorted = append(r.sorted, value)
+	sort.Slice(r.sorted, func(i, j int) bool {
+		return len(r.sorted[i]) > len(r.sorted[j])
+	})
+}
+
+// Redact replaces registered secrets and well-known token formats in s
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	for _, value := range r.sorted {
+		s = strings.ReplaceAll(s, value, Redacted)
+	}
+	r.mu.RUnlock()
+
+	return tokenPatterns.ReplaceAllString(s, Redacted)
+}
+
+var defaultRedactor = NewRedactor()
+
+// Register adds a secret value to the process-wide redactor
+func Register(value string) {
+	defaultRedactor.Register(value)
+}
+
+// Redact removes secrets known to the process-wide redactor from s
+func Redact(s string) string {
+	return defaultRedactor.Redact(s)
+}
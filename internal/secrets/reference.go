@@ -0,0 +1,40 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scheme prefixes configuration values that refer to a secret instead of containing it
+const Scheme = "secret://"
+
+// Reference points to a secret held by a backend, written as
+// secret://<backend>/<name>, e.g. secret://env/GITHUB_TOKEN,
+// secret://file/github-token or secret://store/payments/github
+type Reference struct {
+	Backend string
+	Name    string
+}
+
+// IsReference reports whether a configuration value is a secret reference
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme)
+}
+
+// ParseReference parses a secret:// reference
+func ParseReference(value string) (Reference, error) {
+	if !IsReference(value) {
+		return Reference{}, fmt.Errorf("not a secret reference")
+	}
+
+	backend, name, ok := strings.Cut(strings.TrimPrefix(value, Scheme), "/")
+	if !ok || backend == "" || name == "" {
+		return Reference{}, fmt.Errorf("secret reference must look like %s<backend>/<name>", Scheme)
+	}
+
+	return Reference{Backend: backend, Name: name}, nil
+}
+
+func (r Reference) String() string {
+	return Scheme + r.Backend + "/" + r.Name
+}
@@ -0,0 +1,106 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenSource returns the current value of a credential. Sources backed by a
+// secret reference re-resolve it once the cache expires, so rotated tokens
+// are used without a restart.
+type TokenSource func(ctx context.Context) (string, error)
+
+// Resolver resolves secret:// references through pluggable backends and
+// registers every value it hands out with the redactor
+type Resolver struct {
+	ttl      time.Duration
+	redactor *Redactor
+
+	mu       sync.RWMutex
+	backends map[string]Backend
+	cache    map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewResolver(ttl time.Duration) *Resolver {
+	return &Resolver{
+		ttl:      ttl,
+		redactor: defaultRedactor,
+		backends: make(map[string]Backend),
+		cache:    make(map[string]cachedSecret),
+	}
+}
+
+// RegisterBackend makes a backend available under secret://<name>/...
+func (r *Resolver) RegisterBackend(name string, backend Backend) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.backends[name] = backend
+}
+
+// Resolve returns the secret a value refers to. Values that are not
+// references are returned unchanged, but are still registered for redaction.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		r.redactor.Register(value)
+		return value, nil
+	}
+
+	r.mu.RLock()
+	cached, ok := r.cache[value]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.value, nil
+	}
+
+	ref, err := ParseReference(value)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.RLock()
+	backend, ok := r.backends[ref.Backend]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown secret backend %q in %s", ref.Backend, ref)
+	}
+
+	secret, err := backend.Get(ctx, ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", ref, err)
+	}
+
+	r.redactor.Register(secret)
+	r.mu.Lock()
+	r.cache[value] = cachedSecret{value: secret, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return secret, nil
+}
+
+// Invalidate drops a cached reference so the next lookup hits the backend
+func (r *Resolver) Invalidate(value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cache, value)
+}
+
+// InvalidateAll drops every cached secret so the next lookups re-read their backends
+func (r *Resolver) InvalidateAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]cachedSecret)
+}
+
+// Source returns a TokenSource for a configuration value
+func (r *Resolver) Source(value string) TokenSource {
+	return func(ctx context.Context) (string, error) {
+		return r.Resolve(ctx, value)
+	}
+}
@@ -0,0 +1,182 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Row is an encrypted secret at rest. Version increments on every rotation.
+type Row struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Envelope  Envelope  `json:"envelope"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists encrypted secret rows
+type Store interface {
+	Get(ctx context.Context, name string) (*Row, error)
+	Put(ctx context.Context, row *Row) error
+	Delete(ctx context.Context, name string) error
+	List(ctx context.Context) ([]*Row, error)
+	// Ping checks that the store can be written to
+	Ping(ctx context.Context) error
+}
+
+// FileStore keeps encrypted rows in a single JSON file, written atomically.
+// Only ciphertext ever touches the disk.
+type FileStore struct {
+	path string
+
+	mu   sync.RWMutex
+	rows map[string]*Row
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore opens the store at path; an empty path keeps rows in memory only
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+		rows: make(map[string]*Row),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secret store: %w", err)
+	}
+
+	var rows []*Row
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("decoding secret store: %w", err)
+	}
+	for _, row := range rows {
+		s.rows[row.Name] = row
+	}
+	return s, nil
+}
+
+func (s *FileStore) Get(_ context.Context, name string) (*Row, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	row, ok := s.rows[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	copied := *row
+	return &copied, nil
+}
+
+func (s *FileStore) Put(_ context.Context, row *Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *row
+	previous, existed := s.rows[row.Name]
+	s.rows[row.Name] = &stored
+
+	if err := s.flush(); err != nil {
+		if existed {
+			s.rows[row.Name] = previous
+		} else {
+			delete(s.rows, row.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) Delete(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.rows[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.rows, name)
+
+	if err := s.flush(); err != nil {
+		s.rows[name] = previous
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) List(_ context.Context) ([]*Row, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := make([]*Row, 0, len(s.rows))
+	for _, row := range s.rows {
+		copied := *row
+		rows = append(rows, &copied)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Name < rows[j].Name
+	})
+	return rows, nil
+}
+
+// Ping checks that the store's directory is still writable
+func (s *FileStore) Ping(_ context.Context) error {
+	if s.path == "" {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".secrets-ping-*")
+	if err != nil {
+		return fmt.Errorf("secret store is not writable: %w", err)
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// flush writes all rows to a temporary file and renames it over the store
+func (s *FileStore) flush() error {
+	if s.path == "" {
+		return nil
+	}
+
+	rows := make([]*Row, 0, len(s.rows))
+	for _, row := range s.rows {
+		rows = append(rows, row)
+	}
+	data, err := json.MarshalIndent(rows, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".secrets-*")
+	if err != nil {
+		return fmt.Errorf("writing secret store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing secret store: %w", err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing secret store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing secret store: %w", err)
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/auth"
+)
+
+// DefaultPolicy is used when no policy file is configured
+func DefaultPolicy() *auth.Policy {
+	return &auth.Policy{
+		Roles: map[string]auth.Role{
+			"admin":  {Scopes: []auth.Scope{auth.ScopeAdmin}},
+			"viewer": {Scopes: []auth.Scope{auth.ScopeReadVCS, auth.ScopeReadMetrics}},
+		},
+	}
+}
+
+// LoadPolicy reads the RBAC policy file, falling back to DefaultPolicy
+func LoadPolicy(cfg config.AuthConfig) (*auth.Policy, error) {
+	if cfg.RBACPolicyFile == "" {
+		return DefaultPolicy(), nil
+	}
+
+	data, err := os.ReadFile(cfg.RBACPolicyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading RBAC policy: %w", err)
+	}
+
+	policy := &auth.Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("decoding RBAC policy: %w", err)
+	}
+
+	for name, role := range policy.Roles {
+		for _, scope := range role.Scopes {
+			if !scope.IsValid() {
+				return nil, fmt.Errorf("RBAC role %q: unknown scope %q", name, scope)
+			}
+		}
+	}
+
+	return policy, nil
+}
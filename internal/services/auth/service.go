@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/auth"
+	"devmetrics/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// Service manages API keys and authenticates requests made with them
+type Service struct {
+	store            auth.KeyStore
+	defaultRateLimit int
+	logger           logger.Logger
+	// revoked are called with the ID of every key revoked at runtime
+	revoked []func(id string)
+}
+
+func NewService(cfg *config.Config, store auth.KeyStore, log logger.Logger) (*Service, error) {
+	s := &Service{
+		store:            store,
+		defaultRateLimit: cfg.Auth.DefaultRateLimit,
+		logger:           log,
+	}
+
+	if err := s.loadStaticKeys(cfg.Auth.APIKeys, config.DefaultTenantID); err != nil {
+		return nil, err
+	}
+	for _, tenantCfg := range cfg.Tenants {
+		if err := s.loadStaticKeys(tenantCfg.APIKeys, tenantCfg.ID); err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", tenantCfg.ID, err)
+		}
+	}
+
+	return s, nil
+}
+
+// loadStaticKeys registers the API keys declared in configuration
+func (s *Service) loadStaticKeys(keys []config.APIKeyConfig, defaultTenantID string) error {
+	for _, keyCfg := range keys {
+		tenantID := keyCfg.Tenant
+		if tenantID == "" {
+			tenantID = defaultTenantID
+		}
+
+		scopes, err := ParseScopes(keyCfg.Scopes)
+		if err != nil {
+			return fmt.Errorf("API key %q: %w", keyCfg.Name, err)
+		}
+
+		key := &auth.APIKey{
+			ID:        "static-" + tenantID + "-" + keyCfg.Name,
+			TenantID:  tenantID,
+			Name:      keyCfg.Name,
+			Hash:      strings.ToLower(keyCfg.Hash),
+			Scopes:    scopes,
+			RateLimit: keyCfg.RateLimit,
+			CreatedAt: time.Now(),
+			Static:    true,
+		}
+		if err := s.store.Create(context.Background(), key); err != nil {
+			return fmt.Errorf("API key %q: %w", keyCfg.Name, err)
+		}
+	}
+	return nil
+}
+
+// Authenticate resolves a raw API key to the principal it represents
+func (s *Service) Authenticate(ctx context.Context, secret string) (*auth.Principal, *auth.APIKey, error) {
+	if secret == "" {
+		return nil, nil, auth.ErrInvalidKey
+	}
+
+	hash := auth.HashKey(secret)
+	key, err := s.store.GetByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, auth.ErrKeyNotFound) {
+			return nil, nil, auth.ErrInvalidKey
+		}
+		return nil, nil, err
+	}
+	if !auth.HashesEqual(key.Hash, hash) || !key.IsActive() {
+		return nil, nil, auth.ErrInvalidKey
+	}
+
+	now := time.Now()
+	key.LastUsedAt = &now
+	if err := s.store.Update(ctx, key); err != nil {
+		logger.FromContext(ctx, s.logger).Warn("Failed to record API key usage",
+			logger.String("key_id", key.ID),
+			logger.Error(err),
+		)
+	}
+
+	return &auth.Principal{
+		Subject:  "apikey:" + key.ID,
+		Name:     key.Name,
+		KeyID:    key.ID,
+		TenantID: key.TenantID,
+		Scopes:   key.Scopes,
+	}, key, nil
+}
+
+// RateLimit returns the requests per minute allowed for a key; 0 means unlimited
+func (s *Service) RateLimit(key *auth.APIKey) int {
+	if key.RateLimit > 0 {
+		return key.RateLimit
+	}
+	return s.defaultRateLimit
+}
+
+// CreateKey issues a new API key for a tenant. The secret is returned only once.
+func (s *Service) CreateKey(ctx context.Context, tenantID, name string, scopes []auth.Scope, rateLimit int) (string, *auth.APIKey, error) {
+	secret, err := auth.GenerateKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("generating API key: %w", err)
+	}
+
+	key := &auth.APIKey{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      name,
+		Hash:      auth.HashKey(secret),
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.Create(ctx, key); err != nil {
+		return "", nil, fmt.Errorf("storing API key: %w", err)
+	}
+
+	logger.FromContext(ctx, s.logger).Info("API key created",
+		logger.String("key_id", key.ID),
+		logger.String("key_name", key.Name),
+		logger.String("tenant", key.TenantID),
+		logger.Any("scopes", key.Scopes),
+	)
+
+	return secret, key, nil
+}
+
+// ListKeys returns a tenant's keys, including revoked ones
+func (s *Service) ListKeys(ctx context.Context, tenantID string) ([]*auth.APIKey, error) {
+	keys, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantKeys := make([]*auth.APIKey, 0, len(keys))
+	for _, key := range keys {
+		if key.TenantID == tenantID {
+			tenantKeys = append(tenantKeys, key)
+		}
+	}
+	return tenantKeys, nil
+}
+
+// RevokeKey disables a key created at runtime. Keys of other tenants are reported as not found.
+func (s *Service) RevokeKey(ctx context.Context, tenantID, id string) (*auth.APIKey, error) {
+	key, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.TenantID != tenantID {
+		return nil, auth.ErrKeyNotFound
+	}
+	if key.Static {
+		return nil, fmt.Errorf("%w: key %q is defined in configuration", ErrStaticKey, key.Name)
+	}
+	if !key.IsActive() {
+		return key, nil
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	if err := s.store.Update(ctx, key); err != nil {
+		return nil, fmt.Errorf("revoking API key: %w", err)
+	}
+	for _, fn := range s.revoked {
+		fn(key.ID)
+	}
+
+	logger.FromContext(ctx, s.logger).Info("API key revoked",
+		logger.String("key_id", key.ID),
+		logger.String("key_name", key.Name),
+	)
+
+	return key, nil
+}
+
+// OnRevoke registers fn to be called with the ID of every key revoked at
+// runtime. It must be called before the service is used.
+func (s *Service) OnRevoke(fn func(id string)) {
+	s.revoked = append(s.revoked, fn)
+}
+
+// ErrStaticKey is returned when attempting to modify a key defined in configuration
+var ErrStaticKey = errors.New("static API key cannot be modified")
+
+// ParseScopes converts scope names into validated scopes
+func ParseScopes(names []string) ([]auth.Scope, error) {
+	scopes := make([]auth.Scope, 0, len(names))
+	for _, name := range names {
+		scope := auth.Scope(name)
+		if !scope.IsValid() {
+			return nil, fmt.Errorf("unknown scope %q", name)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// KeyProvider resolves JWT signing keys by key ID
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// TokenVerifier validates identity provider JWTs and maps their claims to a
+// principal whose scopes come from the RBAC policy
+type TokenVerifier struct {
+	cfg    config.OIDCConfig
+	keys   KeyProvider
+	policy *auth.Policy
+}
+
+func NewTokenVerifier(cfg config.AuthConfig, keys KeyProvider, policy *auth.Policy) *TokenVerifier {
+	return &TokenVerifier{
+		cfg:    cfg.OIDC,
+		keys:   keys,
+		policy: policy,
+	}
+}
+
+// Enabled reports whether bearer JWTs are accepted
+func (v *TokenVerifier) Enabled() bool {
+	return v.cfg.Enabled
+}
+
+// LooksLikeJWT reports whether a bearer credential is shaped like a JWS compact token
+func LooksLikeJWT(credential string) bool {
+	return strings.Count(credential, ".") == 2 && !strings.HasPrefix(credential, auth.KeyPrefix)
+}
+
+// Verify checks the token signature, issuer, audience and expiry and builds
+// a principal. Its TenantID is empty when the token has no string tenant
+// claim; the caller decides whether the default tenant applies.
+func (v *TokenVerifier) Verify(ctx context.Context, raw string) (*auth.Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+	}
+	if v.cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(v.cfg.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidToken)
+	}
+
+	roles := v.mapRoles(claimStrings(claims, v.cfg.RolesClaim))
+	name, _ := claims["email"].(string)
+	if name == "" {
+		name, _ = claims["preferred_username"].(string)
+	}
+
+	tenantID, _ := claims[v.cfg.TenantClaim].(string)
+
+	return &auth.Principal{
+		Subject:  "user:" + subject,
+		Name:     name,
+		TenantID: tenantID,
+		Roles:    roles,
+		Scopes:   v.policy.ScopesFor(roles),
+	}, nil
+}
+
+// mapRoles translates claim values into RBAC role names
+func (v *TokenVerifier) mapRoles(values []string) []string {
+	roles := make([]string, 0, len(values))
+	for _, value := range values {
+		if mapped, ok := v.cfg.RoleMapping[value]; ok {
+			value = mapped
+		}
+		if _, known := v.policy.Roles[value]; known {
+			roles = append(roles, value)
+		}
+	}
+	return roles
+}
+
+// claimStrings reads a string or string list claim at a dot-separated path,
+// e.g. "groups" or "realm_access.roles"
+func claimStrings(claims jwt.MapClaims, path string) []string {
+	var current interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+
+	switch value := current.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
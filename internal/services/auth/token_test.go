@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/auth"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
+
+func newTestVerifier(t *testing.T) (*TokenVerifier, *ecdsa.PrivateKey) {
+	t.Helper()
+	signing, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.AuthConfig{OIDC: config.OIDCConfig{
+		Enabled:     true,
+		Issuer:      "https://idp.example.com",
+		Audience:    "devmetrics",
+		RolesClaim:  "realm_access.roles",
+		RoleMapping: map[string]string{"engineering": "viewer"},
+	}}
+	return NewTokenVerifier(cfg, staticKeys{"k1": &signing.PublicKey}, DefaultPolicy()), signing
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":          "https://idp.example.com",
+		"aud":          "devmetrics",
+		"sub":          "u-1",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"email":        "jane@example.com",
+		"realm_access": map[string]interface{}{"roles": []interface{}{"engineering", "unknown-group"}},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	verifier, signing := newTestVerifier(t)
+
+	principal, err := verifier.Verify(context.Background(), sign(t, signing, "k1", validClaims()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &auth.Principal{
+		Subject: "user:u-1",
+		Name:    "jane@example.com",
+		Roles:   []string{"viewer"},
+		Scopes:  []auth.Scope{auth.ScopeReadVCS, auth.ScopeReadMetrics},
+	}
+	if !reflect.DeepEqual(principal, want) {
+		t.Errorf("Verify = %+v, want %+v", principal, want)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	verifier, signing := newTestVerifier(t)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	with := func(change func(jwt.MapClaims)) jwt.MapClaims {
+		claims := validClaims()
+		change(claims)
+		return claims
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"other issuer", sign(t, signing, "k1", with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }))},
+		{"other audience", sign(t, signing, "k1", with(func(c jwt.MapClaims) { c["aud"] = "other" }))},
+		{"expired", sign(t, signing, "k1", with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }))},
+		{"no expiry", sign(t, signing, "k1", with(func(c jwt.MapClaims) { delete(c, "exp") }))},
+		{"no subject", sign(t, signing, "k1", with(func(c jwt.MapClaims) { delete(c, "sub") }))},
+		{"unknown key ID", sign(t, signing, "k2", validClaims())},
+		{"signed by another key", sign(t, other, "k1", validClaims())},
+		{"symmetric algorithm", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+			token.Header["kid"] = "k1"
+			signed, _ := token.SignedString([]byte("secret"))
+			return signed
+		}()},
+		{"not a token", "a.b.c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.Verify(context.Background(), tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Verify error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	claims := jwt.MapClaims{
+		"groups": []interface{}{"a", 1, "b"},
+		"scope":  "read write",
+		"nested": map[string]interface{}{"roles": []interface{}{"x"}},
+	}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"groups", []string{"a", "b"}},
+		{"scope", []string{"read", "write"}},
+		{"nested.roles", []string{"x"}},
+		{"nested.missing", nil},
+		{"groups.roles", nil},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		if got := claimStrings(claims, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("claimStrings(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLooksLikeJWT(t *testing.T) {
+	tests := []struct {
+		credential string
+		want       bool
+	}{
+		{"eyJh.eyJz.sig", true},
+		{"dm_abc.def.ghi", false},
+		{"dm_abcdef", false},
+		{"a.b", false},
+	}
+	for _, tt := range tests {
+		if got := LooksLikeJWT(tt.credential); got != tt.want {
+			t.Errorf("LooksLikeJWT(%q) = %v, want %v", tt.credential, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package identity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"devmetrics/internal/domain/identity"
+	"devmetrics/internal/domain/vcs"
+)
+
+// Display name sources, in increasing precedence
+const (
+	nameFromUser = iota + 1
+	nameFromMailmap
+	nameFromOverride
+)
+
+type preferredName struct {
+	name     string
+	priority int
+}
+
+// directory links the aliases seen in one tenant's data. Aliases are keyed
+// as "email:<address>", "login:<provider>:<login>", "name:<full name>" and
+// "override:<id>"; linked aliases form one person.
+type directory struct {
+	// labels keeps the original spelling of every alias seen
+	labels map[string]string
+	// links are the pairs observed in provider data, replayed on rebuild
+	links map[[2]string]bool
+	// names counts the author names seen with each alias
+	names map[string]map[string]int
+	// userNames are display names reported by provider user APIs
+	userNames map[string]string
+	// lookedUp records accounts already looked up, keyed by login alias
+	lookedUp map[string]bool
+
+	overrides []identity.Override
+	mailmap   []identity.MailmapEntry
+
+	parent    map[string]string
+	preferred map[string]preferredName
+	// people maps every alias to its person; nil when it must be rebuilt
+	people map[string]*identity.Person
+	// byID indexes people by ID, rebuilt with people
+	byID map[string]*identity.Person
+}
+
+func newDirectory() *directory {
+	d := &directory{
+		labels:    make(map[string]string),
+		links:     make(map[[2]string]bool),
+		names:     make(map[string]map[string]int),
+		userNames: make(map[string]string),
+		lookedUp:  make(map[string]bool),
+	}
+	d.rebuild()
+	return d
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func loginKey(provider vcs.ProviderType, login string) string {
+	return "login:" + string(provider) + ":" + strings.ToLower(strings.TrimSpace(login))
+}
+
+func nameKey(name string) string {
+	return "name:" + strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// isFullName reports whether a name is specific enough to link aliases by;
+// single words such as "admin" or "john" are shared by too many people
+func isFullName(name string) bool {
+	return len(strings.Fields(name)) >= 2
+}
+
+// observe records an author and returns the alias identifying them. The
+// login may come from the provider or from a noreply e-mail address.
+func (d *directory) observe(providerType vcs.ProviderType, email, login, name string) string {
+	var keys []string
+	if login != "" {
+		keys = append(keys, d.add(loginKey(providerType, login), login))
+	}
+	if email != "" {
+		keys = append(keys, d.add(emailKey(email), email))
+		if account, ok := identity.NoreplyAccount(email); ok {
+			keys = append(keys, d.add(loginKey(account.Provider, account.Login), account.Login))
+		}
+	}
+	name = strings.TrimSpace(name)
+	if name != "" && (len(keys) == 0 || isFullName(name)) {
+		keys = append(keys, d.add(nameKey(name), name))
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+
+	if name != "" && name != login {
+		if d.names[keys[0]] == nil {
+			d.names[keys[0]] = make(map[string]int)
+		}
+		d.names[keys[0]][name]++
+	}
+	for _, key := range keys[1:] {
+		d.link(keys[0], key)
+	}
+	return keys[0]
+}
+
+// observeUser records the result of a provider user lookup
+func (d *directory) observeUser(providerType vcs.ProviderType, user *vcs.User) {
+	key := d.add(loginKey(providerType, user.Login), user.Login)
+	if user.Email != "" {
+		d.link(key, d.add(emailKey(user.Email), user.Email))
+	}
+	if user.Name != "" {
+		d.userNames[key] = user.Name
+		d.prefer(key, user.Name, nameFromUser)
+		if isFullName(user.Name) {
+			d.link(key, d.add(nameKey(user.Name), user.Name))
+		}
+		d.people = nil
+	}
+}
+
+// pendingLookups returns up to max logins that were never looked up and
+// marks them as looked up
+func (d *directory) pendingLookups(providerType vcs.ProviderType, logins []string, max int) []string {
+	var pending []string
+	for _, login := range logins {
+		key := loginKey(providerType, login)
+		if login == "" || d.lookedUp[key] || len(pending) >= max {
+			continue
+		}
+		d.lookedUp[key] = true
+		pending = append(pending, login)
+	}
+	return pending
+}
+
+func (d *directory) add(key, label string) string {
+	if _, ok := d.labels[key]; !ok {
+		d.labels[key] = label
+		d.parent[key] = key
+		d.people = nil
+	}
+	return key
+}
+
+func (d *directory) link(a, b string) {
+	if a == b {
+		return
+	}
+	pair := [2]string{a, b}
+	if a > b {
+		pair = [2]string{b, a}
+	}
+	if !d.links[pair] {
+		d.links[pair] = true
+		d.union(a, b)
+	}
+}
+
+func (d *directory) find(key string) string {
+	root, ok := d.parent[key]
+	if !ok {
+		d.parent[key] = key
+		return key
+	}
+	if root != key {
+		root = d.find(root)
+		d.parent[key] = root
+	}
+	return root
+}
+
+func (d *directory) union(a, b string) {
+	rootA, rootB := d.find(a), d.find(b)
+	if rootA != rootB {
+		d.parent[rootB] = rootA
+		d.people = nil
+	}
+}
+
+// configure replaces the overrides and mailmap and relinks every alias
+func (d *directory) configure(overrides []identity.Override, mailmap []identity.MailmapEntry) {
+	d.overrides = overrides
+	d.mailmap = mailmap
+	d.rebuild()
+}
+
+func (d *directory) rebuild() {
+	d.parent = make(map[string]string, len(d.labels))
+	d.preferred = make(map[string]preferredName)
+	d.people = nil
+	for key := range d.labels {
+		// Overrides are re-added below; deleted ones must not linger as people
+		if strings.HasPrefix(key, "override:") {
+			delete(d.labels, key)
+			continue
+		}
+		d.parent[key] = key
+	}
+	for pair := range d.links {
+		d.union(pair[0], pair[1])
+	}
+	for key, name := range d.userNames {
+		d.prefer(key, name, nameFromUser)
+	}
+
+	// Entries are matched by commit e-mail; a commit name narrowing the
+	// entry further is not taken into account
+	for _, entry := range d.mailmap {
+		commit := d.add(emailKey(entry.CommitEmail), entry.CommitEmail)
+		if entry.ProperEmail != "" {
+			d.union(commit, d.add(emailKey(entry.ProperEmail), entry.ProperEmail))
+		}
+		if entry.ProperName != "" {
+			d.prefer(commit, entry.ProperName, nameFromMailmap)
+		}
+	}
+
+	for _, override := range d.overrides {
+		key := d.add("override:"+override.ID, override.ID)
+		for _, email := range override.Emails {
+			d.union(key, d.add(emailKey(email), email))
+		}
+		for _, account := range override.Logins {
+			d.union(key, d.add(loginKey(account.Provider, account.Login), account.Login))
+		}
+		if override.Name != "" {
+			d.prefer(key, override.Name, nameFromOverride)
+		}
+	}
+}
+
+func (d *directory) prefer(key, name string, priority int) {
+	if current, ok := d.preferred[key]; !ok || priority > current.priority {
+		d.preferred[key] = preferredName{name: name, priority: priority}
+	}
+}
+
+// person returns the person an alias belongs to
+func (d *directory) person(key string) *identity.Person {
+	if d.people == nil {
+		d.buildPeople()
+	}
+	return d.people[key]
+}
+
+// list returns every known person ordered by name
+func (d *directory) list() []identity.Person {
+	if d.people == nil {
+		d.buildPeople()
+	}
+	seen := make(map[*identity.Person]bool)
+	var people []identity.Person
+	for _, person := range d.people {
+		if !seen[person] {
+			seen[person] = true
+			people = append(people, *person)
+		}
+	}
+	sort.Slice(people, func(i, j int) bool {
+		if !strings.EqualFold(people[i].Name, people[j].Name) {
+			return strings.ToLower(people[i].Name) < strings.ToLower(people[j].Name)
+		}
+		return people[i].ID < people[j].ID
+	})
+	return people
+}
+
+func (d *directory) buildPeople() {
+	components := make(map[string][]string)
+	for key := range d.labels {
+		root := d.find(key)
+		components[root] = append(components[root], key)
+	}
+
+	d.people = make(map[string]*identity.Person, len(d.labels))
+	d.byID = make(map[string]*identity.Person, len(components))
+	for _, keys := range components {
+		sort.Strings(keys)
+		person := d.newPerson(keys)
+		for _, key := range keys {
+			d.people[key] = person
+		}
+		d.byID[person.ID] = person
+	}
+}
+
+// lookup returns the people an alias names: a person ID, an e-mail address,
+// a provider:login account or a login on any provider
+func (d *directory) lookup(alias string) []*identity.Person {
+	if d.people == nil {
+		d.buildPeople()
+	}
+	alias = strings.TrimSpace(alias)
+	if person, ok := d.byID[alias]; ok {
+		return []*identity.Person{person}
+	}
+
+	var keys []string
+	provider, login, qualified := strings.Cut(alias, ":")
+	switch {
+	case strings.Contains(alias, "@"):
+		keys = []string{emailKey(alias)}
+	case qualified && isProvider(vcs.ProviderType(provider)):
+		keys = []string{loginKey(vcs.ProviderType(provider), login)}
+	default:
+		for _, providerType := range providerTypes {
+			keys = append(keys, loginKey(providerType, alias))
+		}
+	}
+
+	var people []*identity.Person
+	for _, key := range keys {
+		if person, ok := d.people[key]; ok {
+			people = append(people, person)
+		}
+	}
+	return people
+}
+
+var providerTypes = []vcs.ProviderType{vcs.ProviderGitHub, vcs.ProviderGitLab, vcs.ProviderBitbucket}
+
+func isProvider(providerType vcs.ProviderType) bool {
+	for _, known := range providerTypes {
+		if providerType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *directory) newPerson(keys []string) *identity.Person {
+	person := &identity.Person{}
+	nameCounts := make(map[string]int)
+	var best preferredName
+	var overrideID string
+
+	for _, key := range keys {
+		kind, value, _ := strings.Cut(key, ":")
+		switch kind {
+		case "email":
+			person.Emails = append(person.Emails, d.labels[key])
+		case "login":
+			provider, _, _ := strings.Cut(value, ":")
+			person.Logins = append(person.Logins, identity.Account{Provider: vcs.ProviderType(provider), Login: d.labels[key]})
+		case "name":
+			if _, ok := nameCounts[d.labels[key]]; !ok {
+				nameCounts[d.labels[key]] = 0
+			}
+		case "override":
+			overrideID = d.labels[key]
+		}
+		for name, count := range d.names[key] {
+			nameCounts[name] += count
+		}
+		if preferred, ok := d.preferred[key]; ok && preferred.priority > best.priority {
+			best = preferred
+		}
+	}
+
+	for name := range nameCounts {
+		person.Names = append(person.Names, name)
+	}
+	sort.Slice(person.Names, func(i, j int) bool {
+		a, b := person.Names[i], person.Names[j]
+		if nameCounts[a] != nameCounts[b] {
+			return nameCounts[a] > nameCounts[b]
+		}
+		return a < b
+	})
+
+	switch {
+	case best.name != "":
+		person.Name = best.name
+	case len(person.Names) > 0:
+		person.Name = person.Names[0]
+	case len(person.Logins) > 0:
+		person.Name = person.Logins[0].Login
+	case len(person.Emails) > 0:
+		person.Name = person.Emails[0]
+	}
+
+	if overrideID != "" {
+		person.ID = overrideID
+	} else {
+		person.ID = personID(keys)
+	}
+	return person
+}
+
+// personID derives a stable ID from the person's first login, else their
+// first e-mail, else their name. keys must be sorted.
+func personID(keys []string) string {
+	anchor := keys[0]
+	for _, prefix := range []string{"login:", "email:"} {
+		if i := sort.Search(len(keys), func(i int) bool { return keys[i] >= prefix }); i < len(keys) && strings.HasPrefix(keys[i], prefix) {
+			anchor = keys[i]
+			break
+		}
+	}
+	sum := sha256.Sum256([]byte(anchor))
+	return "p_" + hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,332 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"devmetrics/internal/domain/identity"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// maxLookupsPerCall bounds the provider user lookups a single listing may
+// trigger; remaining accounts are looked up by later requests
+const maxLookupsPerCall = 20
+
+var (
+	ErrPersonNotFound  = errors.New("person not found")
+	ErrInvalidOverride = errors.New("invalid identity override")
+	ErrInvalidMailmap  = errors.New("invalid mailmap")
+)
+
+// Service resolves commit and pull request authors to canonical people. It
+// links aliases from provider data, provider user APIs, noreply e-mail
+// addresses, each tenant's .mailmap and manual overrides.
+type Service struct {
+	store  identity.Store
+	logger logger.Logger
+
+	mu          sync.Mutex
+	directories map[string]*tenantDirectory
+}
+
+type tenantDirectory struct {
+	mu sync.Mutex
+	*directory
+}
+
+var _ vcs.ContributorResolver = (*Service)(nil)
+
+func NewService(store identity.Store, log logger.Logger) *Service {
+	return &Service{
+		store:       store,
+		logger:      log.With(logger.String("component", "identity")),
+		directories: make(map[string]*tenantDirectory),
+	}
+}
+
+// directory returns the tenant's directory, loading its overrides and mailmap on first use
+func (s *Service) directory(ctx context.Context) (*tenantDirectory, error) {
+	tenantID := tenant.IDFromContext(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dir, ok := s.directories[tenantID]; ok {
+		return dir, nil
+	}
+
+	dir := &tenantDirectory{directory: newDirectory()}
+	if err := s.configure(ctx, tenantID, dir); err != nil {
+		return nil, err
+	}
+	s.directories[tenantID] = dir
+	return dir, nil
+}
+
+func (s *Service) configure(ctx context.Context, tenantID string, dir *tenantDirectory) error {
+	overrides, err := s.store.ListOverrides(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("loading identity overrides: %w", err)
+	}
+	content, err := s.store.GetMailmap(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("loading mailmap: %w", err)
+	}
+	mailmap, err := identity.ParseMailmap(content)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMailmap, err)
+	}
+
+	dir.mu.Lock()
+	defer dir.mu.Unlock()
+	dir.configure(overrides, mailmap)
+	return nil
+}
+
+// ResolveCommits resolves the authors and co-authors of commits
+func (s *Service) ResolveCommits(ctx context.Context, providerType vcs.ProviderType, provider vcs.Provider, commits []vcs.Commit) {
+	keys := make([]string, len(commits))
+	logins := make([]string, len(commits))
+	coAuthorKeys := make([][]string, len(commits))
+	s.resolve(ctx, providerType, provider, func(dir *directory) {
+		for i, commit := range commits {
+			keys[i] = dir.observe(providerType, commit.AuthorEmail, commit.AuthorLogin, commit.AuthorName)
+			logins[i] = commit.AuthorLogin
+			for _, coAuthor := range commit.CoAuthors {
+				coAuthorKeys[i] = append(coAuthorKeys[i], dir.observe(providerType, coAuthor.Email, "", coAuthor.Name))
+			}
+		}
+	}, logins, func(dir *directory) {
+		for i := range commits {
+			commits[i].Contributor = contributor(dir, keys[i])
+			for j, key := range coAuthorKeys[i] {
+				commits[i].CoAuthors[j].Contributor = contributor(dir, key)
+			}
+		}
+	})
+}
+
+// ResolvePullRequests resolves the authors of pull requests and the
+// accounts that reviewed them or were asked to
+func (s *Service) ResolvePullRequests(ctx context.Context, providerType vcs.ProviderType, provider vcs.Provider, prs []vcs.PullRequest) {
+	keys := make([]string, len(prs))
+	logins := make([]string, len(prs))
+	// Authors are looked up first; reviewers take the lookups left over
+	reviewerKeys := make(map[string]string)
+	for i, pr := range prs {
+		logins[i] = pr.AuthorLogin
+		forEachReviewer(&prs[i], func(reviewer *vcs.Reviewer) {
+			if _, ok := reviewerKeys[reviewer.Login]; !ok && reviewer.Login != "" {
+				reviewerKeys[reviewer.Login] = ""
+				logins = append(logins, reviewer.Login)
+			}
+		})
+	}
+
+	s.resolve(ctx, providerType, provider, func(dir *directory) {
+		for i, pr := range prs {
+			keys[i] = dir.observe(providerType, "", pr.AuthorLogin, pr.AuthorName)
+		}
+		for login := range reviewerKeys {
+			reviewerKeys[login] = dir.observe(providerType, "", login, "")
+		}
+	}, logins, func(dir *directory) {
+		for i := range prs {
+			prs[i].Contributor = contributor(dir, keys[i])
+			forEachReviewer(&prs[i], func(reviewer *vcs.Reviewer) {
+				reviewer.Contributor = contributor(dir, reviewerKeys[reviewer.Login])
+			})
+		}
+	})
+}
+
+// forEachReviewer calls fn with the reviewers and requested reviewers of a pull request
+func forEachReviewer(pr *vcs.PullRequest, fn func(*vcs.Reviewer)) {
+	for i := range pr.Reviews {
+		fn(&pr.Reviews[i].Reviewer)
+	}
+	for i := range pr.RequestedReviewers {
+		fn(&pr.RequestedReviewers[i])
+	}
+}
+
+// resolve records the authors, looks up accounts not seen before and then
+// assigns the contributors. Lookups run without holding the directory lock.
+func (s *Service) resolve(
+	ctx context.Context,
+	providerType vcs.ProviderType,
+	provider vcs.Provider,
+	observe func(*directory),
+	logins []string,
+	assign func(*directory),
+) {
+	dir, err := s.directory(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Warn("Identity resolution unavailable", logger.Error(err))
+		return
+	}
+
+	dir.mu.Lock()
+	observe(dir.directory)
+	pending := dir.pendingLookups(providerType, logins, maxLookupsPerCall)
+	dir.mu.Unlock()
+
+	var users []*vcs.User
+	for _, login := range pending {
+		user, err := provider.GetUser(ctx, login)
+		if err != nil {
+			logger.FromContext(ctx, s.logger).Debug("User lookup failed",
+				logger.String("provider", string(providerType)),
+				logger.String("login", login),
+				logger.Error(err),
+			)
+			continue
+		}
+		users = append(users, user)
+	}
+
+	dir.mu.Lock()
+	defer dir.mu.Unlock()
+	for _, user := range users {
+		dir.observeUser(providerType, user)
+	}
+	assign(dir.directory)
+}
+
+func contributor(dir *directory, key string) *vcs.Contributor {
+	if key == "" {
+		return nil
+	}
+	person := dir.person(key)
+	if person == nil {
+		return nil
+	}
+	return &vcs.Contributor{ID: person.ID, Name: person.Name}
+}
+
+// People returns every person seen in the tenant's data or declared by an override
+func (s *Service) People(ctx context.Context) ([]identity.Person, error) {
+	dir, err := s.directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir.mu.Lock()
+	defer dir.mu.Unlock()
+	return dir.list(), nil
+}
+
+// Person returns a person by ID
+func (s *Service) Person(ctx context.Context, id string) (*identity.Person, error) {
+	people, err := s.People(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range people {
+		if people[i].ID == id {
+			return &people[i], nil
+		}
+	}
+	return nil, ErrPersonNotFound
+}
+
+// Members resolves member aliases to people. Aliases are person IDs, e-mail
+// addresses, provider:login accounts or logins on any provider; those not
+// seen in any data yet match nobody.
+func (s *Service) Members(ctx context.Context, aliases []string) ([]identity.Person, error) {
+	dir, err := s.directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir.mu.Lock()
+	defer dir.mu.Unlock()
+	seen := make(map[string]bool)
+	people := []identity.Person{}
+	for _, alias := range aliases {
+		for _, person := range dir.lookup(alias) {
+			if !seen[person.ID] {
+				seen[person.ID] = true
+				people = append(people, *person)
+			}
+		}
+	}
+	return people, nil
+}
+
+func (s *Service) ContributorIDs(ctx context.Context, aliases []string) map[string]bool {
+	ids := make(map[string]bool)
+	people, err := s.Members(ctx, aliases)
+	if err != nil {
+		logger.FromContext(ctx, s.logger).Warn("Identity resolution unavailable", logger.Error(err))
+		return ids
+	}
+	for _, person := range people {
+		ids[person.ID] = true
+	}
+	return ids
+}
+
+func (s *Service) ListOverrides(ctx context.Context) ([]identity.Override, error) {
+	return s.store.ListOverrides(ctx, tenant.IDFromContext(ctx))
+}
+
+// PutOverride creates or replaces an override and relinks the tenant's people
+func (s *Service) PutOverride(ctx context.Context, override identity.Override) (identity.Override, error) {
+	override.ID = strings.TrimSpace(override.ID)
+	if override.ID == "" {
+		return identity.Override{}, fmt.Errorf("%w: id is required", ErrInvalidOverride)
+	}
+	if len(override.Emails) == 0 && len(override.Logins) == 0 {
+		return identity.Override{}, fmt.Errorf("%w: at least one e-mail or login is required", ErrInvalidOverride)
+	}
+	for _, account := range override.Logins {
+		if account.Provider == "" || account.Login == "" {
+			return identity.Override{}, fmt.Errorf("%w: logins need a provider and a login", ErrInvalidOverride)
+		}
+	}
+	override.UpdatedAt = time.Now()
+
+	tenantID := tenant.IDFromContext(ctx)
+	if err := s.store.PutOverride(ctx, tenantID, override); err != nil {
+		return identity.Override{}, err
+	}
+	return override, s.reconfigure(ctx)
+}
+
+// DeleteOverride removes an override and relinks the tenant's people
+func (s *Service) DeleteOverride(ctx context.Context, id string) error {
+	if err := s.store.DeleteOverride(ctx, tenant.IDFromContext(ctx), id); err != nil {
+		return err
+	}
+	return s.reconfigure(ctx)
+}
+
+func (s *Service) Mailmap(ctx context.Context) (string, error) {
+	return s.store.GetMailmap(ctx, tenant.IDFromContext(ctx))
+}
+
+// PutMailmap replaces the tenant's .mailmap after checking that it parses
+func (s *Service) PutMailmap(ctx context.Context, content string) ([]identity.MailmapEntry, error) {
+	entries, err := identity.ParseMailmap(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMailmap, err)
+	}
+	if err := s.store.PutMailmap(ctx, tenant.IDFromContext(ctx), content); err != nil {
+		return nil, err
+	}
+	return entries, s.reconfigure(ctx)
+}
+
+func (s *Service) reconfigure(ctx context.Context) error {
+	dir, err := s.directory(ctx)
+	if err != nil {
+		return err
+	}
+	return s.configure(ctx, tenant.IDFromContext(ctx), dir)
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// maxBuckets bounds the time series a single query may produce
+const maxBuckets = 400
+
+// Interval is the width of the buckets a time series is split into
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+)
+
+// start returns the start of the bucket containing t. Buckets are aligned in
+// UTC; weeks start on Monday.
+func (i Interval) start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch i {
+	case IntervalWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func (i Interval) next(start time.Time) time.Time {
+	switch i {
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// bucketStarts returns the start of every bucket overlapping [since, until]
+func bucketStarts(interval Interval, since, until time.Time) ([]time.Time, error) {
+	var starts []time.Time
+	for start := interval.start(since); !start.After(until); start = interval.next(start) {
+		if len(starts) == maxBuckets {
+			return nil, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("more than %d %s buckets requested, use a wider interval", maxBuckets, interval))
+		}
+		starts = append(starts, start)
+	}
+	return starts, nil
+}
+
+// inRange reports whether t is set and falls within [since, until]
+func inRange(t *time.Time, since, until time.Time) bool {
+	return t != nil && !t.Before(since) && !t.After(until)
+}
@@ -0,0 +1,362 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/glob"
+)
+
+// KnowledgeThresholds tune how ownership is weighted and when knowledge counts as concentrated
+type KnowledgeThresholds struct {
+	// HalfLife is the age at which a change counts half towards ownership
+	HalfLife time.Duration
+	// Coverage is the share of a path's ownership, between 0 and 1, that the
+	// authors making up its bus factor hold together
+	Coverage float64
+	// InactiveAfter is how long after their last commit an author counts as gone
+	InactiveAfter time.Duration
+	// RiskThreshold flags paths whose bus factor is at most this
+	RiskThreshold int
+}
+
+// KnowledgeAnalyzer computes knowledge distribution reports with configured defaults
+type KnowledgeAnalyzer struct {
+	defaults KnowledgeThresholds
+}
+
+func NewKnowledgeAnalyzer(cfg *config.Config) *KnowledgeAnalyzer {
+	busFactor := cfg.Metrics.BusFactor
+	return &KnowledgeAnalyzer{
+		defaults: KnowledgeThresholds{
+			HalfLife:      days(busFactor.HalfLifeDays),
+			Coverage:      float64(busFactor.CoveragePercent) / 100,
+			InactiveAfter: days(busFactor.InactiveDays),
+			RiskThreshold: busFactor.RiskThreshold,
+		},
+	}
+}
+
+// Defaults returns the configured thresholds
+func (a *KnowledgeAnalyzer) Defaults() KnowledgeThresholds {
+	return a.defaults
+}
+
+// KnowledgeQuery describes a knowledge distribution report over commits with loaded files
+type KnowledgeQuery struct {
+	KnowledgeThresholds
+	// Until is the reference time for recency weights and author activity
+	Until time.Time
+	// Include keeps only matching paths; empty keeps every path
+	Include glob.Set
+	Exclude glob.Set
+	// Limit caps the listed files and directories
+	Limit int
+	// Depth caps the directory tree below the root
+	Depth int
+}
+
+func (q KnowledgeQuery) includes(p string) bool {
+	return HotspotQuery{Include: q.Include, Exclude: q.Exclude}.includes(p)
+}
+
+// Owner is an author's share of a path's ownership
+type Owner struct {
+	// Key identifies the author, see authorKey
+	Key   string
+	Name  string
+	Email string
+	// Share is the author's part of the path's weighted changes, between 0 and 1
+	Share float64
+	// LastCommitAt is the author's last commit in the report window, to any path
+	LastCommitAt time.Time
+	// Inactive is set when LastCommitAt is longer ago than the inactivity threshold
+	Inactive bool
+}
+
+// PathKnowledge describes how the knowledge of a file or directory is spread
+type PathKnowledge struct {
+	Path string
+	// Files counts the files below a directory; 1 for a file
+	Files int
+	// BusFactor is the fewest authors holding the coverage share together
+	BusFactor int
+	// AtRisk is set when the bus factor is at most the risk threshold
+	AtRisk bool
+	// Owners are ordered by share
+	Owners []Owner
+}
+
+// TopOwner returns the author with the largest share; the path must have owners
+func (k PathKnowledge) TopOwner() Owner {
+	return k.Owners[0]
+}
+
+// KnowledgeNode is a directory with the knowledge of every file below it
+type KnowledgeNode struct {
+	PathKnowledge
+	// Children are ordered by path; directories below the depth limit are left out
+	Children []*KnowledgeNode
+}
+
+// KnowledgeReport describes ownership and bus factor of a repository and its paths
+type KnowledgeReport struct {
+	// Commits counts the commits that touched an included path
+	Commits int
+	// Repository holds the knowledge of every included file
+	Repository PathKnowledge
+	// AtRiskFiles and Orphaned list the most valuable files at risk and the
+	// files whose top owner is inactive, capped at the query limit
+	AtRiskFiles       []PathKnowledge
+	AtRiskFileCount   int
+	Orphaned          []PathKnowledge
+	OrphanedCount     int
+	AtRiskDirectories []PathKnowledge
+	// Files counts the files still present at the end of the window
+	Files int
+	Tree  *KnowledgeNode
+}
+
+// knowledgeAccumulator collects the weighted changes of a file or directory per author
+type knowledgeAccumulator struct {
+	path    string
+	weights map[string]float64
+	files   int
+}
+
+func newKnowledgeAccumulator(p string) *knowledgeAccumulator {
+	return &knowledgeAccumulator{path: p, weights: make(map[string]float64)}
+}
+
+func (a *knowledgeAccumulator) total() float64 {
+	total := 0.0
+	for _, weight := range a.weights {
+		total += weight
+	}
+	return total
+}
+
+// author is what is known about a commit author across the window
+type author struct {
+	name, email  string
+	lastCommitAt time.Time
+}
+
+// Knowledge computes each author's share of the files changed by commits,
+// weighting changed lines by recency, and rolls the shares up into
+// directories. Renamed files keep their history and removed files are left
+// out. Only the commits in the window are known, so history before it
+// doesn't count towards ownership.
+func (a *KnowledgeAnalyzer) Knowledge(commits []vcs.Commit, query KnowledgeQuery) *KnowledgeReport {
+	ordered := append([]vcs.Commit(nil), commits...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CommittedAt.Before(ordered[j].CommittedAt)
+	})
+
+	authors := make(map[string]*author)
+	files := make(map[string]*knowledgeAccumulator)
+	touched := 0
+	for _, commit := range ordered {
+		key := authorKey(commit)
+		if authors[key] == nil {
+			authors[key] = &author{}
+		}
+		// Oldest first, so the latest name, e-mail and commit win
+		authors[key].name, authors[key].email = commitAuthorName(commit), commit.AuthorEmail
+		authors[key].lastCommitAt = commit.CommittedAt
+
+		weight := decay(query.Until.Sub(commit.CommittedAt), query.HalfLife)
+		included := false
+		for _, change := range commit.Files {
+			if !query.includes(change.Path) {
+				continue
+			}
+			included = true
+
+			file := files[change.Path]
+			if change.PreviousPath != "" && file == nil {
+				if previous, ok := files[change.PreviousPath]; ok {
+					delete(files, change.PreviousPath)
+					previous.path = change.Path
+					file = previous
+				}
+			}
+			if change.Status == vcs.FileRemoved {
+				delete(files, change.Path)
+				continue
+			}
+			if file == nil {
+				file = newKnowledgeAccumulator(change.Path)
+			}
+			files[change.Path] = file
+
+			// Changes without line counts, such as binary files or pure
+			// renames, still count as one line
+			lines := math.Max(float64(change.Additions+change.Deletions), 1)
+			file.weights[key] += lines * weight
+		}
+		if included {
+			touched++
+		}
+	}
+
+	dirs := make(map[string]*knowledgeAccumulator)
+	report := &KnowledgeReport{Commits: touched, Files: len(files)}
+	var fileKnowledge []PathKnowledge
+	fileWeights := make(map[string]float64, len(files))
+	for p, file := range files {
+		file.files = 1
+		knowledge := newPathKnowledge(file, authors, query)
+		fileKnowledge = append(fileKnowledge, knowledge)
+		fileWeights[p] = file.total()
+
+		for _, dir := range directories(p) {
+			if dirs[dir] == nil {
+				dirs[dir] = newKnowledgeAccumulator(dir)
+			}
+			dirs[dir].files++
+			for key, w := range file.weights {
+				dirs[dir].weights[key] += w
+			}
+		}
+	}
+
+	// Files at risk are listed by how much work they hold, so that the
+	// ones most costly to lose come first
+	byWeight := func(items []PathKnowledge) {
+		sort.Slice(items, func(i, j int) bool {
+			wi, wj := fileWeights[items[i].Path], fileWeights[items[j].Path]
+			if wi != wj {
+				return wi > wj
+			}
+			return items[i].Path < items[j].Path
+		})
+	}
+	for _, knowledge := range fileKnowledge {
+		if knowledge.AtRisk {
+			report.AtRiskFiles = append(report.AtRiskFiles, knowledge)
+		}
+		if len(knowledge.Owners) > 0 && knowledge.TopOwner().Inactive {
+			report.Orphaned = append(report.Orphaned, knowledge)
+		}
+	}
+	report.AtRiskFileCount, report.OrphanedCount = len(report.AtRiskFiles), len(report.Orphaned)
+	byWeight(report.AtRiskFiles)
+	byWeight(report.Orphaned)
+	report.AtRiskFiles = capped(report.AtRiskFiles, query.Limit)
+	report.Orphaned = capped(report.Orphaned, query.Limit)
+
+	for p, dir := range dirs {
+		if p == rootPath {
+			continue
+		}
+		if knowledge := newPathKnowledge(dir, authors, query); knowledge.AtRisk {
+			report.AtRiskDirectories = append(report.AtRiskDirectories, knowledge)
+		}
+	}
+	// Directories holding the most files come first
+	sort.Slice(report.AtRiskDirectories, func(i, j int) bool {
+		a, b := report.AtRiskDirectories[i], report.AtRiskDirectories[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Path < b.Path
+	})
+	report.AtRiskDirectories = capped(report.AtRiskDirectories, query.Limit)
+
+	if root, ok := dirs[rootPath]; ok {
+		report.Repository = newPathKnowledge(root, authors, query)
+		children := make(map[string][]string)
+		for p := range dirs {
+			if p != rootPath {
+				parent := parentDirectory(p)
+				children[parent] = append(children[parent], p)
+			}
+		}
+		report.Tree = knowledgeTree(root, dirs, children, authors, query, query.Depth)
+	} else {
+		report.Repository = PathKnowledge{Path: rootPath}
+	}
+	return report
+}
+
+// newPathKnowledge ranks the owners of a path and derives its bus factor
+func newPathKnowledge(acc *knowledgeAccumulator, authors map[string]*author, query KnowledgeQuery) PathKnowledge {
+	knowledge := PathKnowledge{Path: acc.path, Files: acc.files}
+	total := acc.total()
+	if total == 0 {
+		return knowledge
+	}
+	for key, weight := range acc.weights {
+		a := authors[key]
+		knowledge.Owners = append(knowledge.Owners, Owner{
+			Key:          key,
+			Name:         a.name,
+			Email:        a.email,
+			Share:        weight / total,
+			LastCommitAt: a.lastCommitAt,
+			Inactive:     query.Until.Sub(a.lastCommitAt) > query.InactiveAfter,
+		})
+	}
+	sort.Slice(knowledge.Owners, func(i, j int) bool {
+		if knowledge.Owners[i].Share != knowledge.Owners[j].Share {
+			return knowledge.Owners[i].Share > knowledge.Owners[j].Share
+		}
+		return knowledge.Owners[i].Key < knowledge.Owners[j].Key
+	})
+
+	covered := 0.0
+	for _, owner := range knowledge.Owners {
+		knowledge.BusFactor++
+		covered += owner.Share
+		// Shares rarely add up to exactly 1
+		if covered >= query.Coverage-1e-9 {
+			break
+		}
+	}
+	knowledge.AtRisk = knowledge.BusFactor <= query.RiskThreshold
+	return knowledge
+}
+
+// knowledgeTree builds the node of a directory and its subdirectories down to depth levels
+func knowledgeTree(acc *knowledgeAccumulator, dirs map[string]*knowledgeAccumulator, children map[string][]string, authors map[string]*author, query KnowledgeQuery, depth int) *KnowledgeNode {
+	node := &KnowledgeNode{PathKnowledge: newPathKnowledge(acc, authors, query)}
+	if depth == 0 {
+		return node
+	}
+	for _, child := range children[acc.path] {
+		node.Children = append(node.Children, knowledgeTree(dirs[child], dirs, children, authors, query, depth-1))
+	}
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Path < node.Children[j].Path
+	})
+	return node
+}
+
+// minDecay keeps the weight of old changes above zero, which a short half
+// life would otherwise underflow to, leaving their files without owners
+const minDecay = 1e-9
+
+// decay halves a weight every halfLife of age, down to minDecay; changes
+// after the reference time count fully
+func decay(age, halfLife time.Duration) float64 {
+	if age <= 0 || halfLife <= 0 {
+		return 1
+	}
+	return math.Max(math.Pow(0.5, float64(age)/float64(halfLife)), minDecay)
+}
+
+// commitAuthorName prefers the resolved person's name over the commit's
+func commitAuthorName(commit vcs.Commit) string {
+	if commit.Contributor != nil && commit.Contributor.Name != "" {
+		return commit.Contributor.Name
+	}
+	return commit.AuthorName
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
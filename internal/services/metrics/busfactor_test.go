@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+func TestDecay(t *testing.T) {
+	tests := []struct {
+		name     string
+		age      time.Duration
+		halfLife time.Duration
+		want     float64
+	}{
+		{"new", 0, time.Hour, 1},
+		{"after the reference time", -time.Hour, time.Hour, 1},
+		{"one half life", time.Hour, time.Hour, 0.5},
+		{"two half lives", 2 * time.Hour, time.Hour, 0.25},
+		{"no half life", 100 * time.Hour, 0, 1},
+		{"far older than the half life", 3 * 365 * 24 * time.Hour, time.Hour, minDecay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decay(tt.age, tt.halfLife); got != tt.want {
+				t.Errorf("decay(%v, %v) = %v, want %v", tt.age, tt.halfLife, got, tt.want)
+			}
+		})
+	}
+}
+
+func knowledgeQuery(until time.Time, halfLife time.Duration) KnowledgeQuery {
+	return KnowledgeQuery{
+		KnowledgeThresholds: KnowledgeThresholds{
+			HalfLife:      halfLife,
+			Coverage:      0.5,
+			InactiveAfter: 36 * time.Hour,
+			RiskThreshold: 1,
+		},
+		Until: until,
+		Depth: 1,
+	}
+}
+
+func TestKnowledge(t *testing.T) {
+	commits := []vcs.Commit{
+		{SHA: "3", AuthorEmail: "carol@example.com", CommittedAt: date(3, 0), Files: []vcs.FileChange{
+			{Path: "web/c.go", Status: vcs.FileAdded, Additions: 5},
+			{Path: "old.go", Status: vcs.FileRemoved, Deletions: 5},
+		}},
+		{SHA: "2", AuthorEmail: "bob@example.com", CommittedAt: date(2, 0), Files: []vcs.FileChange{
+			{Path: "api/a.go", Status: vcs.FileModified, Additions: 10},
+		}},
+		{SHA: "1", AuthorEmail: "alice@example.com", CommittedAt: date(1, 0), Files: []vcs.FileChange{
+			{Path: "api/a.go", Status: vcs.FileAdded, Additions: 30},
+			{Path: "api/b.go", Status: vcs.FileAdded, Additions: 10},
+			{Path: "old.go", Status: vcs.FileAdded, Additions: 5},
+		}},
+	}
+	report := (&KnowledgeAnalyzer{}).Knowledge(commits, knowledgeQuery(date(3, 0), 0))
+
+	if report.Commits != 3 || report.Files != 3 {
+		t.Errorf("report = %d commits, %d files, want 3, 3 without the removed file", report.Commits, report.Files)
+	}
+	if report.AtRiskFileCount != 3 || report.OrphanedCount != 2 {
+		t.Errorf("at risk = %d, orphaned = %d, want 3, 2", report.AtRiskFileCount, report.OrphanedCount)
+	}
+
+	var orphaned []string
+	for _, knowledge := range report.Orphaned {
+		orphaned = append(orphaned, knowledge.Path)
+	}
+	if want := []string{"api/a.go", "api/b.go"}; !reflect.DeepEqual(orphaned, want) {
+		t.Errorf("orphaned = %v, want %v, the most work first", orphaned, want)
+	}
+
+	tests := []struct {
+		name      string
+		knowledge PathKnowledge
+		busFactor int
+		owner     string
+		share     float64
+	}{
+		{"top file", report.Orphaned[0], 1, "alice@example.com", 0.75},
+		{"repository", report.Repository, 1, "alice@example.com", 40.0 / 55},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := tt.knowledge
+			if k.BusFactor != tt.busFactor || !k.AtRisk {
+				t.Errorf("bus factor = %d, at risk %v, want %d, at risk", k.BusFactor, k.AtRisk, tt.busFactor)
+			}
+			if top := k.TopOwner(); top.Key != tt.owner || top.Share != tt.share || !top.Inactive {
+				t.Errorf("top owner = %+v, want inactive %s with %v", top, tt.owner, tt.share)
+			}
+		})
+	}
+
+	if report.Tree == nil || len(report.Tree.Children) != 2 {
+		t.Errorf("tree = %+v, want the root with api and web", report.Tree)
+	}
+}
+
+func TestKnowledgeOldCommits(t *testing.T) {
+	commits := []vcs.Commit{
+		{SHA: "1", AuthorEmail: "alice@example.com", CommittedAt: date(1, 0), Files: []vcs.FileChange{
+			{Path: "main.go", Status: vcs.FileAdded, Additions: 10},
+		}},
+	}
+	// Thousands of half lives later the decay would underflow to zero
+	report := (&KnowledgeAnalyzer{}).Knowledge(commits, knowledgeQuery(date(1, 0).AddDate(3, 0, 0), time.Hour))
+
+	if len(report.Orphaned) != 1 {
+		t.Fatalf("orphaned = %+v, want main.go", report.Orphaned)
+	}
+	if owner := report.Orphaned[0].TopOwner(); owner.Key != "alice@example.com" || owner.Share != 1 {
+		t.Errorf("top owner = %+v, want alice with the whole share", owner)
+	}
+}
@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// CommitCategory groups commits by the kind of change their message declares
+type CommitCategory string
+
+const (
+	CommitFeature CommitCategory = "feature"
+	CommitFix     CommitCategory = "fix"
+	// CommitChore covers the other Conventional Commits types, such as chore,
+	// docs, refactor, test and ci
+	CommitChore  CommitCategory = "chore"
+	CommitMerge  CommitCategory = "merge"
+	CommitRevert CommitCategory = "revert"
+	// CommitUnconventional commits don't follow Conventional Commits
+	CommitUnconventional CommitCategory = "unconventional"
+)
+
+// CommitCategories lists every category in reporting order
+var CommitCategories = []CommitCategory{CommitFeature, CommitFix, CommitChore, CommitMerge, CommitRevert, CommitUnconventional}
+
+// Category classifies a commit with parsed message fields. Merges and
+// reverts take precedence over the declared type.
+func Category(commit vcs.Commit) CommitCategory {
+	switch {
+	case commit.Merge:
+		return CommitMerge
+	case commit.Revert:
+		return CommitRevert
+	case commit.Type == "feat" || commit.Type == "feature":
+		return CommitFeature
+	case commit.Type == "fix":
+		return CommitFix
+	case commit.Type != "":
+		return CommitChore
+	default:
+		return CommitUnconventional
+	}
+}
+
+// CommitQuery describes a commit classification report
+type CommitQuery struct {
+	// Limit caps the listed authors
+	Limit int
+}
+
+// CommitTypeCount counts the commits of a Conventional Commits type
+type CommitTypeCount struct {
+	Type    string
+	Commits int
+}
+
+// AuthorCommits describes the commits credited to one person
+type AuthorCommits struct {
+	Person
+	// Commits counts the commits the person authored
+	Commits int
+	// CoAuthored counts the commits naming the person in a Co-authored-by trailer
+	CoAuthored int
+	// Categories counts authored and co-authored commits by category
+	Categories map[CommitCategory]int
+}
+
+// Credited counts the commits the person authored or co-authored
+func (a AuthorCommits) Credited() int {
+	return a.Commits + a.CoAuthored
+}
+
+// CommitReport classifies commits by their messages and credits their
+// authors and co-authors
+type CommitReport struct {
+	Commits int
+	// Conventional counts the commits with a Conventional Commits type
+	Conventional int
+	Breaking     int
+	// CoAuthored counts the commits with at least one co-author
+	CoAuthored int
+	SignedOff  int
+	Categories map[CommitCategory]int
+	// Types are ordered by commits, most first
+	Types []CommitTypeCount
+	// Authors are ordered by credited commits, most first, and capped at the limit
+	Authors []AuthorCommits
+}
+
+// Share is the share of commits in a category
+func (r *CommitReport) Share(category CommitCategory) float64 {
+	return ratio(r.Categories[category], r.Commits)
+}
+
+// ConventionalRate is the share of commits other than merges that follow
+// Conventional Commits
+func (r *CommitReport) ConventionalRate() float64 {
+	return ratio(r.Conventional, r.Commits-r.Categories[CommitMerge])
+}
+
+// Commits classifies commits with parsed message fields. A co-author who
+// is also the commit's author is only credited once.
+func Commits(commits []vcs.Commit, query CommitQuery) *CommitReport {
+	report := &CommitReport{Commits: len(commits), Categories: make(map[CommitCategory]int)}
+	authors := make(map[string]*AuthorCommits)
+	credit := func(person Person, category CommitCategory) *AuthorCommits {
+		a := authors[person.Key]
+		if a == nil {
+			a = &AuthorCommits{Person: person, Categories: make(map[CommitCategory]int)}
+			authors[person.Key] = a
+		}
+		a.Categories[category]++
+		return a
+	}
+
+	types := make(map[string]int)
+	for _, commit := range commits {
+		category := Category(commit)
+		report.Categories[category]++
+		if commit.Type != "" && !commit.Merge {
+			report.Conventional++
+			types[commit.Type]++
+		}
+		if commit.Breaking {
+			report.Breaking++
+		}
+		if len(commit.SignedOffBy) > 0 {
+			report.SignedOff++
+		}
+
+		author := Person{Key: authorKey(commit), Name: commitAuthorName(commit)}
+		credit(author, category).Commits++
+
+		credited := map[string]bool{author.Key: true}
+		for _, coAuthor := range commit.CoAuthors {
+			person := coAuthorPerson(coAuthor)
+			if credited[person.Key] {
+				continue
+			}
+			credited[person.Key] = true
+			credit(person, category).CoAuthored++
+		}
+		if len(credited) > 1 {
+			report.CoAuthored++
+		}
+	}
+
+	for t, count := range types {
+		report.Types = append(report.Types, CommitTypeCount{Type: t, Commits: count})
+	}
+	sort.Slice(report.Types, func(i, j int) bool {
+		if report.Types[i].Commits != report.Types[j].Commits {
+			return report.Types[i].Commits > report.Types[j].Commits
+		}
+		return report.Types[i].Type < report.Types[j].Type
+	})
+
+	for _, a := range authors {
+		report.Authors = append(report.Authors, *a)
+	}
+	sort.Slice(report.Authors, func(i, j int) bool {
+		a, b := report.Authors[i], report.Authors[j]
+		if a.Credited() != b.Credited() {
+			return a.Credited() > b.Credited()
+		}
+		return a.Key < b.Key
+	})
+	report.Authors = capped(report.Authors, query.Limit)
+	return report
+}
+
+// coAuthorPerson identifies a co-author the way authorKey identifies authors
+func coAuthorPerson(p vcs.CommitPerson) Person {
+	switch {
+	case p.Contributor != nil:
+		name := p.Contributor.Name
+		if name == "" {
+			name = p.Name
+		}
+		return Person{Key: p.Contributor.ID, Name: name}
+	case p.Email != "":
+		return Person{Key: strings.ToLower(p.Email), Name: p.Name}
+	default:
+		return Person{Key: p.Name, Name: p.Name}
+	}
+}
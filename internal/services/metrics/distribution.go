@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DurationStats summarizes a set of durations
+type DurationStats struct {
+	Count  int
+	Median time.Duration
+	P75    time.Duration
+	P90    time.Duration
+}
+
+func newDurationStats(durations []time.Duration) DurationStats {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return DurationStats{
+		Count:  len(sorted),
+		Median: percentile(sorted, 50),
+		P75:    percentile(sorted, 75),
+		P90:    percentile(sorted, 90),
+	}
+}
+
+// percentile interpolates linearly between the closest ranks of sorted
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return sorted[lower] + time.Duration(weight*float64(sorted[upper]-sorted[lower]))
+}
+
+// HistogramBin counts the durations in [Min, Max); a zero Max is unbounded
+type HistogramBin struct {
+	Label string
+	Min   time.Duration
+	Max   time.Duration
+	Count int
+}
+
+// durationBins are the bin edges of duration histograms, from review within
+// the hour to pull requests left open for over a month
+var durationBins = []struct {
+	label string
+	max   time.Duration
+}{
+	{"<1h", time.Hour},
+	{"1h-4h", 4 * time.Hour},
+	{"4h-1d", 24 * time.Hour},
+	{"1d-3d", 3 * 24 * time.Hour},
+	{"3d-7d", 7 * 24 * time.Hour},
+	{"7d-14d", 14 * 24 * time.Hour},
+	{"14d-30d", 30 * 24 * time.Hour},
+	{">30d", 0},
+}
+
+func newHistogram(durations []time.Duration) []HistogramBin {
+	bins := make([]HistogramBin, len(durationBins))
+	var min time.Duration
+	for i, bin := range durationBins {
+		bins[i] = HistogramBin{Label: bin.label, Min: min, Max: bin.max}
+		min = bin.max
+	}
+	for _, d := range durations {
+		for i := range bins {
+			if bins[i].Max == 0 || d < bins[i].Max {
+				bins[i].Count++
+				break
+			}
+		}
+	}
+	return bins
+}
+
+// ratio divides, returning 0 when there is nothing to divide by
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+// median returns the middle value of values, averaging the two middle ones
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// spearman returns the rank correlation of x and y, from -1 to 1. It is nil
+// for fewer than three pairs or when either side is constant.
+func spearman(x, y []float64) *float64 {
+	if len(x) < 3 || len(x) != len(y) {
+		return nil
+	}
+	rx, ry := ranks(x), ranks(y)
+
+	var meanX, meanY float64
+	for i := range rx {
+		meanX += rx[i]
+		meanY += ry[i]
+	}
+	meanX /= float64(len(rx))
+	meanY /= float64(len(ry))
+
+	var cov, varX, varY float64
+	for i := range rx {
+		dx, dy := rx[i]-meanX, ry[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+	r := cov / math.Sqrt(varX*varY)
+	return &r
+}
+
+// ranks returns the 1-based rank of every value; ties share their average rank
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+
+	ranked := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start
+		for end+1 < len(order) && values[order[end+1]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end)/2 + 1
+		for k := start; k <= end; k++ {
+			ranked[order[k]] = rank
+		}
+		start = end + 1
+	}
+	return ranked
+}
+
+// gini measures how unevenly values are spread, from 0 when all are equal
+// to nearly 1 when one holds everything; nil without any positive value
+func gini(values []float64) *float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var total, weighted float64
+	for i, v := range sorted {
+		total += v
+		weighted += float64(i+1) * v
+	}
+	if total == 0 {
+		return nil
+	}
+	n := float64(len(sorted))
+	g := (2*weighted)/(n*total) - (n+1)/n
+	return &g
+}
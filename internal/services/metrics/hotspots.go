@@ -0,0 +1,272 @@
+package metrics
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/glob"
+)
+
+// rootPath names the repository root in directory rollups
+const rootPath = "."
+
+// HotspotQuery describes a hotspot report over commits with loaded files
+type HotspotQuery struct {
+	// Include keeps only matching paths; empty keeps every path
+	Include glob.Set
+	Exclude glob.Set
+	// ReworkWindow is how soon after its previous change a file's changed
+	// lines count as rework
+	ReworkWindow time.Duration
+	// Limit caps the ranked files and directories
+	Limit int
+	// Depth caps the directory tree below the root
+	Depth int
+}
+
+// PathStats measures the changes to a file or to everything below a directory
+type PathStats struct {
+	Path      string
+	Additions int
+	Deletions int
+	// Changes counts the commits touching the path
+	Changes int
+	// Authors counts the distinct authors of those commits
+	Authors int
+	// ReworkLines are the lines changed within the rework window of the
+	// file's previous change
+	ReworkLines int
+	// Score ranks hotspots: changes weighted by the logarithm of churn
+	Score float64
+}
+
+// Churn is the number of lines added and deleted
+func (s PathStats) Churn() int {
+	return s.Additions + s.Deletions
+}
+
+// ReworkRate is the share of churn that was rework
+func (s PathStats) ReworkRate() float64 {
+	return ratio(s.ReworkLines, s.Churn())
+}
+
+// FileHotspot is a file's share of a hotspot report
+type FileHotspot struct {
+	PathStats
+	// Removed is set when the file's last change in the window deleted it
+	Removed bool
+}
+
+// DirectoryNode is a directory with the rollup of every file below it
+type DirectoryNode struct {
+	PathStats
+	// Files counts the changed files below the directory
+	Files int
+	// Children are ordered by score; directories below the depth limit are left out
+	Children []*DirectoryNode
+}
+
+// HotspotReport ranks the files and directories changed most often and most heavily
+type HotspotReport struct {
+	// Commits counts the commits that touched an included path
+	Commits int
+	// Files and Directories are ranked by score and capped at the query limit
+	Files       []FileHotspot
+	Directories []PathStats
+	// ChangedFiles counts every included file, ranked or not
+	ChangedFiles int
+	Tree         *DirectoryNode
+}
+
+// pathAccumulator collects the changes of one file or directory
+type pathAccumulator struct {
+	stats   PathStats
+	commits map[string]bool
+	authors map[string]bool
+	// files holds the files below a directory; renamed files count once
+	files map[*pathAccumulator]bool
+	// lastChange is when the file last changed; unused for directories
+	lastChange time.Time
+	removed    bool
+}
+
+func newPathAccumulator(p string) *pathAccumulator {
+	return &pathAccumulator{
+		stats:   PathStats{Path: p},
+		commits: make(map[string]bool),
+		authors: make(map[string]bool),
+		files:   make(map[*pathAccumulator]bool),
+	}
+}
+
+func (a *pathAccumulator) add(commit vcs.Commit, change vcs.FileChange, file *pathAccumulator, rework int) {
+	a.stats.Additions += change.Additions
+	a.stats.Deletions += change.Deletions
+	a.stats.ReworkLines += rework
+	a.commits[commit.SHA] = true
+	a.authors[authorKey(commit)] = true
+	a.files[file] = true
+}
+
+func (a *pathAccumulator) result() PathStats {
+	stats := a.stats
+	stats.Changes = len(a.commits)
+	stats.Authors = len(a.authors)
+	stats.Score = float64(stats.Changes) * math.Log1p(float64(stats.Churn()))
+	return stats
+}
+
+// Hotspots computes churn, change frequency, authorship and rework of the
+// files changed by commits and rolls them up into directories. Rework is
+// approximated per file: every line changed within the rework window of
+// the file's previous change counts. Renamed files keep their history.
+func Hotspots(commits []vcs.Commit, query HotspotQuery) *HotspotReport {
+	// Oldest first, so that rework and renames follow the history
+	ordered := append([]vcs.Commit(nil), commits...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CommittedAt.Before(ordered[j].CommittedAt)
+	})
+
+	files := make(map[string]*pathAccumulator)
+	dirs := make(map[string]*pathAccumulator)
+	touched := make(map[string]bool)
+	for _, commit := range ordered {
+		for _, change := range commit.Files {
+			if !query.includes(change.Path) {
+				continue
+			}
+			touched[commit.SHA] = true
+
+			file := files[change.Path]
+			if change.PreviousPath != "" && file == nil {
+				if previous, ok := files[change.PreviousPath]; ok {
+					delete(files, change.PreviousPath)
+					previous.stats.Path = change.Path
+					file = previous
+				}
+			}
+			if file == nil {
+				file = newPathAccumulator(change.Path)
+			}
+			files[change.Path] = file
+
+			rework := 0
+			if !file.lastChange.IsZero() && commit.CommittedAt.Sub(file.lastChange) <= query.ReworkWindow {
+				rework = change.Additions + change.Deletions
+			}
+			file.add(commit, change, file, rework)
+			file.lastChange = commit.CommittedAt
+			file.removed = change.Status == vcs.FileRemoved
+
+			for _, dir := range directories(change.Path) {
+				if dirs[dir] == nil {
+					dirs[dir] = newPathAccumulator(dir)
+				}
+				dirs[dir].add(commit, change, file, rework)
+			}
+		}
+	}
+
+	report := &HotspotReport{Commits: len(touched), ChangedFiles: len(files)}
+	for _, file := range files {
+		report.Files = append(report.Files, FileHotspot{PathStats: file.result(), Removed: file.removed})
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return ranksBefore(report.Files[i].PathStats, report.Files[j].PathStats)
+	})
+	report.Files = capped(report.Files, query.Limit)
+
+	for p, dir := range dirs {
+		if p != rootPath {
+			report.Directories = append(report.Directories, dir.result())
+		}
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		return ranksBefore(report.Directories[i], report.Directories[j])
+	})
+	report.Directories = capped(report.Directories, query.Limit)
+
+	if root, ok := dirs[rootPath]; ok {
+		children := make(map[string][]string)
+		for p := range dirs {
+			if p != rootPath {
+				parent := parentDirectory(p)
+				children[parent] = append(children[parent], p)
+			}
+		}
+		report.Tree = directoryTree(root, dirs, children, query.Depth)
+	}
+	return report
+}
+
+func (q HotspotQuery) includes(p string) bool {
+	if len(q.Include) > 0 && !q.Include.Match(p) {
+		return false
+	}
+	return !q.Exclude.Match(p)
+}
+
+// directories returns the root and every directory containing the file
+func directories(file string) []string {
+	dirs := []string{rootPath}
+	for dir := path.Dir(file); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// directoryTree builds the node of a directory and its subdirectories down to depth levels
+func directoryTree(acc *pathAccumulator, dirs map[string]*pathAccumulator, children map[string][]string, depth int) *DirectoryNode {
+	node := &DirectoryNode{PathStats: acc.result(), Files: len(acc.files)}
+	if depth == 0 {
+		return node
+	}
+	for _, child := range children[acc.stats.Path] {
+		node.Children = append(node.Children, directoryTree(dirs[child], dirs, children, depth-1))
+	}
+	sort.Slice(node.Children, func(i, j int) bool {
+		return ranksBefore(node.Children[i].PathStats, node.Children[j].PathStats)
+	})
+	return node
+}
+
+func parentDirectory(dir string) string {
+	if !strings.Contains(dir, "/") {
+		return rootPath
+	}
+	return path.Dir(dir)
+}
+
+// ranksBefore orders by score, then churn, then path
+func ranksBefore(a, b PathStats) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Churn() != b.Churn() {
+		return a.Churn() > b.Churn()
+	}
+	return a.Path < b.Path
+}
+
+func capped[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+// authorKey identifies a commit author by their resolved identity, falling
+// back to the e-mail and then the name
+func authorKey(commit vcs.Commit) string {
+	if commit.Contributor != nil {
+		return commit.Contributor.ID
+	}
+	if commit.AuthorEmail != "" {
+		return strings.ToLower(commit.AuthorEmail)
+	}
+	return commit.AuthorName
+}
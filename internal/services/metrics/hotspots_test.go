@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/glob"
+)
+
+func compileSet(t *testing.T, patterns ...string) glob.Set {
+	t.Helper()
+	set, err := glob.CompileSet(patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func hotspotCommits() []vcs.Commit {
+	return []vcs.Commit{
+		// Newest first, as listings return them
+		{SHA: "4", AuthorEmail: "bob@example.com", CommittedAt: date(6, 0), Files: []vcs.FileChange{
+			{Path: "README.md", Status: vcs.FileRemoved, Deletions: 5},
+		}},
+		{SHA: "3", AuthorEmail: "alice@example.com", CommittedAt: date(5, 0), Files: []vcs.FileChange{
+			{Path: "api/new.go", PreviousPath: "api/old.go", Status: vcs.FileRenamed, Additions: 1, Deletions: 1},
+			{Path: "vendor/lib.go", Status: vcs.FileModified, Additions: 100},
+		}},
+		{SHA: "2", AuthorEmail: "Bob@example.com", CommittedAt: date(2, 0), Files: []vcs.FileChange{
+			{Path: "api/handler.go", Status: vcs.FileModified, Additions: 4, Deletions: 2},
+			{Path: "api/old.go", Status: vcs.FileAdded, Additions: 3},
+		}},
+		{SHA: "1", AuthorEmail: "alice@example.com", CommittedAt: date(1, 0), Files: []vcs.FileChange{
+			{Path: "api/handler.go", Status: vcs.FileAdded, Additions: 10},
+			{Path: "README.md", Status: vcs.FileAdded, Additions: 5},
+		}},
+	}
+}
+
+func TestHotspots(t *testing.T) {
+	report := Hotspots(hotspotCommits(), HotspotQuery{
+		Exclude:      compileSet(t, "vendor/"),
+		ReworkWindow: 48 * time.Hour,
+		Depth:        1,
+	})
+	if report.Commits != 4 || report.ChangedFiles != 3 {
+		t.Errorf("report = %d commits, %d files, want 4, 3", report.Commits, report.ChangedFiles)
+	}
+
+	tests := []struct {
+		path                 string
+		additions, deletions int
+		changes, authors     int
+		rework               int
+		removed              bool
+	}{
+		// The second change to handler.go came within the rework window
+		{"api/handler.go", 14, 2, 2, 2, 6, false},
+		{"README.md", 5, 5, 2, 2, 0, true},
+		// Renamed files keep the history of their previous path
+		{"api/new.go", 4, 1, 2, 2, 0, false},
+	}
+	if len(report.Files) != len(tests) {
+		t.Fatalf("files = %+v, want %d", report.Files, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			f := report.Files[i]
+			if f.Path != tt.path {
+				t.Fatalf("file %d = %s, want %s", i, f.Path, tt.path)
+			}
+			if f.Additions != tt.additions || f.Deletions != tt.deletions || f.Changes != tt.changes || f.Authors != tt.authors {
+				t.Errorf("stats = +%d -%d in %d changes by %d authors, want +%d -%d in %d by %d",
+					f.Additions, f.Deletions, f.Changes, f.Authors, tt.additions, tt.deletions, tt.changes, tt.authors)
+			}
+			if f.ReworkLines != tt.rework || f.Removed != tt.removed {
+				t.Errorf("rework = %d, removed = %v, want %d, %v", f.ReworkLines, f.Removed, tt.rework, tt.removed)
+			}
+		})
+	}
+
+	if len(report.Directories) != 1 || report.Directories[0].Path != "api" || report.Directories[0].Changes != 3 {
+		t.Errorf("directories = %+v, want api changed by 3 commits", report.Directories)
+	}
+	root := report.Tree
+	if root == nil || root.Path != rootPath || root.Files != 3 || root.Changes != 4 {
+		t.Fatalf("tree = %+v, want the root with 3 files in 4 commits", root)
+	}
+	if len(root.Children) != 1 || root.Children[0].Path != "api" || root.Children[0].Files != 2 {
+		t.Errorf("root children = %+v, want api with 2 files", root.Children)
+	}
+}
+
+func TestHotspotsIncludeAndLimit(t *testing.T) {
+	report := Hotspots(hotspotCommits(), HotspotQuery{Include: compileSet(t, "api/**"), Limit: 1})
+
+	var paths []string
+	for _, f := range report.Files {
+		paths = append(paths, f.Path)
+	}
+	if want := []string{"api/handler.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("files = %v, want %v", paths, want)
+	}
+	if report.Commits != 3 || report.ChangedFiles != 2 {
+		t.Errorf("report = %d commits, %d files, want 3, 2", report.Commits, report.ChangedFiles)
+	}
+	if report.Tree.Children != nil {
+		t.Errorf("tree children = %+v, want none at depth 0", report.Tree.Children)
+	}
+}
+
+func TestDirectories(t *testing.T) {
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"main.go", []string{rootPath}},
+		{"a/b/c.go", []string{rootPath, "a/b", "a"}},
+	}
+	for _, tt := range tests {
+		if got := directories(tt.file); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("directories(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package metrics
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/codeowners"
+)
+
+// OwnerReviewStatus tells whether the code owners of a pull request's files reviewed it
+type OwnerReviewStatus string
+
+const (
+	// OwnerReviewed pull requests had an owner's review for every owned file
+	OwnerReviewed OwnerReviewStatus = "reviewed"
+	// OwnerReviewPartial pull requests had an owner's review for some owned files
+	OwnerReviewPartial OwnerReviewStatus = "partial"
+	OwnerNotReviewed   OwnerReviewStatus = "not_reviewed"
+	// OwnerReviewUnowned pull requests changed no file that requires an owner's review
+	OwnerReviewUnowned OwnerReviewStatus = "unowned"
+)
+
+// OwnershipSource is what an ownership report reads of one repository
+type OwnershipSource struct {
+	// CodeOwners is nil when the repository has no CODEOWNERS file
+	CodeOwners *codeowners.File
+	// Paths are the files of the default branch
+	Paths []string
+	// PullRequests need their files and reviews loaded
+	PullRequests []vcs.PullRequest
+	// IsOwner reports whether a reviewer is one of an owner's accounts or team members
+	IsOwner func(owner, reviewerLogin string) bool
+	// Limit caps the listed unowned paths and pull requests
+	Limit int
+}
+
+// PathCount counts the files below a path
+type PathCount struct {
+	Path  string
+	Files int
+}
+
+// OwnerCount counts the files of a code owner
+type OwnerCount struct {
+	Owner string
+	Files int
+}
+
+// PullRequestOwnership is a pull request with the review it got from code owners
+type PullRequestOwnership struct {
+	vcs.PullRequest
+	Status OwnerReviewStatus
+	// Owners are the owners of the changed files in sections that require a review
+	Owners []string
+	// Reviewers are the reviewers who count as one of those owners
+	Reviewers []string
+	// UnownedFiles counts the changed files without owners
+	UnownedFiles int
+}
+
+// OwnershipReport describes how CODEOWNERS covers a repository and whether
+// code owners reviewed its pull requests
+type OwnershipReport struct {
+	HasCodeOwners bool
+	// Files counts the files of the default branch; OwnedFiles those with an owner
+	Files      int
+	OwnedFiles int
+	// UnownedPaths collapses unowned files into the highest directories
+	// holding no owned file, largest first and capped at the limit
+	UnownedPaths []PathCount
+	// Owners counts the files of every owner, most first
+	Owners []OwnerCount
+	// ChangedFiles counts the distinct files the pull requests changed
+	ChangedFiles      int
+	OwnedChangedFiles int
+	// Statuses counts the pull requests by owner review status
+	Statuses map[OwnerReviewStatus]int
+	// PullRequests are ordered as given and capped at the limit
+	PullRequests []PullRequestOwnership
+}
+
+// Coverage is the share of files with an owner
+func (r *OwnershipReport) Coverage() float64 {
+	return ratio(r.OwnedFiles, r.Files)
+}
+
+// ChangedCoverage is the share of changed files with an owner
+func (r *OwnershipReport) ChangedCoverage() float64 {
+	return ratio(r.OwnedChangedFiles, r.ChangedFiles)
+}
+
+// Ownership maps every file of a repository and every file changed by its
+// pull requests to their code owners. Reviews by the pull request's author
+// and drafts don't count; optional GitLab sections require no review.
+func Ownership(source OwnershipSource) *OwnershipReport {
+	report := &OwnershipReport{
+		HasCodeOwners: source.CodeOwners != nil,
+		Files:         len(source.Paths),
+		Statuses:      make(map[OwnerReviewStatus]int),
+	}
+
+	owners := func(p string) []string {
+		if source.CodeOwners == nil {
+			return nil
+		}
+		return source.CodeOwners.Owners(p)
+	}
+
+	ownerFiles := make(map[string]int)
+	var owned, unowned []string
+	for _, p := range source.Paths {
+		fileOwners := owners(p)
+		if len(fileOwners) == 0 {
+			unowned = append(unowned, p)
+			continue
+		}
+		owned = append(owned, p)
+		for _, owner := range fileOwners {
+			ownerFiles[owner]++
+		}
+	}
+	report.OwnedFiles = len(owned)
+	report.UnownedPaths = capped(collapseUnowned(owned, unowned), source.Limit)
+	for owner, files := range ownerFiles {
+		report.Owners = append(report.Owners, OwnerCount{Owner: owner, Files: files})
+	}
+	sort.Slice(report.Owners, func(i, j int) bool {
+		if report.Owners[i].Files != report.Owners[j].Files {
+			return report.Owners[i].Files > report.Owners[j].Files
+		}
+		return report.Owners[i].Owner < report.Owners[j].Owner
+	})
+
+	changed := make(map[string]bool)
+	for _, pr := range source.PullRequests {
+		ownership := pullRequestOwnership(pr, source)
+		report.Statuses[ownership.Status]++
+		report.PullRequests = append(report.PullRequests, ownership)
+
+		for _, file := range pr.Files {
+			if !changed[file.Path] {
+				changed[file.Path] = true
+				if len(owners(file.Path)) > 0 {
+					report.OwnedChangedFiles++
+				}
+			}
+		}
+	}
+	report.ChangedFiles = len(changed)
+	report.PullRequests = capped(report.PullRequests, source.Limit)
+	return report
+}
+
+// pullRequestOwnership checks every owned file of a pull request, in every
+// section that owns it, for a review by one of its owners
+func pullRequestOwnership(pr vcs.PullRequest, source OwnershipSource) PullRequestOwnership {
+	ownership := PullRequestOwnership{PullRequest: pr}
+
+	var reviewers []string
+	for _, review := range pr.Reviews {
+		if review.Submitted() && !strings.EqualFold(review.Login, pr.AuthorLogin) {
+			reviewers = append(reviewers, review.Login)
+		}
+	}
+
+	owners := make(map[string]bool)
+	ownerReviewers := make(map[string]bool)
+	required, satisfied := 0, 0
+	for _, file := range pr.Files {
+		var matches []codeowners.Match
+		if source.CodeOwners != nil {
+			matches = source.CodeOwners.Match(file.Path)
+		}
+
+		hasOwner := false
+		for _, match := range matches {
+			if len(match.Owners) == 0 {
+				continue
+			}
+			hasOwner = true
+			if match.Optional {
+				continue
+			}
+
+			required++
+			reviewed := false
+			for _, owner := range match.Owners {
+				if !owners[owner] {
+					owners[owner] = true
+					ownership.Owners = append(ownership.Owners, owner)
+				}
+				for _, reviewer := range reviewers {
+					if source.IsOwner(owner, reviewer) {
+						reviewed = true
+						if !ownerReviewers[reviewer] {
+							ownerReviewers[reviewer] = true
+							ownership.Reviewers = append(ownership.Reviewers, reviewer)
+						}
+					}
+				}
+			}
+			if reviewed {
+				satisfied++
+			}
+		}
+		if !hasOwner {
+			ownership.UnownedFiles++
+		}
+	}
+
+	switch {
+	case required == 0:
+		ownership.Status = OwnerReviewUnowned
+	case satisfied == required:
+		ownership.Status = OwnerReviewed
+	case satisfied > 0:
+		ownership.Status = OwnerReviewPartial
+	default:
+		ownership.Status = OwnerNotReviewed
+	}
+	return ownership
+}
+
+// collapseUnowned reports every unowned file under the highest directory
+// that holds no owned file, or on its own when its directory holds one
+func collapseUnowned(owned, unowned []string) []PathCount {
+	ownedDirs := make(map[string]bool)
+	for _, p := range owned {
+		for _, dir := range directories(p) {
+			ownedDirs[dir] = true
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, p := range unowned {
+		collapsed := p
+		for dir := path.Dir(p); dir != "." && dir != "/" && !ownedDirs[dir]; dir = path.Dir(dir) {
+			collapsed = dir + "/"
+		}
+		// Nothing is owned: the whole repository is one unowned path
+		if len(owned) == 0 {
+			collapsed = rootPath
+		}
+		counts[collapsed]++
+	}
+
+	collapsed := make([]PathCount, 0, len(counts))
+	for p, files := range counts {
+		collapsed = append(collapsed, PathCount{Path: p, Files: files})
+	}
+	sortPathCounts(collapsed)
+	return collapsed
+}
+
+// sortPathCounts orders by files, most first, then by path
+func sortPathCounts(counts []PathCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Files != counts[j].Files {
+			return counts[i].Files > counts[j].Files
+		}
+		return counts[i].Path < counts[j].Path
+	})
+}
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// GroupKey names a group pull requests are broken down by
+type GroupKey struct {
+	Key  string
+	Name string
+}
+
+// PullRequestQuery describes a pull request report
+type PullRequestQuery struct {
+	Since    time.Time
+	Until    time.Time
+	Interval Interval
+	// GroupBy assigns each pull request to zero or more groups; nil computes no groups
+	GroupBy func(vcs.PullRequest) []GroupKey
+}
+
+// PullRequestStats measures the pull requests of a period or group. Opened
+// counts pull requests by creation, Merged and Closed by when they finished.
+type PullRequestStats struct {
+	Opened int
+	// Merged is the throughput: pull requests merged in the period
+	Merged int
+	// Closed counts pull requests closed without being merged
+	Closed int
+	// OpenCloseRatio is opened per finished pull request; 0 when none finished
+	OpenCloseRatio float64
+	// AbandonedRate is the share of finished pull requests closed without merging
+	AbandonedRate float64
+	// TimeToMerge spans creation to merge of the pull requests merged in the period
+	TimeToMerge DurationStats
+}
+
+// PullRequestBucket is one interval of a time series
+type PullRequestBucket struct {
+	Start time.Time
+	PullRequestStats
+}
+
+// PullRequestSeries is a summary with its time series and time-to-merge distribution
+type PullRequestSeries struct {
+	Summary    PullRequestStats
+	Histogram  []HistogramBin
+	TimeSeries []PullRequestBucket
+}
+
+// PullRequestGroup is the share of a report belonging to one group
+type PullRequestGroup struct {
+	GroupKey
+	PullRequestSeries
+}
+
+// PullRequestReport holds cycle time and throughput figures
+type PullRequestReport struct {
+	Since    time.Time
+	Until    time.Time
+	Interval Interval
+	PullRequestSeries
+	// Groups are ordered by merged pull requests, then by key
+	Groups []PullRequestGroup
+}
+
+// PullRequests computes a report over pull requests created in the time
+// range. Pull requests created earlier but merged or closed in the range
+// are not part of the input and therefore not counted.
+func PullRequests(prs []vcs.PullRequest, query PullRequestQuery) (*PullRequestReport, error) {
+	starts, err := bucketStarts(query.Interval, query.Since, query.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PullRequestReport{
+		Since:             query.Since,
+		Until:             query.Until,
+		Interval:          query.Interval,
+		PullRequestSeries: newSeries(prs, query, starts),
+	}
+	if query.GroupBy == nil {
+		return report, nil
+	}
+
+	keys := make(map[string]GroupKey)
+	members := make(map[string][]vcs.PullRequest)
+	for _, pr := range prs {
+		for _, key := range query.GroupBy(pr) {
+			keys[key.Key] = key
+			members[key.Key] = append(members[key.Key], pr)
+		}
+	}
+	for key, groupPRs := range members {
+		report.Groups = append(report.Groups, PullRequestGroup{
+			GroupKey:          keys[key],
+			PullRequestSeries: newSeries(groupPRs, query, starts),
+		})
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Summary.Merged != b.Summary.Merged {
+			return a.Summary.Merged > b.Summary.Merged
+		}
+		return a.Key < b.Key
+	})
+	return report, nil
+}
+
+func newSeries(prs []vcs.PullRequest, query PullRequestQuery, starts []time.Time) PullRequestSeries {
+	series := PullRequestSeries{
+		Summary:    newStats(prs, query.Since, query.Until),
+		TimeSeries: make([]PullRequestBucket, 0, len(starts)),
+	}
+	series.Histogram = newHistogram(timesToMerge(prs, query.Since, query.Until))
+
+	for _, start := range starts {
+		// The first and last buckets are clipped to the requested range
+		since, until := start, query.Interval.next(start).Add(-time.Nanosecond)
+		if since.Before(query.Since) {
+			since = query.Since
+		}
+		if until.After(query.Until) {
+			until = query.Until
+		}
+		series.TimeSeries = append(series.TimeSeries, PullRequestBucket{
+			Start:            start,
+			PullRequestStats: newStats(prs, since, until),
+		})
+	}
+	return series
+}
+
+func newStats(prs []vcs.PullRequest, since, until time.Time) PullRequestStats {
+	var stats PullRequestStats
+	for _, pr := range prs {
+		created := pr.CreatedAt
+		if inRange(&created, since, until) {
+			stats.Opened++
+		}
+		switch {
+		case inRange(pr.MergedAt, since, until):
+			stats.Merged++
+		case pr.MergedAt == nil && inRange(pr.ClosedAt, since, until):
+			stats.Closed++
+		}
+	}
+
+	finished := stats.Merged + stats.Closed
+	stats.OpenCloseRatio = ratio(stats.Opened, finished)
+	stats.AbandonedRate = ratio(stats.Closed, finished)
+	stats.TimeToMerge = newDurationStats(timesToMerge(prs, since, until))
+	return stats
+}
+
+// timesToMerge returns the creation-to-merge time of pull requests merged in the range
+func timesToMerge(prs []vcs.PullRequest, since, until time.Time) []time.Duration {
+	var durations []time.Duration
+	for _, pr := range prs {
+		if inRange(pr.MergedAt, since, until) {
+			durations = append(durations, pr.MergedAt.Sub(pr.CreatedAt))
+		}
+	}
+	return durations
+}
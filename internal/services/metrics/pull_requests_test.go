@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+func date(day, hour int) time.Time {
+	return time.Date(2024, time.January, day, hour, 0, 0, 0, time.UTC)
+}
+
+func at(day, hour int) *time.Time {
+	t := date(day, hour)
+	return &t
+}
+
+func TestBucketStarts(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval Interval
+		since    time.Time
+		until    time.Time
+		want     []time.Time
+	}{
+		{"days", IntervalDay, date(1, 12), date(3, 1), []time.Time{date(1, 0), date(2, 0), date(3, 0)}},
+		{"weeks start on Monday", IntervalWeek, date(3, 0), date(10, 0), []time.Time{date(1, 0), date(8, 0)}},
+		{"months", IntervalMonth, date(15, 0), time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC), []time.Time{date(1, 0), time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)}},
+		{"buckets align in UTC", IntervalDay, time.Date(2024, time.January, 1, 0, 30, 0, 0, time.FixedZone("CET", 3600)), date(1, 0), []time.Time{date(0, 0), date(1, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bucketStarts(tt.interval, tt.since, tt.until)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bucketStarts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketStartsTooMany(t *testing.T) {
+	_, err := bucketStarts(IntervalDay, date(1, 0), date(1, 0).AddDate(2, 0, 0))
+	if !errors.Is(err, vcs.ErrInvalidInput) {
+		t.Errorf("error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{10, 20, 30, 40, 50}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 10},
+		{50, 30},
+		{75, 40},
+		{90, 46},
+		{100, 50},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Errorf("percentile of nothing = %v, want 0", got)
+	}
+}
+
+func TestNewHistogram(t *testing.T) {
+	bins := newHistogram([]time.Duration{
+		30 * time.Minute,
+		time.Hour,
+		24 * time.Hour,
+		60 * 24 * time.Hour,
+	})
+	want := map[string]int{"<1h": 1, "1h-4h": 1, "1d-3d": 1, ">30d": 1}
+	for _, bin := range bins {
+		if bin.Count != want[bin.Label] {
+			t.Errorf("bin %s = %d, want %d", bin.Label, bin.Count, want[bin.Label])
+		}
+	}
+	if last := bins[len(bins)-1]; last.Min != 30*24*time.Hour || last.Max != 0 {
+		t.Errorf("last bin = [%v, %v), want [720h, unbounded)", last.Min, last.Max)
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	prs := []vcs.PullRequest{
+		{Number: 1, AuthorLogin: "alice", CreatedAt: date(1, 10), MergedAt: at(2, 10), ClosedAt: at(2, 10)},
+		{Number: 2, AuthorLogin: "alice", CreatedAt: date(3, 10), MergedAt: at(10, 10), ClosedAt: at(10, 10)},
+		{Number: 3, AuthorLogin: "bob", CreatedAt: date(8, 10), ClosedAt: at(9, 10)},
+		{Number: 4, AuthorLogin: "bob", CreatedAt: date(9, 10)},
+		// Merged after the range: opened, but not part of the throughput
+		{Number: 5, AuthorLogin: "carol", CreatedAt: date(14, 10), MergedAt: at(20, 10)},
+	}
+	report, err := PullRequests(prs, PullRequestQuery{
+		Since:    date(1, 0),
+		Until:    date(15, 0).Add(-time.Nanosecond),
+		Interval: IntervalWeek,
+		GroupBy: func(pr vcs.PullRequest) []GroupKey {
+			return []GroupKey{{Key: pr.AuthorLogin, Name: pr.AuthorLogin}}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := report.Summary
+	if summary.Opened != 5 || summary.Merged != 2 || summary.Closed != 1 {
+		t.Errorf("summary = %d opened, %d merged, %d closed, want 5, 2, 1", summary.Opened, summary.Merged, summary.Closed)
+	}
+	if summary.OpenCloseRatio != 5.0/3 || summary.AbandonedRate != 1.0/3 {
+		t.Errorf("ratios = %v, %v, want 5/3, 1/3", summary.OpenCloseRatio, summary.AbandonedRate)
+	}
+	if ttm := summary.TimeToMerge; ttm.Count != 2 || ttm.Median != 96*time.Hour {
+		t.Errorf("time to merge = %d with median %v, want 2 with median 96h", ttm.Count, ttm.Median)
+	}
+
+	tests := []struct {
+		name                          string
+		stats                         PullRequestStats
+		opened, merged, closed        int
+		openCloseRatio, abandonedRate float64
+	}{
+		{"first week", report.TimeSeries[0].PullRequestStats, 2, 1, 0, 2, 0},
+		{"second week", report.TimeSeries[1].PullRequestStats, 3, 1, 1, 1.5, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.stats
+			if s.Opened != tt.opened || s.Merged != tt.merged || s.Closed != tt.closed {
+				t.Errorf("stats = %d opened, %d merged, %d closed, want %d, %d, %d", s.Opened, s.Merged, s.Closed, tt.opened, tt.merged, tt.closed)
+			}
+			if s.OpenCloseRatio != tt.openCloseRatio || s.AbandonedRate != tt.abandonedRate {
+				t.Errorf("ratios = %v, %v, want %v, %v", s.OpenCloseRatio, s.AbandonedRate, tt.openCloseRatio, tt.abandonedRate)
+			}
+		})
+	}
+
+	var groups []string
+	for _, group := range report.Groups {
+		groups = append(groups, group.Key)
+	}
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v ordered by merged", groups, want)
+	}
+}
@@ -0,0 +1,273 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// ReviewQuery describes a reviewer workload report over pull requests with loaded reviews
+type ReviewQuery struct {
+	// IncludeBots counts reviews and requests of automation accounts
+	IncludeBots bool
+	// Teams are the teams whose review concentration is measured
+	Teams []TeamMembers
+	// Limit caps the reviewer-author pairs
+	Limit int
+}
+
+// TeamMembers names a team and the person IDs of its members
+type TeamMembers struct {
+	ID      string
+	Name    string
+	Members []string
+}
+
+// Person identifies a reviewer or author by their resolved identity, else by login
+type Person struct {
+	Key  string
+	Name string
+}
+
+// ReviewerStats describes the reviews one person gave
+type ReviewerStats struct {
+	Person
+	// Reviews counts submitted reviews; a pull request may get several
+	Reviews          int
+	Approvals        int
+	ChangesRequested int
+	Comments         int
+	// PullRequests counts the distinct pull requests reviewed
+	PullRequests int
+	// Requested counts the pull requests the person was asked to review or
+	// reviewed; reviews nobody asked for count as requested
+	Requested int
+	// TimeToFirstReview spans the creation of a pull request to the person's
+	// first review of it, where the provider reports review times
+	TimeToFirstReview DurationStats
+}
+
+// CompletionRate is the share of requested pull requests the person reviewed
+func (s ReviewerStats) CompletionRate() float64 {
+	return ratio(s.PullRequests, s.Requested)
+}
+
+// TeamConcentration measures how evenly a team's members share its reviews
+type TeamConcentration struct {
+	ID      string
+	Name    string
+	Members int
+	// Reviewers counts the members who reviewed at least once
+	Reviewers int
+	Reviews   int
+	// Gini is 0 when every member reviewed equally often; nil without reviews
+	Gini *float64
+	// TopShare is the share of the team's reviews given by its busiest member
+	TopShare float64
+}
+
+// ReviewPair counts the pull requests of an author that a reviewer reviewed
+type ReviewPair struct {
+	Reviewer     Person
+	Author       Person
+	PullRequests int
+}
+
+// ReviewReport describes who carries the review load
+type ReviewReport struct {
+	PullRequests int
+	// Reviewed counts the pull requests someone other than the author reviewed
+	Reviewed int
+	Reviews  int
+	// TimeToFirstReview spans creation to the first review by anyone but the author
+	TimeToFirstReview DurationStats
+	// Reviewers are ordered by reviews, most first
+	Reviewers []ReviewerStats
+	// Gini measures the concentration of reviews across all reviewers
+	Gini  *float64
+	Teams []TeamConcentration
+	// Pairs are ordered by pull requests, most first, and capped at the limit
+	Pairs []ReviewPair
+}
+
+// reviewerAccumulator collects the reviews of one person
+type reviewerAccumulator struct {
+	stats ReviewerStats
+	waits []time.Duration
+}
+
+// Reviews computes reviewer workload from pull requests with loaded reviews.
+// Self-reviews and drafts don't count.
+func Reviews(prs []vcs.PullRequest, query ReviewQuery) *ReviewReport {
+	report := &ReviewReport{PullRequests: len(prs)}
+	reviewers := make(map[string]*reviewerAccumulator)
+	reviewer := func(person Person) *reviewerAccumulator {
+		if reviewers[person.Key] == nil {
+			reviewers[person.Key] = &reviewerAccumulator{stats: ReviewerStats{Person: person}}
+		}
+		return reviewers[person.Key]
+	}
+	pairs := make(map[[2]string]*ReviewPair)
+
+	var firstReviews []time.Duration
+	for _, pr := range prs {
+		author := pullRequestAuthor(pr)
+		counts := func(r vcs.Reviewer) bool {
+			return (query.IncludeBots || !r.Bot) && reviewerPerson(r).Key != author.Key
+		}
+
+		// seen holds the people counted for this pull request
+		seen := make(map[string]bool)
+		var first *time.Time
+		reviewedBy := 0
+		for _, review := range pr.Reviews {
+			if !review.Submitted() || !counts(review.Reviewer) {
+				continue
+			}
+			person := reviewerPerson(review.Reviewer)
+			acc := reviewer(person)
+			acc.stats.Reviews++
+			report.Reviews++
+			switch review.State {
+			case vcs.ReviewApproved:
+				acc.stats.Approvals++
+			case vcs.ReviewChangesRequested:
+				acc.stats.ChangesRequested++
+			default:
+				acc.stats.Comments++
+			}
+
+			if !seen[person.Key] {
+				seen[person.Key] = true
+				reviewedBy++
+				acc.stats.PullRequests++
+				acc.stats.Requested++
+				if wait, ok := firstReviewWait(pr, person.Key); ok {
+					acc.waits = append(acc.waits, wait)
+				}
+
+				pairKey := [2]string{person.Key, author.Key}
+				if pairs[pairKey] == nil {
+					pairs[pairKey] = &ReviewPair{Reviewer: person, Author: author}
+				}
+				pairs[pairKey].PullRequests++
+			}
+			if review.SubmittedAt != nil && (first == nil || review.SubmittedAt.Before(*first)) {
+				first = review.SubmittedAt
+			}
+		}
+
+		// Requests still open; a requested reviewer who reviewed was counted above
+		for _, requested := range pr.RequestedReviewers {
+			person := reviewerPerson(requested)
+			if counts(requested) && !seen[person.Key] {
+				// Listed once even when requested twice
+				seen[person.Key] = true
+				reviewer(person).stats.Requested++
+			}
+		}
+
+		if reviewedBy > 0 {
+			report.Reviewed++
+		}
+		if first != nil {
+			firstReviews = append(firstReviews, first.Sub(pr.CreatedAt))
+		}
+	}
+	report.TimeToFirstReview = newDurationStats(firstReviews)
+
+	counts := make(map[string]float64, len(reviewers))
+	var all []float64
+	for key, acc := range reviewers {
+		acc.stats.TimeToFirstReview = newDurationStats(acc.waits)
+		report.Reviewers = append(report.Reviewers, acc.stats)
+		counts[key] = float64(acc.stats.Reviews)
+		if acc.stats.Reviews > 0 {
+			all = append(all, float64(acc.stats.Reviews))
+		}
+	}
+	sort.Slice(report.Reviewers, func(i, j int) bool {
+		a, b := report.Reviewers[i], report.Reviewers[j]
+		if a.Reviews != b.Reviews {
+			return a.Reviews > b.Reviews
+		}
+		return a.Key < b.Key
+	})
+	report.Gini = gini(all)
+
+	for _, t := range query.Teams {
+		report.Teams = append(report.Teams, teamConcentration(t, counts))
+	}
+
+	for _, pair := range pairs {
+		report.Pairs = append(report.Pairs, *pair)
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		a, b := report.Pairs[i], report.Pairs[j]
+		if a.PullRequests != b.PullRequests {
+			return a.PullRequests > b.PullRequests
+		}
+		if a.Reviewer.Key != b.Reviewer.Key {
+			return a.Reviewer.Key < b.Reviewer.Key
+		}
+		return a.Author.Key < b.Author.Key
+	})
+	report.Pairs = capped(report.Pairs, query.Limit)
+	return report
+}
+
+// teamConcentration measures the spread of reviews across every member of
+// a team, counting members who gave none
+func teamConcentration(t TeamMembers, reviews map[string]float64) TeamConcentration {
+	concentration := TeamConcentration{ID: t.ID, Name: t.Name, Members: len(t.Members)}
+	values := make([]float64, 0, len(t.Members))
+	var top float64
+	for _, member := range t.Members {
+		count := reviews[member]
+		values = append(values, count)
+		concentration.Reviews += int(count)
+		if count > 0 {
+			concentration.Reviewers++
+		}
+		if count > top {
+			top = count
+		}
+	}
+	concentration.Gini = gini(values)
+	if concentration.Reviews > 0 {
+		concentration.TopShare = top / float64(concentration.Reviews)
+	}
+	return concentration
+}
+
+// firstReviewWait returns how long after its creation a pull request got
+// its first timed review from a person
+func firstReviewWait(pr vcs.PullRequest, key string) (time.Duration, bool) {
+	var first *time.Time
+	for _, review := range pr.Reviews {
+		if review.Submitted() && review.SubmittedAt != nil && reviewerPerson(review.Reviewer).Key == key {
+			if first == nil || review.SubmittedAt.Before(*first) {
+				first = review.SubmittedAt
+			}
+		}
+	}
+	if first == nil {
+		return 0, false
+	}
+	return first.Sub(pr.CreatedAt), true
+}
+
+func reviewerPerson(r vcs.Reviewer) Person {
+	if r.Contributor != nil {
+		return Person{Key: r.Contributor.ID, Name: r.Contributor.Name}
+	}
+	return Person{Key: r.Login, Name: r.Login}
+}
+
+func pullRequestAuthor(pr vcs.PullRequest) Person {
+	if pr.Contributor != nil {
+		return Person{Key: pr.Contributor.ID, Name: pr.Contributor.Name}
+	}
+	return Person{Key: pr.AuthorLogin, Name: pr.AuthorName}
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+func approxEqual(got *float64, want float64) bool {
+	return got != nil && math.Abs(*got-want) < 1e-9
+}
+
+func TestGini(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"equal", []float64{2, 2, 2}, 0},
+		{"one carries everything", []float64{0, 0, 3}, 2.0 / 3},
+		{"uneven", []float64{3, 2}, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gini(tt.values); !approxEqual(got, tt.want) {
+				t.Errorf("gini(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+	if got := gini([]float64{0, 0}); got != nil {
+		t.Errorf("gini without reviews = %v, want nil", *got)
+	}
+}
+
+func reviewPullRequests() []vcs.PullRequest {
+	reviewer := func(login string) vcs.Reviewer { return vcs.Reviewer{Login: login} }
+	return []vcs.PullRequest{
+		{Number: 1, AuthorLogin: "alice", CreatedAt: date(1, 0), Reviews: []vcs.Review{
+			review("alice", vcs.ReviewCommented, at(1, 1)),
+			{Reviewer: vcs.Reviewer{Login: "lint-bot", Bot: true}, State: vcs.ReviewCommented, SubmittedAt: at(1, 1)},
+			review("bob", vcs.ReviewApproved, at(1, 2)),
+			review("bob", vcs.ReviewCommented, at(1, 3)),
+			review("bob", vcs.ReviewPending, nil),
+			review("carol", vcs.ReviewChangesRequested, at(1, 5)),
+		}, RequestedReviewers: []vcs.Reviewer{reviewer("dave"), reviewer("carol")}},
+		// GitLab approvals carry no time
+		{Number: 2, AuthorLogin: "alice", CreatedAt: date(1, 0), Reviews: []vcs.Review{
+			review("bob", vcs.ReviewApproved, nil),
+		}},
+		{Number: 3, AuthorLogin: "bob", CreatedAt: date(1, 0), Reviews: []vcs.Review{
+			review("carol", vcs.ReviewApproved, at(1, 4)),
+		}},
+		{Number: 4, AuthorLogin: "alice", CreatedAt: date(1, 0), RequestedReviewers: []vcs.Reviewer{reviewer("bob")}},
+	}
+}
+
+func TestReviews(t *testing.T) {
+	report := Reviews(reviewPullRequests(), ReviewQuery{
+		Teams: []TeamMembers{{ID: "platform", Name: "Platform", Members: []string{"bob", "carol", "erin"}}},
+		Limit: 2,
+	})
+
+	if report.PullRequests != 4 || report.Reviewed != 3 || report.Reviews != 5 {
+		t.Errorf("report = %d pull requests, %d reviewed, %d reviews, want 4, 3, 5", report.PullRequests, report.Reviewed, report.Reviews)
+	}
+	if ttfr := report.TimeToFirstReview; ttfr.Count != 2 || ttfr.Median != 3*time.Hour {
+		t.Errorf("time to first review = %d with median %v, want 2 with median 3h", ttfr.Count, ttfr.Median)
+	}
+
+	tests := []struct {
+		key                                     string
+		reviews, approvals, changes, comments   int
+		pullRequests, requested, timedFirstWait int
+		medianWait                              time.Duration
+	}{
+		{"bob", 3, 2, 0, 1, 2, 3, 1, 2 * time.Hour},
+		{"carol", 2, 1, 1, 0, 2, 2, 2, 4*time.Hour + 30*time.Minute},
+		// Requested but never reviewed
+		{"dave", 0, 0, 0, 0, 0, 1, 0, 0},
+	}
+	if len(report.Reviewers) != len(tests) {
+		t.Fatalf("reviewers = %+v, want %d", report.Reviewers, len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			r := report.Reviewers[i]
+			if r.Key != tt.key {
+				t.Fatalf("reviewer %d = %s, want %s", i, r.Key, tt.key)
+			}
+			if r.Reviews != tt.reviews || r.Approvals != tt.approvals || r.ChangesRequested != tt.changes || r.Comments != tt.comments {
+				t.Errorf("reviews = %d (%d approvals, %d changes requested, %d comments), want %d (%d, %d, %d)",
+					r.Reviews, r.Approvals, r.ChangesRequested, r.Comments, tt.reviews, tt.approvals, tt.changes, tt.comments)
+			}
+			if r.PullRequests != tt.pullRequests || r.Requested != tt.requested {
+				t.Errorf("reviewed %d of %d requested, want %d of %d", r.PullRequests, r.Requested, tt.pullRequests, tt.requested)
+			}
+			if r.TimeToFirstReview.Count != tt.timedFirstWait || r.TimeToFirstReview.Median != tt.medianWait {
+				t.Errorf("time to first review = %d with median %v, want %d with median %v",
+					r.TimeToFirstReview.Count, r.TimeToFirstReview.Median, tt.timedFirstWait, tt.medianWait)
+			}
+		})
+	}
+
+	if !approxEqual(report.Gini, 0.1) {
+		t.Errorf("gini = %v, want 0.1", report.Gini)
+	}
+	team := report.Teams[0]
+	if team.Members != 3 || team.Reviewers != 2 || team.Reviews != 5 || team.TopShare != 0.6 {
+		t.Errorf("team = %+v, want 2 of 3 members reviewing 5 times, top share 0.6", team)
+	}
+
+	var pairs [][2]string
+	for _, pair := range report.Pairs {
+		pairs = append(pairs, [2]string{pair.Reviewer.Key, pair.Author.Key})
+	}
+	if want := [][2]string{{"bob", "alice"}, {"carol", "alice"}}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("pairs = %v, want %v", pairs, want)
+	}
+}
+
+func TestReviewsIncludeBots(t *testing.T) {
+	report := Reviews(reviewPullRequests(), ReviewQuery{IncludeBots: true})
+	if report.Reviews != 6 || len(report.Reviewers) != 4 {
+		t.Errorf("report = %d reviews by %d reviewers, want 6 by 4 with the bot", report.Reviews, len(report.Reviewers))
+	}
+	if ttfr := report.TimeToFirstReview; ttfr.Median != 2*time.Hour+30*time.Minute {
+		t.Errorf("time to first review median = %v, want 2h30m counting the bot", ttfr.Median)
+	}
+}
@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/glob"
+)
+
+// Size classifies a pull request by its changed lines
+type Size string
+
+const (
+	SizeXS  Size = "XS"
+	SizeS   Size = "S"
+	SizeM   Size = "M"
+	SizeL   Size = "L"
+	SizeXL  Size = "XL"
+	SizeXXL Size = "XXL"
+)
+
+// sizes lists the size classes from smallest to largest
+var sizes = []Size{SizeXS, SizeS, SizeM, SizeL, SizeXL, SizeXXL}
+
+// SizeThreshold is the largest changed line count of a size class; XXL has none
+type SizeThreshold struct {
+	Size     Size
+	MaxLines int
+}
+
+// SizeClassifier assigns pull requests to size classes
+type SizeClassifier struct {
+	// thresholds holds the classes up to XL in ascending order
+	thresholds []SizeThreshold
+	// excluded matches the files whose lines don't count
+	excluded glob.Set
+}
+
+func NewSizeClassifier(cfg *config.Config) (*SizeClassifier, error) {
+	bounds := cfg.Metrics.PullRequestSizes
+	excluded, err := glob.CompileSet(bounds.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pull request size exclusion: %w", err)
+	}
+	return &SizeClassifier{
+		thresholds: []SizeThreshold{
+			{SizeXS, bounds.XS},
+			{SizeS, bounds.S},
+			{SizeM, bounds.M},
+			{SizeL, bounds.L},
+			{SizeXL, bounds.XL},
+		},
+		excluded: excluded,
+	}, nil
+}
+
+// Thresholds returns the configured classes up to XL
+func (c *SizeClassifier) Thresholds() []SizeThreshold {
+	return append([]SizeThreshold(nil), c.thresholds...)
+}
+
+// Excluded returns the patterns of files whose lines don't count
+func (c *SizeClassifier) Excluded() []string {
+	patterns := make([]string, 0, len(c.excluded))
+	for _, pattern := range c.excluded {
+		patterns = append(patterns, pattern.String())
+	}
+	return patterns
+}
+
+// Classify returns the size class of a pull request
+func (c *SizeClassifier) Classify(pr vcs.PullRequest) Size {
+	return c.classify(c.changedLines(pr))
+}
+
+func (c *SizeClassifier) classify(lines int) Size {
+	for _, threshold := range c.thresholds {
+		if lines <= threshold.MaxLines {
+			return threshold.Size
+		}
+	}
+	return SizeXXL
+}
+
+// changedLines counts additions and deletions, leaving out excluded files
+// when the pull request's files were loaded
+func (c *SizeClassifier) changedLines(pr vcs.PullRequest) int {
+	if pr.Files == nil {
+		return pr.Additions + pr.Deletions
+	}
+	lines := 0
+	for _, file := range pr.Files {
+		if !c.excluded.Match(file.Path) && !(file.PreviousPath != "" && c.excluded.Match(file.PreviousPath)) {
+			lines += file.Additions + file.Deletions
+		}
+	}
+	return lines
+}
+
+// SizeStats describes the pull requests of one size class
+type SizeStats struct {
+	Size   Size
+	Count  int
+	Merged int
+	// TimeToMerge spans creation to merge of the merged pull requests
+	TimeToMerge DurationStats
+	// TimeToFirstReview spans creation to the first review by anyone but the
+	// author; empty unless reviews were loaded
+	TimeToFirstReview DurationStats
+	// MedianReviews is the median review count, as reported by the provider
+	MedianReviews float64
+}
+
+// SizeCorrelation holds Spearman rank correlations of changed lines; nil
+// when fewer than three pull requests could be compared
+type SizeCorrelation struct {
+	// TimeToMerge is measured over merged pull requests
+	TimeToMerge *float64
+	// TimeToFirstReview is measured over reviewed pull requests; nil unless
+	// reviews were loaded
+	TimeToFirstReview *float64
+	// Untimed counts the reviewed pull requests left out of TimeToFirstReview
+	// because their reviews have no time, as GitLab approvals don't
+	Untimed int
+}
+
+// OversizedPullRequest is an open pull request with its size class
+type OversizedPullRequest struct {
+	vcs.PullRequest
+	Size Size
+	// Lines are the changed lines that count towards the size
+	Lines int
+}
+
+// RepositoryOversized lists the largest open pull requests of a repository
+type RepositoryOversized struct {
+	RepositoryID string
+	PullRequests []OversizedPullRequest
+}
+
+// SizeReport classifies pull requests by size and relates size to how
+// they are reviewed and merged
+type SizeReport struct {
+	Thresholds  []SizeThreshold
+	Sizes       []SizeStats
+	Correlation SizeCorrelation
+	// LargestOpen holds the largest open pull requests of every repository,
+	// ordered by repository
+	LargestOpen []RepositoryOversized
+}
+
+// PullRequestSizes computes a size report. LargestOpen keeps up to largest
+// pull requests per repository.
+func (c *SizeClassifier) PullRequestSizes(prs []vcs.PullRequest, largest int) *SizeReport {
+	report := &SizeReport{Thresholds: c.Thresholds()}
+
+	classes := make(map[Size][]vcs.PullRequest, len(sizes))
+	open := make(map[string][]OversizedPullRequest)
+	var mergeHours, mergedLines, reviewHours, reviewedLines []float64
+	for _, pr := range prs {
+		prLines := c.changedLines(pr)
+		size := c.classify(prLines)
+		classes[size] = append(classes[size], pr)
+
+		if wait, ok := firstReview(pr); ok {
+			reviewedLines = append(reviewedLines, float64(prLines))
+			reviewHours = append(reviewHours, wait.Hours())
+		} else if untimedReview(pr) {
+			report.Correlation.Untimed++
+		}
+		if pr.MergedAt != nil {
+			mergedLines = append(mergedLines, float64(prLines))
+			mergeHours = append(mergeHours, pr.MergedAt.Sub(pr.CreatedAt).Hours())
+		}
+
+		if isOpen(pr) {
+			open[pr.RepositoryID] = append(open[pr.RepositoryID], OversizedPullRequest{PullRequest: pr, Size: size, Lines: prLines})
+		}
+	}
+
+	for _, size := range sizes {
+		report.Sizes = append(report.Sizes, newSizeStats(size, classes[size]))
+	}
+	report.Correlation.TimeToMerge = spearman(mergedLines, mergeHours)
+	report.Correlation.TimeToFirstReview = spearman(reviewedLines, reviewHours)
+
+	for repo, oversized := range open {
+		sort.Slice(oversized, func(i, j int) bool {
+			if oversized[i].Lines != oversized[j].Lines {
+				return oversized[i].Lines > oversized[j].Lines
+			}
+			return oversized[i].Number < oversized[j].Number
+		})
+		if len(oversized) > largest {
+			oversized = oversized[:largest]
+		}
+		report.LargestOpen = append(report.LargestOpen, RepositoryOversized{RepositoryID: repo, PullRequests: oversized})
+	}
+	sort.Slice(report.LargestOpen, func(i, j int) bool {
+		return report.LargestOpen[i].RepositoryID < report.LargestOpen[j].RepositoryID
+	})
+	return report
+}
+
+func newSizeStats(size Size, prs []vcs.PullRequest) SizeStats {
+	stats := SizeStats{Size: size, Count: len(prs)}
+	var durations, waits []time.Duration
+	reviews := make([]float64, 0, len(prs))
+	for _, pr := range prs {
+		if pr.MergedAt != nil {
+			stats.Merged++
+			durations = append(durations, pr.MergedAt.Sub(pr.CreatedAt))
+		}
+		if wait, ok := firstReview(pr); ok {
+			waits = append(waits, wait)
+		}
+		reviews = append(reviews, float64(pr.ReviewCount))
+	}
+	stats.TimeToMerge = newDurationStats(durations)
+	stats.TimeToFirstReview = newDurationStats(waits)
+	stats.MedianReviews = median(reviews)
+	return stats
+}
+
+// firstReview returns how long after its creation a pull request got its
+// first timed review from anyone but its author
+func firstReview(pr vcs.PullRequest) (time.Duration, bool) {
+	var first *time.Time
+	for _, review := range pr.Reviews {
+		if !peerReview(pr, review) || review.SubmittedAt == nil {
+			continue
+		}
+		if first == nil || review.SubmittedAt.Before(*first) {
+			first = review.SubmittedAt
+		}
+	}
+	if first == nil {
+		return 0, false
+	}
+	return first.Sub(pr.CreatedAt), true
+}
+
+// untimedReview reports whether a pull request was reviewed by anyone but
+// its author without the provider saying when
+func untimedReview(pr vcs.PullRequest) bool {
+	reviewed := false
+	for _, review := range pr.Reviews {
+		if !peerReview(pr, review) {
+			continue
+		}
+		if review.SubmittedAt != nil {
+			return false
+		}
+		reviewed = true
+	}
+	return reviewed
+}
+
+// peerReview reports whether a review was handed in by a person other than
+// the author of the pull request; bots don't count
+func peerReview(pr vcs.PullRequest, review vcs.Review) bool {
+	return review.Submitted() && !review.Reviewer.Bot && reviewerPerson(review.Reviewer).Key != pullRequestAuthor(pr).Key
+}
+
+// isOpen reports whether a pull request is neither merged nor closed.
+// GitLab reports open merge requests as "opened".
+func isOpen(pr vcs.PullRequest) bool {
+	state := strings.ToLower(pr.State)
+	return pr.MergedAt == nil && pr.ClosedAt == nil && (state == "open" || state == "opened")
+}
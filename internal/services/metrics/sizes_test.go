@@ -0,0 +1,179 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+)
+
+func newTestClassifier(t *testing.T, exclude ...string) *SizeClassifier {
+	t.Helper()
+	classifier, err := NewSizeClassifier(&config.Config{Metrics: config.MetricsConfig{
+		PullRequestSizes: config.PullRequestSizesConfig{XS: 10, S: 50, M: 250, L: 500, XL: 1000, Exclude: exclude},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return classifier
+}
+
+func TestClassify(t *testing.T) {
+	classifier := newTestClassifier(t)
+	tests := []struct {
+		additions, deletions int
+		want                 Size
+	}{
+		{0, 0, SizeXS},
+		{6, 4, SizeXS},
+		{6, 5, SizeS},
+		{250, 0, SizeM},
+		{400, 100, SizeL},
+		{1000, 0, SizeXL},
+		{1000, 1, SizeXXL},
+	}
+	for _, tt := range tests {
+		pr := vcs.PullRequest{Additions: tt.additions, Deletions: tt.deletions}
+		if got := classifier.Classify(pr); got != tt.want {
+			t.Errorf("Classify(+%d -%d) = %s, want %s", tt.additions, tt.deletions, got, tt.want)
+		}
+	}
+}
+
+func review(login string, state vcs.ReviewState, submittedAt *time.Time) vcs.Review {
+	return vcs.Review{Reviewer: vcs.Reviewer{Login: login}, State: state, SubmittedAt: submittedAt}
+}
+
+func TestFirstReview(t *testing.T) {
+	alice := &vcs.Contributor{ID: "c-alice"}
+	tests := []struct {
+		name    string
+		reviews []vcs.Review
+		want    time.Duration
+		ok      bool
+		untimed bool
+	}{
+		{"no reviews", nil, 0, false, false},
+		{"earliest review", []vcs.Review{
+			review("carol", vcs.ReviewApproved, at(1, 4)),
+			review("bob", vcs.ReviewCommented, at(1, 2)),
+		}, 2 * time.Hour, true, false},
+		{"author, bots and drafts don't count", []vcs.Review{
+			{Reviewer: vcs.Reviewer{Login: "alice-work", Contributor: alice}, State: vcs.ReviewCommented, SubmittedAt: at(1, 1)},
+			{Reviewer: vcs.Reviewer{Login: "review-bot", Bot: true}, State: vcs.ReviewCommented, SubmittedAt: at(1, 1)},
+			review("bob", vcs.ReviewPending, nil),
+			review("carol", vcs.ReviewApproved, at(1, 3)),
+		}, 3 * time.Hour, true, false},
+		{"untimed approval", []vcs.Review{review("bob", vcs.ReviewApproved, nil)}, 0, false, true},
+		{"timed review beside an untimed one", []vcs.Review{
+			review("bob", vcs.ReviewApproved, nil),
+			review("carol", vcs.ReviewApproved, at(1, 5)),
+		}, 5 * time.Hour, true, false},
+		{"only the author reviewed", []vcs.Review{
+			{Reviewer: vcs.Reviewer{Login: "alice", Contributor: alice}, State: vcs.ReviewApproved},
+		}, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := vcs.PullRequest{AuthorLogin: "alice", Contributor: alice, CreatedAt: date(1, 0), Reviews: tt.reviews}
+			got, ok := firstReview(pr)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("firstReview = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+			if untimed := untimedReview(pr); untimed != tt.untimed {
+				t.Errorf("untimedReview = %v, want %v", untimed, tt.untimed)
+			}
+		})
+	}
+}
+
+func TestPullRequestSizes(t *testing.T) {
+	pr := func(number, lines int, repo, state string, mergedAt *time.Time, reviews ...vcs.Review) vcs.PullRequest {
+		return vcs.PullRequest{
+			Number:       number,
+			State:        state,
+			AuthorLogin:  "alice",
+			CreatedAt:    date(1, 0),
+			MergedAt:     mergedAt,
+			ClosedAt:     mergedAt,
+			Additions:    lines,
+			RepositoryID: repo,
+			Reviews:      reviews,
+		}
+	}
+	prs := []vcs.PullRequest{
+		pr(1, 5, "acme/api", "merged", at(1, 2), review("bob", vcs.ReviewApproved, at(1, 1))),
+		pr(2, 40, "acme/api", "merged", at(2, 0), review("carol", vcs.ReviewApproved, at(1, 4))),
+		pr(3, 300, "acme/api", "merged", at(3, 0), review("carol", vcs.ReviewApproved, at(1, 10))),
+		pr(4, 2000, "acme/api", "open", nil, review("bob", vcs.ReviewApproved, nil)),
+		pr(5, 600, "acme/web", "opened", nil),
+		pr(6, 100, "acme/api", "open", nil),
+	}
+	report := newTestClassifier(t).PullRequestSizes(prs, 1)
+
+	for _, size := range report.Sizes {
+		if size.Count != 1 {
+			t.Errorf("%s count = %d, want 1", size.Size, size.Count)
+		}
+	}
+	if s := report.Sizes[1]; s.Merged != 1 || s.TimeToMerge.Median != 24*time.Hour || s.TimeToFirstReview.Median != 4*time.Hour {
+		t.Errorf("S = %d merged in %v, first review after %v, want 1 in 24h, 4h", s.Merged, s.TimeToMerge.Median, s.TimeToFirstReview.Median)
+	}
+
+	correlation := report.Correlation
+	if correlation.TimeToMerge == nil || *correlation.TimeToMerge != 1 {
+		t.Errorf("time to merge correlation = %v, want 1", correlation.TimeToMerge)
+	}
+	if correlation.TimeToFirstReview == nil || *correlation.TimeToFirstReview != 1 {
+		t.Errorf("time to first review correlation = %v, want 1", correlation.TimeToFirstReview)
+	}
+	if correlation.Untimed != 1 {
+		t.Errorf("untimed = %d, want 1", correlation.Untimed)
+	}
+
+	tests := []struct {
+		repo   string
+		number int
+	}{
+		{"acme/api", 4},
+		{"acme/web", 5},
+	}
+	if len(report.LargestOpen) != len(tests) {
+		t.Fatalf("largest open = %+v, want %d repositories", report.LargestOpen, len(tests))
+	}
+	for i, tt := range tests {
+		got := report.LargestOpen[i]
+		if got.RepositoryID != tt.repo || len(got.PullRequests) != 1 || got.PullRequests[0].Number != tt.number {
+			t.Errorf("largest open %d = %+v, want %s #%d", i, got, tt.repo, tt.number)
+		}
+	}
+}
+
+func TestChangedLinesExcluded(t *testing.T) {
+	classifier := newTestClassifier(t, "package-lock.json", "*.pb.go")
+	tests := []struct {
+		name  string
+		files []vcs.FileChange
+		want  int
+	}{
+		{"files not loaded", nil, 30},
+		{"excluded files", []vcs.FileChange{
+			{Path: "api/handler.go", Additions: 5, Deletions: 1},
+			{Path: "web/package-lock.json", Additions: 900},
+			{Path: "api/v1/api.pb.go", Additions: 400, Deletions: 20},
+		}, 6},
+		{"renamed from an excluded path", []vcs.FileChange{
+			{Path: "api/api.go", PreviousPath: "api/api.pb.go", Status: vcs.FileRenamed, Additions: 7},
+		}, 0},
+		{"no files changed", []vcs.FileChange{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := vcs.PullRequest{Additions: 20, Deletions: 10, Files: tt.files}
+			if got := classifier.changedLines(pr); got != tt.want {
+				t.Errorf("changedLines = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
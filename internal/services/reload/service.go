@@ -0,0 +1,257 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/internal/secrets"
+	"devmetrics/internal/services/tenant"
+	vcsservice "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// ProviderFactory builds the providers of a single tenant
+type ProviderFactory interface {
+	CreateProviders(tenantID string, cfg config.VCSConfig) (map[vcs.ProviderType]vcs.Provider, error)
+}
+
+// Trigger names what started a reload
+type Trigger string
+
+const (
+	TriggerSignal Trigger = "signal"
+	TriggerWatch  Trigger = "file_change"
+	TriggerAPI    Trigger = "api"
+)
+
+// Status describes the outcome of the most recent reloads
+type Status struct {
+	ConfigFile string
+	// Generation counts successful reloads since startup
+	Generation  int
+	LastAttempt time.Time
+	LastSuccess time.Time
+	LastTrigger Trigger
+	// LastError is empty when the last attempt succeeded
+	LastError string
+	// Rebuilt lists the tenants whose providers were recreated by the last successful reload
+	Rebuilt []string
+	// RestartRequired lists changed settings that only take effect after a restart
+	RestartRequired []string
+}
+
+// Service reloads the configuration file and swaps the rebuilt tenant
+// providers into the VCS service. A failed reload leaves everything as it was.
+type Service struct {
+	path     string
+	factory  ProviderFactory
+	vcs      *vcsservice.Service
+	tenants  *tenant.Service
+	resolver *secrets.Resolver
+	logger   logger.Logger
+
+	mu      sync.Mutex
+	current *config.Config
+	status  Status
+}
+
+func NewService(
+	path string,
+	cfg *config.Config,
+	factory ProviderFactory,
+	vcs *vcsservice.Service,
+	tenants *tenant.Service,
+	resolver *secrets.Resolver,
+	log logger.Logger,
+) *Service {
+	return &Service{
+		path:     path,
+		factory:  factory,
+		vcs:      vcs,
+		tenants:  tenants,
+		resolver: resolver,
+		logger:   log.With(logger.String("component", "config_reload")),
+		current:  cfg,
+		status:   Status{ConfigFile: path},
+	}
+}
+
+// Status returns the outcome of the most recent reloads
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// Reload loads the configuration again and applies it
+func (s *Service) Reload(ctx context.Context, trigger Trigger) (Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.LastAttempt = time.Now()
+	s.status.LastTrigger = trigger
+	log := logger.FromContext(ctx, s.logger).With(logger.String("trigger", string(trigger)))
+
+	if err := s.apply(); err != nil {
+		s.status.LastError = err.Error()
+		log.Error("Configuration reload failed, keeping the previous configuration", logger.Error(err))
+		return s.status, err
+	}
+
+	s.status.Generation++
+	s.status.LastSuccess = s.status.LastAttempt
+	s.status.LastError = ""
+	log.Info("Configuration reloaded",
+		logger.Int("generation", s.status.Generation),
+		logger.Any("rebuilt_tenants", s.status.Rebuilt),
+	)
+	if len(s.status.Rebuilt) > 0 {
+		verifyCtx, cancel := context.WithTimeout(ctx, time.Duration(s.current.Startup.VerifyTimeout)*time.Second)
+		s.vcs.VerifyProviders(verifyCtx)
+		cancel()
+	}
+	if len(s.status.RestartRequired) > 0 {
+		log.Warn("Changed settings only take effect after a restart", logger.Any("settings", s.status.RestartRequired))
+	}
+	return s.status, nil
+}
+
+func (s *Service) apply() error {
+	cfg, err := config.Load(s.path)
+	if err != nil {
+		return err
+	}
+	for _, warning := range cfg.Warnings {
+		s.logger.Warn("Configuration: " + warning)
+	}
+
+	// Re-read secret references so rotated credentials are picked up even
+	// when the configuration itself did not change
+	s.resolver.InvalidateAll()
+
+	previous := make(map[string]config.TenantConfig, len(s.current.Tenants))
+	for _, t := range s.current.Tenants {
+		previous[t.ID] = t
+	}
+	running := s.vcs.Providers()
+
+	providers := make(vcsservice.Providers, len(cfg.Tenants))
+	var rebuilt []string
+	for _, t := range cfg.Tenants {
+		old, ok := previous[t.ID]
+		if existing, built := running[t.ID]; ok && built && reflect.DeepEqual(old.VCS, t.VCS) {
+			providers[t.ID] = existing
+			continue
+		}
+
+		created, err := s.factory.CreateProviders(t.ID, t.VCS)
+		if err != nil {
+			return fmt.Errorf("tenant %q: %w", t.ID, err)
+		}
+		providers[t.ID] = created
+		rebuilt = append(rebuilt, t.ID)
+	}
+	sort.Strings(rebuilt)
+
+	s.vcs.SetProviders(providers)
+	s.tenants.Reload(cfg.Tenants)
+
+	s.status.Rebuilt = rebuilt
+	s.status.RestartRequired = restartRequired(s.current, cfg)
+	s.current = cfg
+	return nil
+}
+
+// restartRequired lists the top-level sections that changed but are only
+// read at startup
+func restartRequired(old, updated *config.Config) []string {
+	var changed []string
+	sections := map[string][2]interface{}{
+		"environment": {old.Environment, updated.Environment},
+		"server":      {old.Server, updated.Server},
+		"logger":      {old.Logger, updated.Logger},
+		"auth":        {old.Auth, updated.Auth},
+		"secrets":     {old.Secrets, updated.Secrets},
+		"reload":      {old.Reload, updated.Reload},
+	}
+	for name, values := range sections {
+		if !reflect.DeepEqual(values[0], values[1]) {
+			changed = append(changed, name)
+		}
+	}
+
+	for i := range updated.Tenants {
+		if i < len(old.Tenants) && updated.Tenants[i].ID == old.Tenants[i].ID &&
+			!reflect.DeepEqual(updated.Tenants[i].APIKeys, old.Tenants[i].APIKeys) {
+			changed = append(changed, fmt.Sprintf("tenants.%s.api_keys", updated.Tenants[i].ID))
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// Run reloads on SIGHUP and, when a config file is used, whenever it or the
+// tenants file changes. It returns when ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	s.mu.Lock()
+	interval := s.current.Reload.WatchInterval
+	s.mu.Unlock()
+
+	var tick <-chan time.Time
+	if interval > 0 && s.path != "" {
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	watched := s.fingerprint()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			s.Reload(ctx, TriggerSignal)
+			watched = s.fingerprint()
+		case <-tick:
+			if current := s.fingerprint(); current != watched {
+				watched = current
+				s.Reload(ctx, TriggerWatch)
+			}
+		}
+	}
+}
+
+// fingerprint summarises the modification state of the watched files
+func (s *Service) fingerprint() string {
+	s.mu.Lock()
+	files := []string{s.path, s.current.TenantsFile}
+	s.mu.Unlock()
+
+	var fp string
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			fp += file + ":missing;"
+			continue
+		}
+		fp += fmt.Sprintf("%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return fp
+}
@@ -0,0 +1,141 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/internal/secrets"
+	"devmetrics/internal/services/tenant"
+	vcsservice "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// fakeFactory builds empty provider sets and fails for the tenants in fail
+type fakeFactory struct {
+	fail  map[string]bool
+	built []string
+}
+
+func (f *fakeFactory) CreateProviders(tenantID string, _ config.VCSConfig) (map[vcs.ProviderType]vcs.Provider, error) {
+	if f.fail[tenantID] {
+		return nil, errors.New("invalid credentials")
+	}
+	f.built = append(f.built, tenantID)
+	return map[vcs.ProviderType]vcs.Provider{}, nil
+}
+
+// newTestService starts from the configuration in an empty file and
+// returns the path to write the next configuration to
+func newTestService(t *testing.T, factory ProviderFactory) (*Service, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "environment: test\n")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	providers := vcsservice.Providers{config.DefaultTenantID: {}}
+	service := NewService(path, cfg, factory,
+		vcsservice.NewService(providers, nil, nil, logger.NewNop()),
+		tenant.NewService(cfg),
+		secrets.NewResolver(0),
+		logger.NewNop(),
+	)
+	return service, path
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const acmeConfig = `
+environment: test
+tenants:
+  - id: acme
+    vcs:
+      gitlab:
+        enabled: true
+        token: glpat-acme
+`
+
+func TestReload(t *testing.T) {
+	factory := &fakeFactory{}
+	service, path := newTestService(t, factory)
+	writeConfig(t, path, acmeConfig)
+
+	status, err := service.Reload(context.Background(), TriggerAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Generation != 1 || status.LastError != "" {
+		t.Errorf("Status = generation %d, error %q, want 1 and none", status.Generation, status.LastError)
+	}
+	// The default tenant's settings didn't change, so its providers are kept
+	if want := []string{"acme"}; !reflect.DeepEqual(status.Rebuilt, want) || !reflect.DeepEqual(factory.built, want) {
+		t.Errorf("Rebuilt = %v, built %v, want %v", status.Rebuilt, factory.built, want)
+	}
+	if _, ok := service.vcs.Providers()["acme"]; !ok {
+		t.Error("the providers of the new tenant are not serving")
+	}
+	if !service.tenants.Exists("acme") {
+		t.Error("the new tenant is unknown")
+	}
+}
+
+func TestReloadFailureKeepsState(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"provider creation fails", acmeConfig},
+		{"invalid configuration", "server:\n  port: not-a-port\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, path := newTestService(t, &fakeFactory{fail: map[string]bool{"acme": true}})
+			providers := service.vcs.Providers()
+			tenants := service.tenants.List(context.Background())
+			writeConfig(t, path, tt.content)
+
+			status, err := service.Reload(context.Background(), TriggerAPI)
+			if err == nil {
+				t.Fatal("Reload succeeded, want an error")
+			}
+			if status.Generation != 0 || status.LastError == "" {
+				t.Errorf("Status = generation %d, error %q, want 0 and the failure", status.Generation, status.LastError)
+			}
+			if got := service.vcs.Providers(); !reflect.DeepEqual(got, providers) {
+				t.Errorf("Providers = %v, want %v unchanged", got, providers)
+			}
+			if got := service.tenants.List(context.Background()); !reflect.DeepEqual(got, tenants) {
+				t.Errorf("tenants = %v, want %v unchanged", got, tenants)
+			}
+			if service.tenants.Exists("acme") {
+				t.Error("the tenant of the failed reload was added")
+			}
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	old := &config.Config{Tenants: []config.TenantConfig{{ID: "default"}, {ID: "acme"}}}
+	updated := &config.Config{
+		Server:  config.ServerConfig{Port: "9090"},
+		Auth:    config.AuthConfig{Enabled: true},
+		Tenants: []config.TenantConfig{{ID: "default"}, {ID: "acme", APIKeys: []config.APIKeyConfig{{Name: "ci"}}}},
+	}
+	want := []string{"auth", "server", "tenants.acme.api_keys"}
+	if got := restartRequired(old, updated); !reflect.DeepEqual(got, want) {
+		t.Errorf("restartRequired = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,249 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"devmetrics/internal/domain/identity"
+	"devmetrics/internal/domain/team"
+	tenantdomain "devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+	identityservice "devmetrics/internal/services/identity"
+	"devmetrics/internal/services/tenant"
+	vcsservice "devmetrics/internal/services/vcs"
+	"devmetrics/pkg/logger"
+)
+
+var ErrInvalidTeam = errors.New("invalid team")
+
+// Service manages each tenant's teams. Teams declared in the configuration
+// are read-only; those created through the API are kept in the store.
+type Service struct {
+	store      team.Store
+	tenants    *tenant.Service
+	vcs        *vcsservice.Service
+	identities *identityservice.Service
+	logger     logger.Logger
+}
+
+func NewService(
+	store team.Store,
+	tenants *tenant.Service,
+	vcs *vcsservice.Service,
+	identities *identityservice.Service,
+	log logger.Logger,
+) *Service {
+	return &Service{
+		store:      store,
+		tenants:    tenants,
+		vcs:        vcs,
+		identities: identities,
+		logger:     log.With(logger.String("component", "teams")),
+	}
+}
+
+// List returns the tenant's configured and API-managed teams ordered by ID
+func (s *Service) List(ctx context.Context) ([]team.Team, error) {
+	teams, err := s.configured(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.store.List(ctx, tenantdomain.IDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	teams = append(teams, stored...)
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].ID < teams[j].ID
+	})
+	return teams, nil
+}
+
+// Get returns a team by ID
+func (s *Service) Get(ctx context.Context, id string) (*team.Team, error) {
+	configured, err := s.configured(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range configured {
+		if configured[i].ID == id {
+			return &configured[i], nil
+		}
+	}
+	return s.store.Get(ctx, tenantdomain.IDFromContext(ctx), id)
+}
+
+// configured returns the teams declared in the tenant's configuration. Their
+// ID is the configured name.
+func (s *Service) configured(ctx context.Context) ([]team.Team, error) {
+	t, err := s.tenants.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]team.Team, 0, len(t.Teams))
+	for _, configured := range t.Teams {
+		teams = append(teams, team.Team{
+			ID:           configured.Name,
+			Name:         configured.Name,
+			Members:      configured.Members,
+			Repositories: configured.Repositories,
+			Source:       team.SourceConfig,
+		})
+	}
+	return teams, nil
+}
+
+// Put creates or replaces a team. The members of a synced team are managed
+// by syncing: those in the request are ignored and the last synced ones kept.
+func (s *Service) Put(ctx context.Context, t team.Team) (team.Team, error) {
+	if err := validate(&t); err != nil {
+		return team.Team{}, err
+	}
+
+	existing, err := s.Get(ctx, t.ID)
+	switch {
+	case errors.Is(err, team.ErrNotFound):
+	case err != nil:
+		return team.Team{}, err
+	case existing.Source == team.SourceConfig:
+		return team.Team{}, team.ErrReadOnly
+	case t.Sync != nil && existing.Sync != nil && *t.Sync == *existing.Sync:
+		t.Members, t.SyncedAt = existing.Members, existing.SyncedAt
+	}
+	if t.Sync != nil && t.SyncedAt == nil {
+		t.Members = []string{}
+	}
+
+	t.Source = team.SourceAPI
+	t.UpdatedAt = time.Now()
+	if err := s.store.Put(ctx, tenantdomain.IDFromContext(ctx), t); err != nil {
+		return team.Team{}, err
+	}
+	return t, nil
+}
+
+func validate(t *team.Team) error {
+	t.ID = strings.TrimSpace(t.ID)
+	if t.ID == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidTeam)
+	}
+	if t.Name = strings.TrimSpace(t.Name); t.Name == "" {
+		t.Name = t.ID
+	}
+
+	members := make([]string, 0, len(t.Members))
+	for _, member := range t.Members {
+		if member = strings.TrimSpace(member); member != "" {
+			members = append(members, member)
+		}
+	}
+	t.Members = members
+
+	for _, repo := range t.Repositories {
+		if repo.Provider != vcs.ProviderGitHub && repo.Provider != vcs.ProviderGitLab && repo.Provider != vcs.ProviderBitbucket {
+			return fmt.Errorf("%w: unsupported repository provider %q", ErrInvalidTeam, repo.Provider)
+		}
+		if repo.Name == "" {
+			return fmt.Errorf("%w: repository names are required", ErrInvalidTeam)
+		}
+	}
+
+	if t.Sync != nil {
+		if t.Sync.Provider != vcs.ProviderGitHub && t.Sync.Provider != vcs.ProviderGitLab {
+			return fmt.Errorf("%w: teams can only be synced from github or gitlab", ErrInvalidTeam)
+		}
+		if t.Sync.Group = strings.TrimSpace(t.Sync.Group); t.Sync.Group == "" {
+			return fmt.Errorf("%w: sync group is required", ErrInvalidTeam)
+		}
+	}
+	return nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if err := s.checkWritable(ctx, id); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, tenantdomain.IDFromContext(ctx), id)
+}
+
+// Sync replaces a team's members with the accounts of its provider team or group
+func (s *Service) Sync(ctx context.Context, id string) (*team.Team, error) {
+	if err := s.checkWritable(ctx, id); err != nil {
+		return nil, err
+	}
+	t, err := s.store.Get(ctx, tenantdomain.IDFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Sync == nil {
+		return nil, fmt.Errorf("%w: team %q has no sync source", ErrInvalidTeam, id)
+	}
+
+	users, err := s.vcs.GroupMembers(ctx, t.Sync.Provider, t.Sync.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Members = make([]string, 0, len(users))
+	for _, user := range users {
+		t.Members = append(t.Members, string(t.Sync.Provider)+":"+user.Login)
+	}
+	now := time.Now()
+	t.SyncedAt, t.UpdatedAt = &now, now
+	if err := s.store.Put(ctx, tenantdomain.IDFromContext(ctx), *t); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.logger).Info("Team synced",
+		logger.String("team", t.ID),
+		logger.String("provider", string(t.Sync.Provider)),
+		logger.String("group", t.Sync.Group),
+		logger.Int("members", len(t.Members)),
+	)
+	return t, nil
+}
+
+// checkWritable rejects changes to configured teams
+func (s *Service) checkWritable(ctx context.Context, id string) error {
+	configured, err := s.configured(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range configured {
+		if t.ID == id {
+			return team.ErrReadOnly
+		}
+	}
+	return nil
+}
+
+// People resolves a team's members to the people seen so far
+func (s *Service) People(ctx context.Context, t *team.Team) ([]identity.Person, error) {
+	return s.identities.Members(ctx, t.Members)
+}
+
+// Memberships maps the ID of every person seen in a team to their teams
+func (s *Service) Memberships(ctx context.Context) (map[string][]team.Team, error) {
+	teams, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := make(map[string][]team.Team)
+	for _, t := range teams {
+		people, err := s.identities.Members(ctx, t.Members)
+		if err != nil {
+			return nil, err
+		}
+		for _, person := range people {
+			memberships[person.ID] = append(memberships[person.ID], t)
+		}
+	}
+	return memberships, nil
+}
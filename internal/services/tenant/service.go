@@ -0,0 +1,107 @@
+package tenant
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+)
+
+// Service exposes the configured tenants
+type Service struct {
+	mu      sync.RWMutex
+	tenants map[string]*tenant.Tenant
+}
+
+func NewService(cfg *config.Config) *Service {
+	s := &Service{}
+	s.Reload(cfg.Tenants)
+	return s
+}
+
+// Reload replaces the configured tenants
+func (s *Service) Reload(configs []config.TenantConfig) {
+	tenants := make(map[string]*tenant.Tenant, len(configs))
+	for _, tenantCfg := range configs {
+		tenants[tenantCfg.ID] = newTenant(tenantCfg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants = tenants
+}
+
+func newTenant(cfg config.TenantConfig) *tenant.Tenant {
+	t := &tenant.Tenant{
+		ID:   cfg.ID,
+		Name: cfg.Name,
+	}
+	t.Repositories = newRepositories(cfg.Repositories)
+	for _, team := range cfg.Teams {
+		t.Teams = append(t.Teams, tenant.Team{
+			Name:         team.Name,
+			Members:      team.Members,
+			Repositories: newRepositories(team.Repositories),
+		})
+	}
+	return t
+}
+
+func newRepositories(configs []config.TrackedRepositoryConfig) []tenant.Repository {
+	var repos []tenant.Repository
+	for _, repo := range configs {
+		repos = append(repos, tenant.Repository{
+			Provider: vcs.ProviderType(repo.Provider),
+			Name:     repo.Name,
+		})
+	}
+	return repos
+}
+
+// Get returns a tenant by ID
+func (s *Service) Get(_ context.Context, id string) (*tenant.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tenants[id]
+	if !ok {
+		return nil, tenant.ErrNotFound
+	}
+	return t, nil
+}
+
+// Exists reports whether a tenant with the given ID is configured
+func (s *Service) Exists(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.tenants[id]
+	return ok
+}
+
+// MultiTenant reports whether tenants besides the default one are configured
+func (s *Service) MultiTenant() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tenants) > 1
+}
+
+// Current returns the tenant the request context is scoped to
+func (s *Service) Current(ctx context.Context) (*tenant.Tenant, error) {
+	return s.Get(ctx, tenant.IDFromContext(ctx))
+}
+
+// List returns all tenants ordered by ID
+func (s *Service) List(_ context.Context) []*tenant.Tenant {
+	s.mu.RLock()
+	tenants := make([]*tenant.Tenant, 0, len(s.tenants))
+	for _, t := range s.tenants {
+		tenants = append(tenants, t)
+	}
+	s.mu.RUnlock()
+	sort.Slice(tenants, func(i, j int) bool {
+		return tenants[i].ID < tenants[j].ID
+	})
+	return tenants
+}
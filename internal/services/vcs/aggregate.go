@@ -0,0 +1,321 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// aggregatePageSize is the page size used when reading a repository in full
+const aggregatePageSize = 100
+
+// RepositoryRef names a repository on a provider
+type RepositoryRef struct {
+	Provider vcs.ProviderType
+	// Name is "owner/name" on GitHub and the full project path on GitLab
+	Name string
+}
+
+func (r RepositoryRef) String() string {
+	return string(r.Provider) + ":" + r.Name
+}
+
+// ActivityTotals counts the activity of one or more repositories
+type ActivityTotals struct {
+	Commits            int
+	PullRequests       int
+	MergedPullRequests int
+	Additions          int
+	Deletions          int
+	// Contributors counts distinct commit authors and co-authors after identity resolution
+	Contributors int
+}
+
+// RepositoryActivity is one repository's share of an aggregate
+type RepositoryActivity struct {
+	Repository RepositoryRef
+	ActivityTotals
+	// Truncated is set when the repository had more commits or pull requests
+	// than the per-repository limit; only the newest were counted
+	Truncated bool
+}
+
+// RepositoryFailure records a repository that could not be queried
+type RepositoryFailure struct {
+	Repository RepositoryRef
+	Err        error
+}
+
+// Activity is the merged activity of a set of repositories. Pull requests
+// are those created in the time range.
+type Activity struct {
+	Since  time.Time
+	Until  time.Time
+	Totals ActivityTotals
+	// Repositories holds the breakdown of every repository that was queried successfully
+	Repositories []RepositoryActivity
+	// Failures lists the repositories left out of the aggregate
+	Failures []RepositoryFailure
+	// Commits and PullRequests are merged across repositories, newest first
+	Commits      []vcs.Commit
+	PullRequests []vcs.PullRequest
+}
+
+// ActivityQuery selects the activity read from every repository
+type ActivityQuery struct {
+	Since  time.Time
+	Until  time.Time
+	Filter vcs.AuthorFilter
+	// CommitFiles and PullRequestFiles load the changed files of every
+	// commit or pull request, one upstream request each
+	CommitFiles      bool
+	PullRequestFiles bool
+	// PullRequestReviews loads the reviews of every pull request, one upstream request each
+	PullRequestReviews bool
+	// SkipPullRequests leaves pull requests unread for reports built on commits alone
+	SkipPullRequests bool
+	// SkipCommits leaves commits unread for reports built on pull requests alone
+	SkipCommits bool
+}
+
+// Partial reports whether some repositories are missing from the aggregate
+func (a *Activity) Partial() bool {
+	return len(a.Failures) > 0
+}
+
+// Aggregator queries many repositories concurrently and merges the results
+type Aggregator struct {
+	service *Service
+	config  config.AggregationConfig
+	logger  logger.Logger
+}
+
+func NewAggregator(service *Service, cfg *config.Config, log logger.Logger) *Aggregator {
+	return &Aggregator{
+		service: service,
+		config:  cfg.Aggregation,
+		logger:  log.With(logger.String("component", "aggregator")),
+	}
+}
+
+// OwnerRepositories discovers the repositories of an organization, user or group
+func (a *Aggregator) OwnerRepositories(ctx context.Context, providerType vcs.ProviderType, owner string, filter vcs.RepositoryFilter) ([]RepositoryRef, error) {
+	repos, err := a.service.DiscoverRepositories(ctx, providerType, owner, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]RepositoryRef, 0, len(repos))
+	for _, repo := range repos {
+		refs = append(refs, RepositoryRef{Provider: providerType, Name: repo.Path})
+	}
+	return refs, nil
+}
+
+// Activity reads the commits and pull requests of every repository in the
+// time range whose authors pass the filter. Members are matched once every
+// repository was read, so that aliases linked along the way count.
+// Repositories that fail are reported in Activity.Failures; an error is only
+// returned when the input is invalid or every repository failed.
+func (a *Aggregator) Activity(ctx context.Context, repos []RepositoryRef, query ActivityQuery) (*Activity, error) {
+	repos = uniqueRepositories(repos)
+	if len(repos) == 0 {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("no repositories selected"))
+	}
+	if len(repos) > a.config.MaxRepositories {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("%d repositories selected, at most %d are allowed", len(repos), a.config.MaxRepositories))
+	}
+
+	// Bots are left out while reading; members only once identities settled
+	fetchQuery := query
+	fetchQuery.Filter.Members = nil
+
+	results := make([]repositoryResult, len(repos))
+	semaphore := make(chan struct{}, a.config.Concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo RepositoryRef) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = a.repositoryActivity(ctx, repo, fetchQuery)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	activity := &Activity{Since: query.Since, Until: query.Until}
+	for i, result := range results {
+		if result.err != nil {
+			logger.FromContext(ctx, a.logger).Warn("Repository left out of aggregate",
+				logger.String("repository", repos[i].String()),
+				logger.Error(result.err),
+			)
+			activity.Failures = append(activity.Failures, RepositoryFailure{Repository: repos[i], Err: result.err})
+			continue
+		}
+
+		a.service.refreshContributors(ctx, repos[i].Provider, result.commits, result.pullRequests)
+		commits := a.service.filterCommits(ctx, result.commits, query.Filter)
+		prs := a.service.filterPullRequests(ctx, result.pullRequests, query.Filter)
+
+		repoActivity := RepositoryActivity{
+			Repository:     repos[i],
+			ActivityTotals: totals(commits, prs),
+			Truncated:      result.truncated,
+		}
+		repoActivity.Contributors = countContributors(commits)
+
+		activity.Repositories = append(activity.Repositories, repoActivity)
+		activity.Commits = append(activity.Commits, commits...)
+		activity.PullRequests = append(activity.PullRequests, prs...)
+	}
+	if len(activity.Repositories) == 0 {
+		return nil, fmt.Errorf("all %d repositories failed, first error: %w", len(repos), activity.Failures[0].Err)
+	}
+
+	sort.SliceStable(activity.Commits, func(i, j int) bool {
+		return activity.Commits[i].CommittedAt.After(activity.Commits[j].CommittedAt)
+	})
+	sort.SliceStable(activity.PullRequests, func(i, j int) bool {
+		return activity.PullRequests[i].CreatedAt.After(activity.PullRequests[j].CreatedAt)
+	})
+
+	activity.Totals = totals(activity.Commits, activity.PullRequests)
+	activity.Totals.Contributors = countContributors(activity.Commits)
+	return activity, nil
+}
+
+type repositoryResult struct {
+	commits      []vcs.Commit
+	pullRequests []vcs.PullRequest
+	truncated    bool
+	err          error
+}
+
+func (a *Aggregator) repositoryActivity(ctx context.Context, repo RepositoryRef, query ActivityQuery) repositoryResult {
+	var commits []vcs.Commit
+	var commitsTruncated bool
+	if !query.SkipCommits {
+		var err error
+		commits, commitsTruncated, err = collect(a.config.MaxItemsPerRepository, func(offset, limit int) ([]vcs.Commit, int64, error) {
+			commits, total, _, err := a.service.GetCommits(ctx, repo.Provider, repo.Name, query.Since, query.Until, query.Filter, offset, limit)
+			return commits, total, err
+		})
+		if err != nil {
+			return repositoryResult{err: err}
+		}
+	}
+	if query.CommitFiles {
+		if err := a.service.LoadCommitFiles(ctx, repo.Provider, repo.Name, commits); err != nil {
+			return repositoryResult{err: err}
+		}
+	}
+	if query.SkipPullRequests {
+		return repositoryResult{commits: commits, truncated: commitsTruncated}
+	}
+
+	prs, prsTruncated, err := collect(a.config.MaxItemsPerRepository, func(offset, limit int) ([]vcs.PullRequest, int64, error) {
+		prs, total, _, err := a.service.GetPullRequests(ctx, repo.Provider, repo.Name, query.Since, query.Until, query.Filter, offset, limit)
+		return prs, total, err
+	})
+	if err != nil {
+		return repositoryResult{err: err}
+	}
+	if query.PullRequestFiles {
+		if err := a.service.LoadPullRequestFiles(ctx, repo.Provider, repo.Name, prs); err != nil {
+			return repositoryResult{err: err}
+		}
+	}
+	if query.PullRequestReviews {
+		if err := a.service.LoadPullRequestReviews(ctx, repo.Provider, repo.Name, prs); err != nil {
+			return repositoryResult{err: err}
+		}
+	}
+
+	return repositoryResult{commits: commits, pullRequests: prs, truncated: commitsTruncated || prsTruncated}
+}
+
+// collect pages through a provider listing until it is exhausted or max
+// items were read. The second result reports whether items were left unread.
+func collect[T any](max int, fetch func(offset, limit int) ([]T, int64, error)) ([]T, bool, error) {
+	var items []T
+	for offset := 0; ; offset += aggregatePageSize {
+		page, total, err := fetch(offset, aggregatePageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, page...)
+		if len(items) >= max {
+			return items[:max], true, nil
+		}
+
+		// Providers that filter a page after fetching it return short pages
+		// before the end, so the reported total decides as well
+		if len(page) < aggregatePageSize && int64(offset+aggregatePageSize) >= total {
+			return items, false, nil
+		}
+	}
+}
+
+func totals(commits []vcs.Commit, prs []vcs.PullRequest) ActivityTotals {
+	t := ActivityTotals{
+		Commits:      len(commits),
+		PullRequests: len(prs),
+	}
+	for _, commit := range commits {
+		t.Additions += commit.Additions
+		t.Deletions += commit.Deletions
+	}
+	for _, pr := range prs {
+		if pr.MergedAt != nil {
+			t.MergedPullRequests++
+		}
+	}
+	return t
+}
+
+// countContributors counts the distinct authors and co-authors of commits
+func countContributors(commits []vcs.Commit) int {
+	contributors := make(map[string]bool)
+	for _, commit := range commits {
+		contributors[contributorKey(commit.Contributor, commit.AuthorEmail, commit.AuthorName)] = true
+		for _, coAuthor := range commit.CoAuthors {
+			contributors[contributorKey(coAuthor.Contributor, coAuthor.Email, coAuthor.Name)] = true
+		}
+	}
+	return len(contributors)
+}
+
+// contributorKey identifies a commit author or co-author by their resolved
+// identity, falling back to the e-mail and then the name
+func contributorKey(contributor *vcs.Contributor, email, name string) string {
+	if contributor != nil {
+		return contributor.ID
+	}
+	if email != "" {
+		return strings.ToLower(email)
+	}
+	return name
+}
+
+// uniqueRepositories drops repeated repositories, keeping the first occurrence
+func uniqueRepositories(repos []RepositoryRef) []RepositoryRef {
+	seen := make(map[RepositoryRef]bool, len(repos))
+	unique := make([]RepositoryRef, 0, len(repos))
+	for _, repo := range repos {
+		if !seen[repo] {
+			seen[repo] = true
+			unique = append(unique, repo)
+		}
+	}
+	return unique
+}
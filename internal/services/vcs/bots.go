@@ -0,0 +1,111 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/vcs"
+)
+
+// botSuffix ends the logins of GitHub Apps and the names and e-mails they commit with
+const botSuffix = "[bot]"
+
+// BotDetector classifies commit and pull request authors as automation accounts
+type BotDetector struct {
+	patterns []*regexp.Regexp
+	// accounts holds the lower-cased logins and e-mails listed in the configuration
+	accounts map[string]bool
+}
+
+func NewBotDetector(cfg *config.Config) (*BotDetector, error) {
+	d := &BotDetector{accounts: make(map[string]bool, len(cfg.Bots.Accounts))}
+	for _, pattern := range cfg.Bots.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bot pattern %q: %w", pattern, err)
+		}
+		d.patterns = append(d.patterns, re)
+	}
+	for _, account := range cfg.Bots.Accounts {
+		if account = strings.ToLower(strings.TrimSpace(account)); account != "" {
+			d.accounts[account] = true
+		}
+	}
+	return d, nil
+}
+
+// IsBot reports whether an author with the given login, e-mail and name is a bot
+func (d *BotDetector) IsBot(login, email, name string) bool {
+	local, _, _ := strings.Cut(email, "@")
+	for _, value := range []string{login, local, name} {
+		if strings.HasSuffix(strings.ToLower(strings.TrimSpace(value)), botSuffix) {
+			return true
+		}
+	}
+
+	for _, value := range []string{login, email} {
+		if value != "" && d.accounts[strings.ToLower(value)] {
+			return true
+		}
+	}
+
+	for _, re := range d.patterns {
+		for _, value := range []string{login, email, name} {
+			if value != "" && re.MatchString(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authorMatcher returns a predicate applying the filter to classified authors.
+// Members are resolved to people once, when the matcher is created.
+func (s *Service) authorMatcher(ctx context.Context, filter vcs.AuthorFilter) func(bot bool, contributor *vcs.Contributor) bool {
+	var members map[string]bool
+	if filter.Members != nil {
+		members = s.contributors.ContributorIDs(ctx, filter.Members)
+	}
+	return func(bot bool, contributor *vcs.Contributor) bool {
+		if bot && !filter.IncludeBots {
+			return false
+		}
+		return members == nil || (contributor != nil && members[contributor.ID])
+	}
+}
+
+// filterCommits classifies commit authors and drops the commits the filter rejects
+func (s *Service) filterCommits(ctx context.Context, commits []vcs.Commit, filter vcs.AuthorFilter) []vcs.Commit {
+	matches := s.authorMatcher(ctx, filter)
+	kept := commits[:0]
+	for _, commit := range commits {
+		commit.AuthorBot = commit.AuthorBot || s.bots.IsBot(commit.AuthorLogin, commit.AuthorEmail, commit.AuthorName)
+		if matches(commit.AuthorBot, commit.Contributor) {
+			kept = append(kept, commit)
+		}
+	}
+	return kept
+}
+
+// filterPullRequests classifies pull request authors and reviewers and drops
+// the pull requests the filter rejects
+func (s *Service) filterPullRequests(ctx context.Context, prs []vcs.PullRequest, filter vcs.AuthorFilter) []vcs.PullRequest {
+	matches := s.authorMatcher(ctx, filter)
+	kept := prs[:0]
+	for _, pr := range prs {
+		pr.AuthorBot = pr.AuthorBot || s.bots.IsBot(pr.AuthorLogin, "", pr.AuthorName)
+		for i := range pr.Reviews {
+			pr.Reviews[i].Bot = pr.Reviews[i].Bot || s.bots.IsBot(pr.Reviews[i].Login, "", "")
+		}
+		for i := range pr.RequestedReviewers {
+			pr.RequestedReviewers[i].Bot = pr.RequestedReviewers[i].Bot || s.bots.IsBot(pr.RequestedReviewers[i].Login, "", "")
+		}
+		if matches(pr.AuthorBot, pr.Contributor) {
+			kept = append(kept, pr)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,127 @@
+package vcs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"devmetrics/internal/config"
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
+)
+
+func TestIsBot(t *testing.T) {
+	detector, err := NewBotDetector(&config.Config{Bots: config.BotsConfig{
+		Patterns: []string{`^renovate`},
+		Accounts: []string{"CI@Example.com", "deploy-user"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                string
+		login, email, named string
+		want                bool
+	}{
+		{"GitHub App login", "dependabot[bot]", "", "", true},
+		{"GitHub App e-mail", "", "49699333+dependabot[bot]@users.noreply.github.com", "", true},
+		{"GitHub App name", "", "", "github-actions[bot]", true},
+		{"listed e-mail ignoring case", "", "ci@example.com", "CI", true},
+		{"listed login", "deploy-user", "", "", true},
+		{"pattern", "renovate-bot", "", "", true},
+		{"person", "jane", "jane@example.com", "Jane Doe", false},
+		{"bot in the middle", "robotics", "", "Bot Builder", false},
+		{"unknown author", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detector.IsBot(tt.login, tt.email, tt.named); got != tt.want {
+				t.Errorf("IsBot(%q, %q, %q) = %v, want %v", tt.login, tt.email, tt.named, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBotDetectorInvalidPattern(t *testing.T) {
+	if _, err := NewBotDetector(&config.Config{Bots: config.BotsConfig{Patterns: []string{"("}}}); err == nil {
+		t.Error("NewBotDetector accepted an invalid pattern")
+	}
+}
+
+// pageProvider serves a fixed page of commits and pull requests
+type pageProvider struct {
+	vcs.Provider
+	commits []vcs.Commit
+	prs     []vcs.PullRequest
+	total   int64
+}
+
+func (p pageProvider) GetCommits(context.Context, string, time.Time, time.Time, int, int) ([]vcs.Commit, int64, error) {
+	return append([]vcs.Commit(nil), p.commits...), p.total, nil
+}
+
+func (p pageProvider) GetPullRequests(context.Context, string, time.Time, time.Time, int, int) ([]vcs.PullRequest, int64, error) {
+	return append([]vcs.PullRequest(nil), p.prs...), p.total, nil
+}
+
+type noContributors struct{}
+
+func (noContributors) ResolveCommits(context.Context, vcs.ProviderType, vcs.Provider, []vcs.Commit) {}
+
+func (noContributors) ResolvePullRequests(context.Context, vcs.ProviderType, vcs.Provider, []vcs.PullRequest) {
+}
+
+func (noContributors) ContributorIDs(context.Context, []string) map[string]bool { return nil }
+
+func TestGetCommitsFiltered(t *testing.T) {
+	provider := pageProvider{
+		commits: []vcs.Commit{
+			{SHA: "1", AuthorLogin: "jane"},
+			{SHA: "2", AuthorLogin: "dependabot[bot]"},
+			{SHA: "3", AuthorName: "github-actions[bot]"},
+		},
+		prs: []vcs.PullRequest{
+			{Number: 1, AuthorLogin: "renovate[bot]"},
+			{Number: 2, AuthorLogin: "jane"},
+		},
+		total: 120,
+	}
+	detector, err := NewBotDetector(&config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewService(Providers{tenant.DefaultID: {vcs.ProviderGitHub: provider}}, noContributors{}, detector, logger.NewNop())
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	tests := []struct {
+		name         string
+		filter       vcs.AuthorFilter
+		wantCommits  int
+		wantPRs      int
+		wantFiltered [2]int
+	}{
+		{"bots left out", vcs.AuthorFilter{}, 1, 1, [2]int{2, 1}},
+		{"bots included", vcs.AuthorFilter{IncludeBots: true}, 3, 2, [2]int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, total, filtered, err := s.GetCommits(ctx, vcs.ProviderGitHub, "acme/api", time.Time{}, time.Time{}, tt.filter, 0, 30)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(commits) != tt.wantCommits || total != 120 || filtered != tt.wantFiltered[0] {
+				t.Errorf("GetCommits = %d commits, total %d, filtered %d, want %d, 120, %d", len(commits), total, filtered, tt.wantCommits, tt.wantFiltered[0])
+			}
+
+			prs, total, filtered, err := s.GetPullRequests(ctx, vcs.ProviderGitHub, "acme/api", time.Time{}, time.Time{}, tt.filter, 0, 30)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(prs) != tt.wantPRs || total != 120 || filtered != tt.wantFiltered[1] {
+				t.Errorf("GetPullRequests = %d pull requests, total %d, filtered %d, want %d, 120, %d", len(prs), total, filtered, tt.wantPRs, tt.wantFiltered[1])
+			}
+		})
+	}
+}
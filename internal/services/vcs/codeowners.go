@@ -0,0 +1,208 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/codeowners"
+	"devmetrics/pkg/logger"
+)
+
+// CodeOwners is the CODEOWNERS file of a repository
+type CodeOwners struct {
+	// Path is where the file was found
+	Path string
+	File *codeowners.File
+}
+
+// OwnerMatcher reports whether a reviewer, given by login, is one of a code
+// owner's accounts or a member of the owning team
+type OwnerMatcher func(owner, reviewerLogin string) bool
+
+// RepositoryOwnership holds what an ownership report needs of one repository
+type RepositoryOwnership struct {
+	Repository RepositoryRef
+	// CodeOwners is nil when the repository has no CODEOWNERS file
+	CodeOwners *CodeOwners
+	Tree       *vcs.Tree
+	IsOwner    OwnerMatcher
+}
+
+// dialect returns the CODEOWNERS syntax a provider understands
+func dialect(providerType vcs.ProviderType) codeowners.Dialect {
+	if providerType == vcs.ProviderGitLab {
+		return codeowners.GitLab
+	}
+	return codeowners.GitHub
+}
+
+// CodeOwners finds and parses the CODEOWNERS file of a repository in the
+// places the provider looks for it; nil when there is none
+func (s *Service) CodeOwners(ctx context.Context, providerType vcs.ProviderType, repo string) (*CodeOwners, error) {
+	if repo == "" {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository is required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range codeowners.Locations(dialect(providerType)) {
+		content, err := provider.GetFileContent(ctx, repo, path)
+		if errors.Is(err, vcs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", path, err)
+		}
+		return &CodeOwners{Path: path, File: codeowners.Parse(string(content), dialect(providerType))}, nil
+	}
+	return nil, nil
+}
+
+// Tree lists the files of a repository's default branch
+func (s *Service) Tree(ctx context.Context, providerType vcs.ProviderType, repo string) (*vcs.Tree, error) {
+	if repo == "" {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository is required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := provider.GetTree(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree: %w", err)
+	}
+	return tree, nil
+}
+
+// ownerMatcher resolves the owners of a CODEOWNERS file once: users by login
+// and linked identity, e-mail addresses by identity and teams by their
+// members. Roles such as @@maintainer match nobody. GitLab groups are only
+// recognized when written with their parent path. The matcher resolves
+// reviewers lazily and isn't safe for concurrent use.
+func (s *Service) ownerMatcher(ctx context.Context, providerType vcs.ProviderType, file *codeowners.File) OwnerMatcher {
+	logins := make(map[string]bool)
+	people := make(map[string]map[string]bool)
+	teams := make(map[string]map[string]bool)
+	for _, owner := range file.AllOwners() {
+		key := strings.ToLower(owner)
+		switch {
+		case codeowners.IsRole(owner):
+		case codeowners.IsTeam(owner):
+			members, err := s.GroupMembers(ctx, providerType, owner[1:])
+			if err != nil {
+				logger.FromContext(ctx, s.logger).Warn("Code owner team members unavailable",
+					logger.String("team", owner),
+					logger.Error(err),
+				)
+			}
+			teams[key] = make(map[string]bool, len(members))
+			for _, member := range members {
+				teams[key][strings.ToLower(member.Login)] = true
+			}
+		case codeowners.IsEmail(owner):
+			people[key] = s.contributors.ContributorIDs(ctx, []string{owner})
+		default:
+			logins[key] = true
+			people[key] = s.contributors.ContributorIDs(ctx, []string{string(providerType) + ":" + owner[1:]})
+		}
+	}
+
+	reviewers := make(map[string]map[string]bool)
+	return func(owner, reviewerLogin string) bool {
+		owner, reviewer := strings.ToLower(owner), strings.ToLower(reviewerLogin)
+		if teams[owner][reviewer] || (logins[owner] && owner[1:] == reviewer) {
+			return true
+		}
+		if len(people[owner]) == 0 {
+			return false
+		}
+		ids, ok := reviewers[reviewer]
+		if !ok {
+			ids = s.contributors.ContributorIDs(ctx, []string{string(providerType) + ":" + reviewerLogin})
+			reviewers[reviewer] = ids
+		}
+		for id := range ids {
+			if people[owner][id] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Ownership reads the CODEOWNERS file and the file tree of every repository.
+// Repositories that fail are returned as failures; an error is only returned
+// when the input is invalid or every repository failed.
+func (a *Aggregator) Ownership(ctx context.Context, repos []RepositoryRef) ([]RepositoryOwnership, []RepositoryFailure, error) {
+	repos = uniqueRepositories(repos)
+	if len(repos) == 0 {
+		return nil, nil, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("no repositories selected"))
+	}
+	if len(repos) > a.config.MaxRepositories {
+		return nil, nil, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("%d repositories selected, at most %d are allowed", len(repos), a.config.MaxRepositories))
+	}
+
+	results := make([]RepositoryOwnership, len(repos))
+	errs := make([]error, len(repos))
+	semaphore := make(chan struct{}, a.config.Concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func(i int, repo RepositoryRef) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i], errs[i] = a.repositoryOwnership(ctx, repo)
+		}(i, repo)
+	}
+	wg.Wait()
+
+	var ownership []RepositoryOwnership
+	var failures []RepositoryFailure
+	for i, err := range errs {
+		if err != nil {
+			logger.FromContext(ctx, a.logger).Warn("Repository left out of ownership report",
+				logger.String("repository", repos[i].String()),
+				logger.Error(err),
+			)
+			failures = append(failures, RepositoryFailure{Repository: repos[i], Err: err})
+			continue
+		}
+		ownership = append(ownership, results[i])
+	}
+	if len(ownership) == 0 {
+		return nil, failures, fmt.Errorf("all %d repositories failed, first error: %w", len(repos), failures[0].Err)
+	}
+	return ownership, failures, nil
+}
+
+func (a *Aggregator) repositoryOwnership(ctx context.Context, repo RepositoryRef) (RepositoryOwnership, error) {
+	ownership := RepositoryOwnership{Repository: repo}
+
+	owners, err := a.service.CodeOwners(ctx, repo.Provider, repo.Name)
+	if err != nil {
+		return ownership, err
+	}
+	ownership.CodeOwners = owners
+
+	if ownership.Tree, err = a.service.Tree(ctx, repo.Provider, repo.Name); err != nil {
+		return ownership, err
+	}
+
+	if owners != nil {
+		ownership.IsOwner = a.service.ownerMatcher(ctx, repo.Provider, owners.File)
+	} else {
+		ownership.IsOwner = func(string, string) bool { return false }
+	}
+	return ownership, nil
+}
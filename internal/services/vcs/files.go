@@ -0,0 +1,88 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// CommitFiles returns the files changed by a commit
+func (s *Service) CommitFiles(ctx context.Context, providerType vcs.ProviderType, repo, sha string) ([]vcs.FileChange, error) {
+	if repo == "" || sha == "" {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository and commit are required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := provider.GetCommitFiles(ctx, repo, sha)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit files: %w", err)
+	}
+	return files, nil
+}
+
+// PullRequestFiles returns the files changed by a pull request
+func (s *Service) PullRequestFiles(ctx context.Context, providerType vcs.ProviderType, repo string, number int) ([]vcs.FileChange, error) {
+	if repo == "" || number <= 0 {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository and pull request number are required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := provider.GetPullRequestFiles(ctx, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request files: %w", err)
+	}
+	return files, nil
+}
+
+// LoadCommitFiles sets the files of every commit, with the changed file
+// count and, where the listing had none, the line counts derived from them
+func (s *Service) LoadCommitFiles(ctx context.Context, providerType vcs.ProviderType, repo string, commits []vcs.Commit) error {
+	return loadEach(len(commits), func(i int) error {
+		files, err := s.CommitFiles(ctx, providerType, repo, commits[i].SHA)
+		if err != nil {
+			return err
+		}
+		commits[i].Files = files
+		changed := len(files)
+		commits[i].ChangedFiles = &changed
+		if commits[i].Additions == 0 && commits[i].Deletions == 0 {
+			commits[i].Additions, commits[i].Deletions = sumLines(files)
+		}
+		return nil
+	})
+}
+
+// LoadPullRequestFiles sets the files of every pull request, with the
+// changed file count and, where the listing had none, the line counts
+// derived from them
+func (s *Service) LoadPullRequestFiles(ctx context.Context, providerType vcs.ProviderType, repo string, prs []vcs.PullRequest) error {
+	return loadEach(len(prs), func(i int) error {
+		files, err := s.PullRequestFiles(ctx, providerType, repo, prs[i].Number)
+		if err != nil {
+			return err
+		}
+		prs[i].Files = files
+		prs[i].ChangedFiles = len(files)
+		if prs[i].Additions == 0 && prs[i].Deletions == 0 {
+			prs[i].Additions, prs[i].Deletions = sumLines(files)
+		}
+		return nil
+	})
+}
+
+func sumLines(files []vcs.FileChange) (additions, deletions int) {
+	for _, file := range files {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	return additions, deletions
+}
@@ -0,0 +1,66 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// filesProvider serves the files of commits by SHA; unknown commits fail
+type filesProvider struct {
+	vcs.Provider
+	files map[string][]vcs.FileChange
+}
+
+func (p filesProvider) GetCommitFiles(_ context.Context, _, sha string) ([]vcs.FileChange, error) {
+	files, ok := p.files[sha]
+	if !ok {
+		return nil, vcs.ErrNotFound
+	}
+	return files, nil
+}
+
+func TestLoadCommitFiles(t *testing.T) {
+	provider := filesProvider{files: map[string][]vcs.FileChange{
+		"a": {{Path: "main.go", Additions: 3, Deletions: 1}, {Path: "go.mod", Additions: 2}},
+		"b": {{Path: "README.md", Additions: 1}},
+		"c": {},
+	}}
+	s := NewService(Providers{tenant.DefaultID: {vcs.ProviderGitHub: provider}}, nil, nil, logger.NewNop())
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	commits := []vcs.Commit{{SHA: "a"}, {SHA: "b", Additions: 10, Deletions: 4}, {SHA: "c"}}
+	if err := s.LoadCommitFiles(ctx, vcs.ProviderGitHub, "acme/api", commits); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sha                  string
+		changedFiles         int
+		additions, deletions int
+	}{
+		{"a", 2, 3 + 2, 1},
+		{"b", 1, 10, 4},
+		{"c", 0, 0, 0},
+	}
+	for i, tt := range tests {
+		t.Run(tt.sha, func(t *testing.T) {
+			c := commits[i]
+			if c.ChangedFiles == nil || *c.ChangedFiles != tt.changedFiles {
+				t.Errorf("ChangedFiles = %v, want %d", c.ChangedFiles, tt.changedFiles)
+			}
+			if c.Additions != tt.additions || c.Deletions != tt.deletions {
+				t.Errorf("lines = +%d -%d, want +%d -%d", c.Additions, c.Deletions, tt.additions, tt.deletions)
+			}
+		})
+	}
+
+	unknown := []vcs.Commit{{SHA: "a"}, {SHA: "missing"}}
+	if err := s.LoadCommitFiles(ctx, vcs.ProviderGitHub, "acme/api", unknown); !errors.Is(err, vcs.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,32 @@
+package vcs
+
+import "sync"
+
+// loadConcurrency bounds the upstream requests made at once to load the
+// details of one set of commits or pull requests
+const loadConcurrency = 8
+
+// loadEach runs load for items 0 to n-1 concurrently and returns the first error
+func loadEach(n int, load func(i int) error) error {
+	errs := make([]error, n)
+	semaphore := make(chan struct{}, loadConcurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			errs[i] = load(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
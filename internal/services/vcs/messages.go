@@ -0,0 +1,47 @@
+package vcs
+
+import (
+	"strings"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/commitmsg"
+)
+
+// parseCommitMessages sets the fields of commits that are read from their
+// messages. Merges reported by the provider stay merges.
+func parseCommitMessages(commits []vcs.Commit) {
+	for i := range commits {
+		commit := &commits[i]
+		message := commitmsg.Parse(commit.Message)
+
+		commit.Type, commit.Scope, commit.Breaking = message.Type, message.Scope, message.Breaking
+		commit.Merge = commit.Merge || message.Merge
+		commit.Revert, commit.RevertedSHA = message.Revert, message.RevertedSHA
+
+		commit.Trailers, commit.CoAuthors, commit.SignedOffBy = nil, nil, nil
+		for _, trailer := range message.Trailers {
+			commit.Trailers = append(commit.Trailers, vcs.Trailer{Token: trailer.Token, Value: trailer.Value})
+		}
+		commit.CoAuthors = commitPeople(message.Values("Co-authored-by"))
+		commit.SignedOffBy = commitPeople(message.Values("Signed-off-by"))
+	}
+}
+
+// commitPeople reads "Name <email>" trailer values, dropping repeated addresses
+func commitPeople(values []string) []vcs.CommitPerson {
+	var people []vcs.CommitPerson
+	seen := make(map[string]bool)
+	for _, value := range values {
+		name, email := commitmsg.ParseSignature(value)
+		key := strings.ToLower(email)
+		if key == "" {
+			key = name
+		}
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		people = append(people, vcs.CommitPerson{Name: name, Email: email})
+	}
+	return people
+}
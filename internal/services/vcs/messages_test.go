@@ -0,0 +1,40 @@
+package vcs
+
+import (
+	"reflect"
+	"testing"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+func TestParseCommitMessagesCoAuthors(t *testing.T) {
+	commits := []vcs.Commit{{
+		Message: "feat(api)!: x\n\n" +
+			"Co-authored-by: Jane Doe <jane@example.com>\n" +
+			"Co-authored-by: Jane D. <JANE@example.com>\n" +
+			"Co-authored-by: Joe\n" +
+			"Co-authored-by: Joe\n" +
+			"Signed-off-by: Jane Doe <jane@example.com>",
+		Merge: true,
+	}}
+	parseCommitMessages(commits)
+	commit := commits[0]
+
+	wantCoAuthors := []vcs.CommitPerson{{Name: "Jane Doe", Email: "jane@example.com"}, {Name: "Joe"}}
+	if !reflect.DeepEqual(commit.CoAuthors, wantCoAuthors) {
+		t.Errorf("CoAuthors = %+v, want %+v", commit.CoAuthors, wantCoAuthors)
+	}
+	wantSignedOff := []vcs.CommitPerson{{Name: "Jane Doe", Email: "jane@example.com"}}
+	if !reflect.DeepEqual(commit.SignedOffBy, wantSignedOff) {
+		t.Errorf("SignedOffBy = %+v, want %+v", commit.SignedOffBy, wantSignedOff)
+	}
+	if commit.Type != "feat" || commit.Scope != "api" || !commit.Breaking {
+		t.Errorf("Type, Scope, Breaking = %q, %q, %v, want feat, api, true", commit.Type, commit.Scope, commit.Breaking)
+	}
+	if !commit.Merge {
+		t.Error("Merge reported by the provider was cleared")
+	}
+	if len(commit.Trailers) != 5 {
+		t.Errorf("Trailers = %d, want 5", len(commit.Trailers))
+	}
+}
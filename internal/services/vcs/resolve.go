@@ -0,0 +1,105 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"devmetrics/internal/domain/tenant"
+	"devmetrics/internal/domain/vcs"
+)
+
+// scpLikeURL matches SSH clone URLs such as git@github.com:acme/api.git
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@([^:/]+):(.+)$`)
+
+// wellKnownHosts are recognised even when the tenant has no provider for them,
+// so the caller gets a "not configured" error rather than an unknown host
+var wellKnownHosts = map[string]vcs.ProviderType{
+	"github.com": vcs.ProviderGitHub,
+	"gitlab.com": vcs.ProviderGitLab,
+}
+
+// Repository validates a provider name and a repository path: "owner/name"
+// on GitHub, a numeric ID or full project path with any number of groups on
+// GitLab. A trailing ".git" is ignored. A "-" segment is never part of a
+// path; it separates the repository from the resource in routes.
+func (s *Service) Repository(provider, path string) (RepositoryRef, error) {
+	providerType := vcs.ProviderType(strings.ToLower(provider))
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	switch providerType {
+	case vcs.ProviderGitHub:
+		if parts := strings.Split(path, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" || slices.Contains(parts, "-") {
+			return RepositoryRef{}, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository must be owner/name, got %q", path))
+		}
+	case vcs.ProviderGitLab:
+		if path == "" || strings.Contains(path, "//") || slices.Contains(strings.Split(path, "/"), "-") {
+			return RepositoryRef{}, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("invalid project path %q", path))
+		}
+	case vcs.ProviderBitbucket:
+		return RepositoryRef{}, vcs.NewError(vcs.ErrProviderNotConfigured, providerType, "", nil)
+	default:
+		return RepositoryRef{}, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("unknown provider %q", provider))
+	}
+
+	return RepositoryRef{Provider: providerType, Name: path}, nil
+}
+
+// ResolveRepository resolves an HTTPS or SSH clone URL, a repository web
+// page URL or a "provider:path" slug. Hosts are matched against the
+// tenant's providers, so GitHub Enterprise and self-hosted GitLab work.
+func (s *Service) ResolveRepository(ctx context.Context, raw string) (RepositoryRef, error) {
+	raw = strings.TrimSpace(raw)
+
+	var host, path string
+	if match := scpLikeURL.FindStringSubmatch(raw); match != nil {
+		host, path = match[1], match[2]
+	} else if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			return RepositoryRef{}, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("invalid repository URL %q", raw))
+		}
+		host, path = u.Hostname(), u.Path
+	} else if provider, slugPath, ok := strings.Cut(raw, ":"); ok {
+		return s.Repository(provider, slugPath)
+	} else {
+		return RepositoryRef{}, vcs.NewError(vcs.ErrInvalidInput, "", "", fmt.Errorf("%q is neither a repository URL nor provider:path", raw))
+	}
+
+	providerType, ok := s.providerForHost(ctx, host)
+	if !ok {
+		return RepositoryRef{}, vcs.NewError(vcs.ErrProviderNotConfigured, "", "", fmt.Errorf("no provider configured for host %q", host))
+	}
+	return s.Repository(string(providerType), webPath(providerType, path))
+}
+
+// providerForHost finds the tenant provider serving host
+func (s *Service) providerForHost(ctx context.Context, host string) (vcs.ProviderType, bool) {
+	for providerType, provider := range s.Providers()[tenant.IDFromContext(ctx)] {
+		if strings.EqualFold(provider.WebHost(), host) {
+			return providerType, true
+		}
+	}
+	providerType, ok := wellKnownHosts[strings.ToLower(host)]
+	return providerType, ok
+}
+
+// webPath strips the page part of a repository web URL, e.g. /pull/3 on
+// GitHub or /-/merge_requests/3 on GitLab
+func webPath(providerType vcs.ProviderType, path string) string {
+	path = strings.Trim(path, "/")
+	switch providerType {
+	case vcs.ProviderGitHub:
+		if parts := strings.SplitN(path, "/", 3); len(parts) == 3 {
+			return parts[0] + "/" + parts[1]
+		}
+	case vcs.ProviderGitLab:
+		if i := strings.Index(path, "/-/"); i >= 0 {
+			return path[:i]
+		}
+	}
+	return path
+}
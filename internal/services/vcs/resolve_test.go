@@ -0,0 +1,72 @@
+package vcs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
+)
+
+func TestRepository(t *testing.T) {
+	tests := []struct {
+		provider string
+		path     string
+		want     RepositoryRef
+		wantErr  error
+	}{
+		{"github", "acme/api", RepositoryRef{Provider: vcs.ProviderGitHub, Name: "acme/api"}, nil},
+		{"GitHub", "/acme/api.git/", RepositoryRef{Provider: vcs.ProviderGitHub, Name: "acme/api"}, nil},
+		{"github", "acme", RepositoryRef{}, vcs.ErrInvalidInput},
+		{"github", "acme/api/commits", RepositoryRef{}, vcs.ErrInvalidInput},
+		{"github", "acme/-", RepositoryRef{}, vcs.ErrInvalidInput},
+		{"gitlab", "group/sub/project", RepositoryRef{Provider: vcs.ProviderGitLab, Name: "group/sub/project"}, nil},
+		{"gitlab", "group/commits", RepositoryRef{Provider: vcs.ProviderGitLab, Name: "group/commits"}, nil},
+		{"gitlab", "1234", RepositoryRef{Provider: vcs.ProviderGitLab, Name: "1234"}, nil},
+		{"gitlab", "group/project/-/commits", RepositoryRef{}, vcs.ErrInvalidInput},
+		{"gitlab", "group//project", RepositoryRef{}, vcs.ErrInvalidInput},
+		{"gitlab", "", RepositoryRef{}, vcs.ErrInvalidInput},
+		{"bitbucket", "acme/api", RepositoryRef{}, vcs.ErrProviderNotConfigured},
+		{"svn", "trunk", RepositoryRef{}, vcs.ErrInvalidInput},
+	}
+	s := NewService(Providers{}, nil, nil, logger.NewNop())
+	for _, tt := range tests {
+		t.Run(tt.provider+":"+tt.path, func(t *testing.T) {
+			got, err := s.Repository(tt.provider, tt.path)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("Repository error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Repository = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRepository(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    RepositoryRef
+		wantErr error
+	}{
+		{"git@github.com:acme/api.git", RepositoryRef{Provider: vcs.ProviderGitHub, Name: "acme/api"}, nil},
+		{"https://github.com/acme/api/pull/3", RepositoryRef{Provider: vcs.ProviderGitHub, Name: "acme/api"}, nil},
+		{"https://gitlab.com/group/sub/project/-/merge_requests/3", RepositoryRef{Provider: vcs.ProviderGitLab, Name: "group/sub/project"}, nil},
+		{"gitlab:group/project", RepositoryRef{Provider: vcs.ProviderGitLab, Name: "group/project"}, nil},
+		{"https://git.example.com/acme/api", RepositoryRef{}, vcs.ErrProviderNotConfigured},
+		{"acme/api", RepositoryRef{}, vcs.ErrInvalidInput},
+	}
+	s := NewService(Providers{}, nil, nil, logger.NewNop())
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := s.ResolveRepository(context.Background(), tt.raw)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("ResolveRepository error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveRepository = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
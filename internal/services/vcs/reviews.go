@@ -0,0 +1,38 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+
+	"devmetrics/internal/domain/vcs"
+)
+
+// PullRequestReviews returns the reviews submitted on a pull request
+func (s *Service) PullRequestReviews(ctx context.Context, providerType vcs.ProviderType, repo string, number int) ([]vcs.Review, error) {
+	if repo == "" || number <= 0 {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository and pull request number are required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := provider.GetPullRequestReviews(ctx, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// LoadPullRequestReviews sets the reviews of every pull request
+func (s *Service) LoadPullRequestReviews(ctx context.Context, providerType vcs.ProviderType, repo string, prs []vcs.PullRequest) error {
+	return loadEach(len(prs), func(i int) error {
+		reviews, err := s.PullRequestReviews(ctx, providerType, repo, prs[i].Number)
+		if err != nil {
+			return err
+		}
+		prs[i].Reviews = reviews
+		return nil
+	})
+}
@@ -3,66 +3,222 @@ package vcs
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"devmetrics/internal/domain/tenant"
 	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
 )
 
+// Providers holds each tenant's provider instances keyed by tenant ID
+type Providers map[string]map[vcs.ProviderType]vcs.Provider
+
 type Service struct {
-	providers map[vcs.ProviderType]vcs.Provider
+	// providers is swapped as a whole on configuration reload; requests keep
+	// using the provider they looked up until they complete
+	providers    atomic.Pointer[Providers]
+	contributors vcs.ContributorResolver
+	bots         *BotDetector
+	logger       logger.Logger
+
+	statusMu sync.RWMutex
+	statuses []ProviderStatus
+	// failures are providers that could not be created for the current provider set
+	failures []ProviderStatus
 }
 
-func NewService(providers map[vcs.ProviderType]vcs.Provider) *Service {
-	return &Service{
-		providers: providers,
+func NewService(providers Providers, contributors vcs.ContributorResolver, bots *BotDetector, log logger.Logger) *Service {
+	s := &Service{
+		contributors: contributors,
+		bots:         bots,
+		logger:       log,
 	}
+	s.providers.Store(&providers)
+	return s
 }
 
-func (s *Service) GetRepository(ctx context.Context, providerType vcs.ProviderType, repo string) (*vcs.Repository, error) {
-	provider, ok := s.providers[providerType]
+// Providers returns the provider set currently serving requests
+func (s *Service) Providers() Providers {
+	return *s.providers.Load()
+}
+
+// SetProviders atomically replaces the provider set used by new requests.
+// Statuses recorded for the previous set are kept until the next verification.
+func (s *Service) SetProviders(providers Providers) {
+	s.providers.Store(&providers)
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.failures = nil
+}
+
+// provider looks up the provider of the request's tenant and logs unknown lookups
+func (s *Service) provider(ctx context.Context, providerType vcs.ProviderType) (vcs.Provider, error) {
+	tenantID := tenant.IDFromContext(ctx)
+	provider, ok := s.Providers()[tenantID][providerType]
 	if !ok {
-		return nil, fmt.Errorf("unsupported VCS provider: %s", providerType)
+		logger.FromContext(ctx, s.logger).Warn("VCS provider not configured",
+			logger.String("tenant", tenantID),
+			logger.String("provider", string(providerType)),
+		)
+		return nil, vcs.NewError(vcs.ErrProviderNotConfigured, providerType, "", nil)
+	}
+	return provider, nil
+}
+
+// refreshContributors resolves authors again so that items fetched at
+// different times agree on people whose aliases were linked in between
+func (s *Service) refreshContributors(ctx context.Context, providerType vcs.ProviderType, commits []vcs.Commit, prs []vcs.PullRequest) {
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return
+	}
+	s.contributors.ResolveCommits(ctx, providerType, provider, commits)
+	s.contributors.ResolvePullRequests(ctx, providerType, provider, prs)
+}
+
+// validateQuery rejects repository queries adapters cannot handle
+func validateQuery(providerType vcs.ProviderType, repo string, offset, limit int) error {
+	if repo == "" {
+		return vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository is required"))
+	}
+	if offset < 0 || limit <= 0 {
+		return vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("invalid pagination: offset=%d limit=%d", offset, limit))
+	}
+	return nil
+}
+
+func (s *Service) GetRepository(ctx context.Context, providerType vcs.ProviderType, repo string) (*vcs.Repository, error) {
+	if repo == "" {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("repository is required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
 	}
 
 	return provider.GetRepository(ctx, repo)
 }
 
+// DiscoverRepositories lists every repository of an organization, user or
+// group that passes the filter
+func (s *Service) DiscoverRepositories(ctx context.Context, providerType vcs.ProviderType, owner string, filter vcs.RepositoryFilter) ([]vcs.Repository, error) {
+	if owner == "" {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("owner is required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ListRepositories(ctx, owner, filter)
+}
+
+// GroupMembers lists the accounts of a GitHub team or GitLab group
+func (s *Service) GroupMembers(ctx context.Context, providerType vcs.ProviderType, group string) ([]vcs.User, error) {
+	if group == "" {
+		return nil, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("group is required"))
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ListGroupMembers(ctx, group)
+}
+
+// ListRepositories returns one page of an owner's discovered repositories
+func (s *Service) ListRepositories(
+	ctx context.Context,
+	providerType vcs.ProviderType,
+	owner string,
+	filter vcs.RepositoryFilter,
+	offset, limit int,
+) ([]vcs.Repository, int64, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, 0, vcs.NewError(vcs.ErrInvalidInput, providerType, "", fmt.Errorf("invalid pagination: offset=%d limit=%d", offset, limit))
+	}
+
+	repos, err := s.DiscoverRepositories(ctx, providerType, owner, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := int64(len(repos))
+	if offset >= len(repos) {
+		return []vcs.Repository{}, total, nil
+	}
+	end := offset + limit
+	if end > len(repos) {
+		end = len(repos)
+	}
+	return repos[offset:end], total, nil
+}
+
+// GetCommits returns one page of a repository's commits. Commits the filter
+// rejects are dropped from the page and counted in filtered; total counts
+// every commit upstream, including them.
 func (s *Service) GetCommits(
 	ctx context.Context,
 	providerType vcs.ProviderType,
 	repo string,
 	since, until time.Time,
+	filter vcs.AuthorFilter,
 	offset, limit int,
-) ([]vcs.Commit, int64, error) {
-	provider, ok := s.providers[providerType]
-	if !ok {
-		return nil, 0, fmt.Errorf("unsupported VCS provider: %s", providerType)
+) (commits []vcs.Commit, total int64, filtered int, err error) {
+	if err := validateQuery(providerType, repo, offset, limit); err != nil {
+		return nil, 0, 0, err
 	}
 
-	commits, total, err := provider.GetCommits(ctx, repo, since, until, offset, limit)
+	provider, err := s.provider(ctx, providerType)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get commits: %w", err)
+		return nil, 0, 0, err
 	}
 
-	return commits, total, nil
+	commits, total, err = provider.GetCommits(ctx, repo, since, until, offset, limit)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get commits: %w", err)
+	}
+	parseCommitMessages(commits)
+	s.contributors.ResolveCommits(ctx, providerType, provider, commits)
+
+	fetched := len(commits)
+	commits = s.filterCommits(ctx, commits, filter)
+	return commits, total, fetched - len(commits), nil
 }
 
+// GetPullRequests returns one page of a repository's pull requests. Pull
+// requests the filter rejects are dropped from the page and counted in
+// filtered; total counts every pull request upstream, including them.
 func (s *Service) GetPullRequests(
 	ctx context.Context,
 	providerType vcs.ProviderType,
 	repo string,
 	since, until time.Time,
+	filter vcs.AuthorFilter,
 	offset, limit int,
-) ([]vcs.PullRequest, int64, error) {
-	provider, ok := s.providers[providerType]
-	if !ok {
-		return nil, 0, fmt.Errorf("unsupported VCS provider: %s", providerType)
+) (prs []vcs.PullRequest, total int64, filtered int, err error) {
+	if err := validateQuery(providerType, repo, offset, limit); err != nil {
+		return nil, 0, 0, err
+	}
+
+	provider, err := s.provider(ctx, providerType)
+	if err != nil {
+		return nil, 0, 0, err
 	}
 
-	prs, total, err := provider.GetPullRequests(ctx, repo, since, until, offset, limit)
+	prs, total, err = provider.GetPullRequests(ctx, repo, since, until, offset, limit)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get pull requests: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to get pull requests: %w", err)
 	}
+	s.contributors.ResolvePullRequests(ctx, providerType, provider, prs)
 
-	return prs, total, nil
+	fetched := len(prs)
+	prs = s.filterPullRequests(ctx, prs, filter)
+	return prs, total, fetched - len(prs), nil
 }
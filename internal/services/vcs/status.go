@@ -0,0 +1,172 @@
+package vcs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"devmetrics/internal/domain/vcs"
+	"devmetrics/pkg/logger"
+)
+
+// ProviderState summarises whether a tenant's provider can serve requests
+type ProviderState string
+
+const (
+	ProviderStateOK ProviderState = "ok"
+	// ProviderStateDegraded means the provider is reachable but its token lacks required scopes
+	ProviderStateDegraded ProviderState = "degraded"
+	// ProviderStateUnavailable means the provider could not be created or verified
+	ProviderStateUnavailable ProviderState = "unavailable"
+)
+
+// ProviderStatus is the outcome of the last verification of a tenant's provider
+type ProviderStatus struct {
+	TenantID     string
+	Provider     vcs.ProviderType
+	State        ProviderState
+	Verification *vcs.Verification
+	Error        string
+	CheckedAt    time.Time
+}
+
+// RecordFailure marks a provider that could not be created as unavailable
+func (s *Service) RecordFailure(tenantID string, provider vcs.ProviderType, err error) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.failures = append(s.failures, ProviderStatus{
+		TenantID:  tenantID,
+		Provider:  provider,
+		State:     ProviderStateUnavailable,
+		Error:     err.Error(),
+		CheckedAt: time.Now(),
+	})
+}
+
+// VerifyProviders checks every configured provider concurrently and stores
+// the results, together with any recorded creation failures
+func (s *Service) VerifyProviders(ctx context.Context) []ProviderStatus {
+	statuses, _ := s.verifyProviders(ctx)
+	for _, status := range statuses {
+		s.logStatus(ctx, status)
+	}
+	return statuses
+}
+
+// MonitorProviders verifies the providers again every interval, so that
+// readiness follows upstream outages and recoveries without probes calling
+// upstream APIs. Only changed states are logged. It returns when ctx is done.
+func (s *Service) MonitorProviders(ctx context.Context, interval, timeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		verifyCtx, cancel := context.WithTimeout(ctx, timeout)
+		statuses, previous := s.verifyProviders(verifyCtx)
+		cancel()
+
+		states := make(map[string]ProviderState, len(previous))
+		for _, status := range previous {
+			states[status.TenantID+"/"+string(status.Provider)] = status.State
+		}
+		for _, status := range statuses {
+			if states[status.TenantID+"/"+string(status.Provider)] != status.State {
+				s.logStatus(ctx, status)
+			}
+		}
+	}
+}
+
+// verifyProviders stores and returns new statuses along with the ones they replace
+func (s *Service) verifyProviders(ctx context.Context) (statuses, previous []ProviderStatus) {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for tenantID, providers := range s.Providers() {
+		for providerType, provider := range providers {
+			wg.Add(1)
+			go func(tenantID string, providerType vcs.ProviderType, provider vcs.Provider) {
+				defer wg.Done()
+				status := verifyProvider(ctx, tenantID, providerType, provider)
+
+				mu.Lock()
+				defer mu.Unlock()
+				statuses = append(statuses, status)
+			}(tenantID, providerType, provider)
+		}
+	}
+	wg.Wait()
+
+	s.statusMu.Lock()
+	statuses = append(statuses, s.failures...)
+	sortStatuses(statuses)
+	previous = s.statuses
+	s.statuses = statuses
+	s.statusMu.Unlock()
+	return statuses, previous
+}
+
+func (s *Service) logStatus(ctx context.Context, status ProviderStatus) {
+	log := logger.FromContext(ctx, s.logger).With(
+		logger.String("tenant", status.TenantID),
+		logger.String("provider", string(status.Provider)),
+	)
+	switch status.State {
+	case ProviderStateOK:
+		log.Info("VCS provider verified", logger.String("identity", status.Verification.Identity))
+	case ProviderStateDegraded:
+		log.Warn("VCS provider token lacks required scopes",
+			logger.String("identity", status.Verification.Identity),
+			logger.Any("missing_scopes", status.Verification.MissingScopes),
+		)
+	default:
+		log.Error("VCS provider unavailable", logger.String("error", status.Error))
+	}
+}
+
+func verifyProvider(ctx context.Context, tenantID string, providerType vcs.ProviderType, provider vcs.Provider) ProviderStatus {
+	status := ProviderStatus{
+		TenantID: tenantID,
+		Provider: providerType,
+		State:    ProviderStateOK,
+	}
+
+	verification, err := provider.Verify(ctx)
+	status.CheckedAt = time.Now()
+	if err != nil {
+		status.State = ProviderStateUnavailable
+		status.Error = err.Error()
+		return status
+	}
+
+	status.Verification = verification
+	if len(verification.MissingScopes) > 0 {
+		status.State = ProviderStateDegraded
+	}
+	return status
+}
+
+// ProviderStatuses returns the results of the last verification ordered by tenant and provider
+func (s *Service) ProviderStatuses() []ProviderStatus {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	return append([]ProviderStatus(nil), s.statuses...)
+}
+
+func sortStatuses(statuses []ProviderStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].TenantID != statuses[j].TenantID {
+			return statuses[i].TenantID < statuses[j].TenantID
+		}
+		return statuses[i].Provider < statuses[j].Provider
+	})
+}
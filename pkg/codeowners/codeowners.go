@@ -0,0 +1,360 @@
+// Package codeowners parses CODEOWNERS files and finds the owners of paths.
+//
+// Patterns follow the gitignore-like rules of package glob; a pattern whose
+// last segment has no wildcard also matches everything below a directory of
+// that name. On GitHub the last matching rule wins. GitLab files may be split
+// into [Section] headers: the last matching rule of every section applies,
+// so a path can have owners in several sections.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"devmetrics/pkg/glob"
+)
+
+// Dialect selects the syntax of a CODEOWNERS file
+type Dialect string
+
+const (
+	GitHub Dialect = "github"
+	GitLab Dialect = "gitlab"
+)
+
+// Locations lists where a provider looks for the CODEOWNERS file, in order
+func Locations(dialect Dialect) []string {
+	if dialect == GitLab {
+		return []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+	}
+	return []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+}
+
+// Rule assigns owners to the paths matching a pattern
+type Rule struct {
+	Pattern *glob.Pattern
+	// Owners are written as in the file: @user, @org/team, @group/subgroup,
+	// @@role or an e-mail address. Empty on GitHub marks paths as unowned.
+	Owners []string
+	// Exclude marks a GitLab !pattern; matching paths have no owners in the section
+	Exclude bool
+	Line    int
+	// matchesBelow is set when the pattern also matches the contents of directories
+	matchesBelow bool
+}
+
+// Section groups the rules below a GitLab [Section] header. GitHub files and
+// the rules before the first header form an unnamed section.
+type Section struct {
+	Name string
+	// Optional sections, written ^[Section], don't require an owner's approval
+	Optional bool
+	// Approvals is the number of owner approvals the section requires; 0 when unset
+	Approvals int
+	// DefaultOwners apply to the section's rules that name no owners
+	DefaultOwners []string
+	Rules         []Rule
+}
+
+// ParseError describes a line that was skipped
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Dialect  Dialect
+	Sections []*Section
+	// Errors lists the lines that were skipped, as the provider would
+	Errors []ParseError
+}
+
+// Match is the rule of one section that applies to a path
+type Match struct {
+	Section  string
+	Optional bool
+	Owners   []string
+	Line     int
+}
+
+// Parse reads a CODEOWNERS file. Invalid lines are recorded in File.Errors
+// and otherwise ignored.
+func Parse(content string, dialect Dialect) *File {
+	file := &File{Dialect: dialect}
+	current := &Section{}
+	file.Sections = append(file.Sections, current)
+	sections := map[string]*Section{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if dialect == GitLab && (strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[")) {
+			section, err := parseSection(line)
+			if err != nil {
+				file.Errors = append(file.Errors, ParseError{Line: number, Message: err.Error()})
+				continue
+			}
+			// Sections with the same name are combined, ignoring case
+			key := strings.ToLower(section.Name)
+			if existing, ok := sections[key]; ok {
+				existing.DefaultOwners = append(existing.DefaultOwners, section.DefaultOwners...)
+				current = existing
+				continue
+			}
+			sections[key] = section
+			file.Sections = append(file.Sections, section)
+			current = section
+			continue
+		}
+
+		rule, err := parseRule(line, dialect)
+		if err != nil {
+			file.Errors = append(file.Errors, ParseError{Line: number, Message: err.Error()})
+			continue
+		}
+		rule.Line = number
+		if dialect == GitLab && len(rule.Owners) == 0 && !rule.Exclude && current.Name == "" {
+			file.Errors = append(file.Errors, ParseError{Line: number, Message: "rule names no owners"})
+			continue
+		}
+		current.Rules = append(current.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Errors = append(file.Errors, ParseError{Message: err.Error()})
+	}
+	return file
+}
+
+// parseSection reads a GitLab header: ^[Name][approvals] default owners
+func parseSection(line string) (*Section, error) {
+	section := &Section{}
+	if strings.HasPrefix(line, "^") {
+		section.Optional = true
+		line = line[1:]
+	}
+	end := strings.Index(line, "]")
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated section header")
+	}
+	section.Name = strings.TrimSpace(line[1:end])
+	if section.Name == "" {
+		return nil, fmt.Errorf("empty section name")
+	}
+	line = line[end+1:]
+
+	if strings.HasPrefix(line, "[") {
+		end = strings.Index(line, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated approval count")
+		}
+		approvals, err := strconv.Atoi(strings.TrimSpace(line[1:end]))
+		if err != nil || approvals < 1 {
+			return nil, fmt.Errorf("invalid approval count %q", line[1:end])
+		}
+		section.Approvals = approvals
+		line = line[end+1:]
+	}
+
+	for _, owner := range splitFields(line) {
+		if strings.HasPrefix(owner, "#") {
+			break
+		}
+		if !validOwner(owner) {
+			return nil, fmt.Errorf("invalid owner %q", owner)
+		}
+		section.DefaultOwners = append(section.DefaultOwners, owner)
+	}
+	return section, nil
+}
+
+func parseRule(line string, dialect Dialect) (Rule, error) {
+	fields := splitFields(line)
+	pattern := fields[0]
+
+	var rule Rule
+	if dialect == GitLab && strings.HasPrefix(pattern, "!") {
+		rule.Exclude = true
+		pattern = pattern[1:]
+	}
+	if strings.HasPrefix(pattern, "!") {
+		return rule, fmt.Errorf("negated patterns are not supported")
+	}
+	if strings.Contains(pattern, "[") {
+		return rule, fmt.Errorf("character ranges are not supported in %q", pattern)
+	}
+
+	compiled, err := glob.Compile(pattern)
+	if err != nil {
+		return rule, err
+	}
+	rule.Pattern = compiled
+	last := path.Base(strings.TrimSuffix(pattern, "/"))
+	rule.matchesBelow = !strings.HasSuffix(pattern, "/") && !strings.ContainsAny(last, "*?")
+
+	for _, owner := range fields[1:] {
+		// The rest of the line is a comment
+		if strings.HasPrefix(owner, "#") {
+			break
+		}
+		if !validOwner(owner) {
+			return rule, fmt.Errorf("invalid owner %q", owner)
+		}
+		rule.Owners = append(rule.Owners, owner)
+	}
+	return rule, nil
+}
+
+// splitFields splits a line on whitespace that isn't escaped with a backslash
+// and removes the escapes
+func splitFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			field.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ' ' || r == '\t':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+func validOwner(owner string) bool {
+	switch {
+	case strings.HasPrefix(owner, "@@"):
+		return len(owner) > 2
+	case strings.HasPrefix(owner, "@"):
+		name := owner[1:]
+		return name != "" && !strings.HasPrefix(name, "/") && !strings.HasSuffix(name, "/")
+	default:
+		return IsEmail(owner)
+	}
+}
+
+// IsTeam reports whether an owner is a GitHub team or a GitLab group written with its parent path
+func IsTeam(owner string) bool {
+	return strings.HasPrefix(owner, "@") && !IsRole(owner) && strings.Contains(owner, "/")
+}
+
+// IsRole reports whether an owner is a GitLab role such as @@maintainer
+func IsRole(owner string) bool {
+	return strings.HasPrefix(owner, "@@")
+}
+
+// IsEmail reports whether an owner is an e-mail address
+func IsEmail(owner string) bool {
+	at := strings.Index(owner, "@")
+	return at > 0 && at < len(owner)-1
+}
+
+// matches reports whether a rule's pattern matches the path or, where the
+// pattern allows, one of its directories
+func (r Rule) matches(p string) bool {
+	if r.Pattern.Match(p) {
+		return true
+	}
+	if !r.matchesBelow {
+		return false
+	}
+	for dir := path.Dir(p); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if r.Pattern.Match(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the rule that applies to a path in every section with one.
+// Rules without owners are returned too; they leave the path unowned.
+func (f *File) Match(p string) []Match {
+	var matches []Match
+	for _, section := range f.Sections {
+		var last *Rule
+		excluded := false
+		for i := range section.Rules {
+			rule := &section.Rules[i]
+			if !rule.matches(p) {
+				continue
+			}
+			if rule.Exclude {
+				excluded = true
+				continue
+			}
+			last = rule
+		}
+		if last == nil || excluded {
+			continue
+		}
+
+		owners := last.Owners
+		if len(owners) == 0 {
+			owners = section.DefaultOwners
+		}
+		matches = append(matches, Match{Section: section.Name, Optional: section.Optional, Owners: owners, Line: last.Line})
+	}
+	return matches
+}
+
+// Owners returns the distinct owners of a path across sections, in file order
+func (f *File) Owners(p string) []string {
+	var owners []string
+	seen := make(map[string]bool)
+	for _, match := range f.Match(p) {
+		for _, owner := range match.Owners {
+			key := strings.ToLower(owner)
+			if !seen[key] {
+				seen[key] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// AllOwners returns every distinct owner the file names, in file order
+func (f *File) AllOwners() []string {
+	var owners []string
+	seen := make(map[string]bool)
+	add := func(list []string) {
+		for _, owner := range list {
+			key := strings.ToLower(owner)
+			if !seen[key] {
+				seen[key] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	for _, section := range f.Sections {
+		add(section.DefaultOwners)
+		for _, rule := range section.Rules {
+			add(rule.Owners)
+		}
+	}
+	return owners
+}
@@ -0,0 +1,133 @@
+package codeowners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	github := `# Default owners
+*                 @acme/core
+*.go              @gopher
+/docs/            docs@acme.com
+/docs/generated/
+build/            @acme/ci # release tooling
+`
+	gitlab := `* @acme/core
+!vendor/
+
+[Backend][2] @acme/backend
+*.go
+/internal/legacy/ @alice
+!/internal/legacy/generated/
+
+^[Docs] @acme/docs
+docs/
+
+[backend]
+/cmd/ @bob
+`
+
+	tests := []struct {
+		name    string
+		content string
+		dialect Dialect
+		path    string
+		want    []string
+	}{
+		{"github catch-all", github, GitHub, "README.md", []string{"@acme/core"}},
+		{"github last match wins", github, GitHub, "main.go", []string{"@gopher"}},
+		{"github anchored directory", github, GitHub, "docs/guide/intro.md", []string{"docs@acme.com"}},
+		{"github anchored directory not nested", github, GitHub, "api/docs/intro.md", []string{"@acme/core"}},
+		{"github rule without owners unowns", github, GitHub, "docs/generated/api.md", nil},
+		{"github unanchored directory", github, GitHub, "tools/build/release.sh", []string{"@acme/ci"}},
+		{"github trailing comment", github, GitHub, "build/Makefile", []string{"@acme/ci"}},
+		{"gitlab owners from every section", gitlab, GitLab, "main.go", []string{"@acme/core", "@acme/backend"}},
+		{"gitlab last match within a section", gitlab, GitLab, "internal/legacy/db.go", []string{"@acme/core", "@alice"}},
+		{"gitlab exclusion within a section", gitlab, GitLab, "internal/legacy/generated/db.go", []string{"@acme/core"}},
+		{"gitlab exclusion in the unnamed section", gitlab, GitLab, "vendor/lib/lib.go", []string{"@acme/backend"}},
+		{"gitlab section default owners", gitlab, GitLab, "docs/index.md", []string{"@acme/core", "@acme/docs"}},
+		{"gitlab sections combined ignoring case", gitlab, GitLab, "cmd/main.go", []string{"@acme/core", "@bob"}},
+		{"gitlab no section matches", gitlab, GitLab, "vendor/README.md", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := Parse(tt.content, tt.dialect)
+			if len(file.Errors) > 0 {
+				t.Fatalf("unexpected parse errors: %v", file.Errors)
+			}
+			if got := file.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchSections(t *testing.T) {
+	file := Parse(`* @acme/core
+
+^[Docs][2] @acme/docs
+*.md
+`, GitLab)
+
+	want := []Match{
+		{Owners: []string{"@acme/core"}, Line: 1},
+		{Section: "Docs", Optional: true, Owners: []string{"@acme/docs"}, Line: 4},
+	}
+	if got := file.Match("README.md"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Match = %+v, want %+v", got, want)
+	}
+	if approvals := file.Sections[1].Approvals; approvals != 2 {
+		t.Errorf("Approvals = %d, want 2", approvals)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		dialect Dialect
+		line    int
+	}{
+		{"github negation", "!*.go @gopher", GitHub, 1},
+		{"character range", "*.[ch] @c", GitHub, 1},
+		{"invalid owner", "*.go gopher", GitHub, 1},
+		{"gitlab rule without owners outside sections", "# owners\n*.go", GitLab, 2},
+		{"gitlab unterminated section", "[Backend @acme/backend", GitLab, 1},
+		{"gitlab invalid approval count", "[Backend][0] @acme/backend", GitLab, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := Parse(tt.content, tt.dialect)
+			if len(file.Errors) != 1 || file.Errors[0].Line != tt.line {
+				t.Errorf("Errors = %v, want one error on line %d", file.Errors, tt.line)
+			}
+		})
+	}
+}
+
+func TestOwnerKinds(t *testing.T) {
+	tests := []struct {
+		owner             string
+		team, role, email bool
+	}{
+		{"@alice", false, false, false},
+		{"@acme/core", true, false, false},
+		{"@acme/platform/core", true, false, false},
+		{"@@maintainer", false, true, false},
+		{"docs@acme.com", false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.owner, func(t *testing.T) {
+			if got := IsTeam(tt.owner); got != tt.team {
+				t.Errorf("IsTeam = %v, want %v", got, tt.team)
+			}
+			if got := IsRole(tt.owner); got != tt.role {
+				t.Errorf("IsRole = %v, want %v", got, tt.role)
+			}
+			if got := IsEmail(tt.owner); got != tt.email {
+				t.Errorf("IsEmail = %v, want %v", got, tt.email)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package commitmsg parses commit messages: Conventional Commits subjects,
+// git trailers such as Co-authored-by, and the messages git writes for
+// merges and reverts.
+//
+// A subject follows Conventional Commits when it reads
+// "type(scope)!: description"; the scope and the breaking marker are
+// optional. Trailers are read from the last paragraph of the message, and
+// only when every line of it is a trailer or the continuation of one.
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Trailer is a "Token: value" line at the end of a message
+type Trailer struct {
+	Token string
+	Value string
+}
+
+// Message is a parsed commit message
+type Message struct {
+	Subject string
+	// Body holds the paragraphs between the subject and the trailers
+	Body string
+	// Conventional is set when the subject follows Conventional Commits
+	Conventional bool
+	// Type is lower-cased; Type, Scope and Description are empty unless Conventional
+	Type        string
+	Scope       string
+	Description string
+	// Breaking is set by a "!" before the colon or a BREAKING CHANGE trailer
+	Breaking bool
+	// Merge is set for the subjects git and the providers write for merges
+	Merge bool
+	// Revert is set for git's "Revert" subjects and the revert type
+	Revert bool
+	// RevertedSHA is the commit named by "This reverts commit <sha>"
+	RevertedSHA string
+	Trailers    []Trailer
+}
+
+var (
+	conventionalPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)(?:\(([^()]*)\))?(!)?: +(\S.*)$`)
+	mergePattern        = regexp.MustCompile(`^(Merge (branch|branches|pull request|remote-tracking branch|tag|commit) |Merged in |Merged PR )`)
+	revertedPattern     = regexp.MustCompile(`This reverts commit ([0-9a-fA-F]{7,40})`)
+	trailerPattern      = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*|BREAKING CHANGE)(?:: | #)(.*)$`)
+	signaturePattern    = regexp.MustCompile(`^(.*?)\s*<([^<>]*)>$`)
+)
+
+// Parse reads a commit message
+func Parse(message string) Message {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	subject, rest, _ := strings.Cut(message, "\n")
+	m := Message{Subject: strings.TrimSpace(subject)}
+
+	paragraphs := splitParagraphs(rest)
+	if n := len(paragraphs); n > 0 {
+		if trailers, ok := parseTrailers(paragraphs[n-1]); ok {
+			m.Trailers = trailers
+			paragraphs = paragraphs[:n-1]
+		}
+	}
+	m.Body = strings.Join(paragraphs, "\n\n")
+
+	if match := conventionalPattern.FindStringSubmatch(m.Subject); match != nil {
+		m.Conventional = true
+		m.Type = strings.ToLower(match[1])
+		m.Scope = strings.TrimSpace(match[2])
+		m.Breaking = match[3] != ""
+		m.Description = strings.TrimSpace(match[4])
+	}
+	for _, trailer := range m.Trailers {
+		if trailer.Token == "BREAKING CHANGE" || strings.EqualFold(trailer.Token, "BREAKING-CHANGE") {
+			m.Breaking = true
+		}
+	}
+
+	m.Merge = mergePattern.MatchString(m.Subject)
+	m.Revert = strings.HasPrefix(m.Subject, `Revert "`) || m.Type == "revert"
+	if match := revertedPattern.FindStringSubmatch(message); match != nil {
+		m.Revert = true
+		m.RevertedSHA = strings.ToLower(match[1])
+	}
+	return m
+}
+
+// splitParagraphs splits text on blank lines, dropping empty paragraphs
+func splitParagraphs(text string) []string {
+	var paragraphs []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t"))
+	}
+	flush()
+	return paragraphs
+}
+
+// parseTrailers reads a paragraph of trailers. Lines starting with
+// whitespace continue the previous value.
+func parseTrailers(paragraph string) ([]Trailer, bool) {
+	var trailers []Trailer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if (line[0] == ' ' || line[0] == '\t') && len(trailers) > 0 {
+			last := &trailers[len(trailers)-1]
+			last.Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		match := trailerPattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, false
+		}
+		trailers = append(trailers, Trailer{Token: match[1], Value: strings.TrimSpace(match[2])})
+	}
+	return trailers, len(trailers) > 0
+}
+
+// Values returns the values of the trailers with a token, ignoring case
+func (m Message) Values(token string) []string {
+	var values []string
+	for _, trailer := range m.Trailers {
+		if strings.EqualFold(trailer.Token, token) {
+			values = append(values, trailer.Value)
+		}
+	}
+	return values
+}
+
+// ParseSignature splits a "Name <email>" trailer value such as a co-author.
+// Values without an address are returned as a name.
+func ParseSignature(value string) (name, email string) {
+	value = strings.TrimSpace(value)
+	if match := signaturePattern.FindStringSubmatch(value); match != nil {
+		return strings.TrimSpace(match[1]), strings.TrimSpace(match[2])
+	}
+	return value, ""
+}
@@ -0,0 +1,137 @@
+package commitmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    Message
+	}{
+		{
+			name:    "plain subject",
+			message: "Fix the build\n",
+			want:    Message{Subject: "Fix the build"},
+		},
+		{
+			name:    "conventional with scope and breaking marker",
+			message: "feat(api)!: x",
+			want: Message{
+				Subject: "feat(api)!: x", Conventional: true,
+				Type: "feat", Scope: "api", Description: "x", Breaking: true,
+			},
+		},
+		{
+			name:    "conventional type is lower-cased",
+			message: "Fix: handle empty input",
+			want: Message{
+				Subject: "Fix: handle empty input", Conventional: true,
+				Type: "fix", Description: "handle empty input",
+			},
+		},
+		{
+			name:    "colon without space is not conventional",
+			message: "fix:handle empty input",
+			want:    Message{Subject: "fix:handle empty input"},
+		},
+		{
+			name:    "breaking change trailer",
+			message: "refactor: drop v1\n\nThe v1 routes are gone.\n\nBREAKING CHANGE: clients must use /v2",
+			want: Message{
+				Subject: "refactor: drop v1", Body: "The v1 routes are gone.", Conventional: true,
+				Type: "refactor", Description: "drop v1", Breaking: true,
+				Trailers: []Trailer{{Token: "BREAKING CHANGE", Value: "clients must use /v2"}},
+			},
+		},
+		{
+			name:    "body paragraph that only looks like trailers",
+			message: "Tune retries\n\nNote: retries back off now.\nThe limit stays at five.",
+			want: Message{
+				Subject: "Tune retries",
+				Body:    "Note: retries back off now.\nThe limit stays at five.",
+			},
+		},
+		{
+			name:    "trailers only in the last paragraph",
+			message: "Tune retries\n\nSee: the issue\n\nThe limit stays at five.",
+			want: Message{
+				Subject: "Tune retries",
+				Body:    "See: the issue\n\nThe limit stays at five.",
+			},
+		},
+		{
+			name:    "continuation lines and issue references",
+			message: "Add export\n\nCo-authored-by: Jane Doe\n  <jane@example.com>\nFixes #42\r\n",
+			want: Message{
+				Subject: "Add export",
+				Trailers: []Trailer{
+					{Token: "Co-authored-by", Value: "Jane Doe <jane@example.com>"},
+					{Token: "Fixes", Value: "42"},
+				},
+			},
+		},
+		{
+			name:    "merge of a pull request",
+			message: "Merge pull request #12 from acme/feature\n\nAdd export",
+			want:    Message{Subject: "Merge pull request #12 from acme/feature", Body: "Add export", Merge: true},
+		},
+		{
+			name:    "git revert",
+			message: "Revert \"Add export\"\n\nThis reverts commit 0A1B2C3D4E5F.",
+			want: Message{
+				Subject: "Revert \"Add export\"", Body: "This reverts commit 0A1B2C3D4E5F.",
+				Revert: true, RevertedSHA: "0a1b2c3d4e5f",
+			},
+		},
+		{
+			name:    "conventional revert",
+			message: "revert: add export",
+			want: Message{
+				Subject: "revert: add export", Conventional: true,
+				Type: "revert", Description: "add export", Revert: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	message := Parse("Add export\n\nCo-authored-by: Jane <jane@example.com>\nSigned-off-by: Joe <joe@example.com>\nco-authored-by: Jane <JANE@example.com>")
+
+	want := []string{"Jane <jane@example.com>", "Jane <JANE@example.com>"}
+	if got := message.Values("Co-Authored-By"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Values = %v, want %v", got, want)
+	}
+	if got := message.Values("Reviewed-by"); got != nil {
+		t.Errorf("Values = %v, want none", got)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	tests := []struct {
+		value, name, email string
+	}{
+		{"Jane Doe <jane@example.com>", "Jane Doe", "jane@example.com"},
+		{"  Jane Doe<jane@example.com>  ", "Jane Doe", "jane@example.com"},
+		{"<jane@example.com>", "", "jane@example.com"},
+		{"Jane Doe", "Jane Doe", ""},
+		{"Jane <Doe> <jane@example.com>", "Jane <Doe>", "jane@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			name, email := ParseSignature(tt.value)
+			if name != tt.name || email != tt.email {
+				t.Errorf("ParseSignature = (%q, %q), want (%q, %q)", name, email, tt.name, tt.email)
+			}
+		})
+	}
+}
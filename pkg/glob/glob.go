@@ -0,0 +1,93 @@
+// Package glob matches slash-separated file paths against gitignore-like
+// patterns. "*" and "?" match within a path segment, "**" matches across
+// segments, a pattern without a slash matches the file name in any
+// directory and a trailing slash matches everything below a directory.
+package glob
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Pattern is a compiled glob pattern
+type Pattern struct {
+	source string
+	re     *regexp.Regexp
+}
+
+func Compile(pattern string) (*Pattern, error) {
+	trimmed := strings.TrimSpace(pattern)
+	if trimmed == "" || trimmed == "/" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	if strings.HasSuffix(trimmed, "/") {
+		trimmed += "**"
+	}
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !strings.Contains(strings.TrimSuffix(trimmed, "/**"), "/") {
+		expr.WriteString("(?:.*/)?")
+	}
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			expr.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return &Pattern{source: pattern, re: re}, nil
+}
+
+// Match reports whether the path, relative to the repository root, matches
+func (p *Pattern) Match(path string) bool {
+	return p.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+func (p *Pattern) String() string {
+	return p.source
+}
+
+// Set matches a path against any of several patterns
+type Set []*Pattern
+
+// CompileSet compiles every pattern; an empty list matches nothing
+func CompileSet(patterns []string) (Set, error) {
+	set := make(Set, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, p)
+	}
+	return set, nil
+}
+
+// Match reports whether any pattern matches the path
+func (s Set) Match(path string) bool {
+	for _, p := range s {
+		if p.Match(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/app/main.go", true},
+		{"*.go", "main.go.orig", false},
+		{"go.sum", "tools/go.sum", true},
+		{"/go.sum", "tools/go.sum", false},
+		{"/go.sum", "go.sum", true},
+		{"docs/*.md", "docs/intro.md", true},
+		{"docs/*.md", "docs/guide/intro.md", false},
+		{"docs/*.md", "api/docs/intro.md", false},
+		{"docs/**/*.md", "docs/intro.md", true},
+		{"docs/**/*.md", "docs/guide/v1/intro.md", true},
+		{"**/testdata/**", "pkg/glob/testdata/a.txt", true},
+		{"vendor/", "vendor/github.com/lib/lib.go", true},
+		{"vendor/", "internal/vendor/lib.go", true},
+		{"vendor/", "vendor", false},
+		{"/vendor/", "internal/vendor/lib.go", false},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"file?.txt", "dir/file/.txt", false},
+		{"*.min.js", "static/app.min.js", true},
+		{"*.min.js", "static/appxminxjs", false},
+		{"*.go", "/main.go", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			p, err := Compile(tt.pattern)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.pattern, err)
+			}
+			if got := p.Match(tt.path); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileEmpty(t *testing.T) {
+	for _, pattern := range []string{"", "  ", "/"} {
+		if _, err := Compile(pattern); err == nil {
+			t.Errorf("Compile(%q) succeeded, want an error", pattern)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	set, err := CompileSet([]string{"*.lock", "vendor/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"yarn.lock", true},
+		{"vendor/lib.go", true},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := set.Match(tt.path); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	empty, err := CompileSet(nil)
+	if err != nil || empty.Match("main.go") {
+		t.Errorf("empty set matched or failed: %v", err)
+	}
+	if _, err := CompileSet([]string{"*.go", ""}); err == nil {
+		t.Error("CompileSet with an empty pattern succeeded, want an error")
+	}
+}
@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"fmt"
+	"strings"
+
 	"go.uber.org/zap/zapcore"
 )
 
@@ -11,6 +14,8 @@ type Config struct {
 	OutputPaths      []string
 	ErrorOutputPaths []string
 	EncoderConfig    zapcore.EncoderConfig
+	// Redact, when set, scrubs secrets from messages and string fields
+	Redact func(string) string
 }
 
 func DefaultConfig() Config {
@@ -36,3 +41,39 @@ func DefaultConfig() Config {
 		ErrorOutputPaths: []string{"stderr"},
 	}
 }
+
+// NewConfig builds a Config from textual settings such as those read from the
+// environment. Format is either "json" or "console" and output is a
+// comma-separated list of zap sinks ("stdout", "stderr" or file paths).
+func NewConfig(level, format, output, timeFormat string) (Config, error) {
+	config := DefaultConfig()
+
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+		config.Level = lvl
+	}
+
+	switch format {
+	case "", "json":
+		config.Encoding = "json"
+	case "console":
+		config.Encoding = "console"
+		config.Development = true
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	default:
+		return Config{}, fmt.Errorf("invalid log format %q: expected json or console", format)
+	}
+
+	if output != "" {
+		config.OutputPaths = strings.Split(output, ",")
+	}
+
+	if timeFormat != "" {
+		config.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout(timeFormat)
+	}
+
+	return config, nil
+}
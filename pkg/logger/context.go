@@ -0,0 +1,24 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying the given logger
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or fallback when there is none.
+// A nil fallback yields a no-op logger so callers never have to nil-check.
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+			return logger
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return NewNop()
+}
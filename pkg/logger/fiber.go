@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"time"
+
+	"devmetrics/pkg/requestid"
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocalsKey is the fiber.Ctx locals key holding the request-scoped logger
+const LocalsKey = "logger"
+
+// FiberMiddleware is the Fiber counterpart of HTTPMiddleware. It attaches a
+// request-scoped logger to both c.Locals and the user context, and logs the
+// outcome of every request once the handler chain has returned.
+func FiberMiddleware(logger Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestLogger := logger.With(
+			String("request_id", requestID(c)),
+			String("method", c.Method()),
+			String("path", c.Path()),
+			String("remote_addr", c.IP()),
+			String("user_agent", c.Get(fiber.HeaderUserAgent)),
+		)
+
+		c.Locals(LocalsKey, requestLogger)
+		c.SetUserContext(WithContext(c.UserContext(), requestLogger))
+
+		err := c.Next()
+		if err != nil {
+			// Let the app error handler write the response so the status is final
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		fields := []Field{
+			String("route", c.Route().Path),
+			Int("status", c.Response().StatusCode()),
+			Int("bytes", len(c.Response().Body())),
+			Duration("duration", time.Since(start)),
+		}
+
+		status := c.Response().StatusCode()
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			requestLogger.Error("Request completed", append(fields, Error(err))...)
+		case status >= fiber.StatusBadRequest:
+			requestLogger.Warn("Request completed", fields...)
+		default:
+			requestLogger.Info("Request completed", fields...)
+		}
+
+		return nil
+	}
+}
+
+// FromFiber returns the request-scoped logger attached by FiberMiddleware,
+// falling back to the given logger when the middleware did not run
+func FromFiber(c *fiber.Ctx, fallback Logger) Logger {
+	if logger, ok := c.Locals(LocalsKey).(Logger); ok {
+		return logger
+	}
+	return FromContext(c.UserContext(), fallback)
+}
+
+// requestID prefers the ID placed in the user context by the request ID
+// middleware and falls back to the response and request headers
+func requestID(c *fiber.Ctx) string {
+	if id := requestid.FromContext(c.UserContext()); id != "" {
+		return id
+	}
+	return c.GetRespHeader(requestid.Header, c.Get(requestid.Header))
+}
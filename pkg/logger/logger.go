@@ -30,7 +30,15 @@ func NewLogger(config Config) (Logger, error) {
 		ErrorOutputPaths: config.ErrorOutputPaths,
 	}
 
-	logger, err := zapConfig.Build()
+	// Skip the zapLogger wrapper so callers are reported instead of this file
+	options := []zap.Option{zap.AddCallerSkip(1)}
+	if config.Redact != nil {
+		options = append(options, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return newRedactCore(core, config.Redact)
+		}))
+	}
+
+	logger, err := zapConfig.Build(options...)
 	if err != nil {
 		return nil, err
 	}
@@ -65,3 +73,10 @@ func (l *zapLogger) With(fields ...Field) Logger {
 		logger: l.logger.With(fields...),
 	}
 }
+
+// NewNop returns a Logger that discards everything written to it
+func NewNop() Logger {
+	return &zapLogger{
+		logger: zap.NewNop(),
+	}
+}
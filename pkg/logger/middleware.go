@@ -3,6 +3,8 @@ package logger
 import (
 	"net/http"
 	"time"
+
+	"devmetrics/pkg/requestid"
 )
 
 type ResponseWriter struct {
@@ -33,9 +35,15 @@ func HTTPMiddleware(logger Logger) func(http.Handler) http.Handler {
 				StatusCode:     http.StatusOK,
 			}
 
+			id := r.Header.Get(requestid.Header)
+			if !requestid.IsValid(id) {
+				id = requestid.New()
+			}
+			rw.Header().Set(requestid.Header, id)
+
 			// Create request-specific logger with request ID
 			requestLogger := logger.With(
-				String("request_id", r.Header.Get("X-Request-ID")),
+				String("request_id", id),
 				String("method", r.Method),
 				String("path", r.URL.Path),
 				String("remote_addr", r.RemoteAddr),
@@ -45,8 +53,11 @@ func HTTPMiddleware(logger Logger) func(http.Handler) http.Handler {
 			// Log request
 			requestLogger.Info("Incoming request")
 
+			ctx := requestid.NewContext(r.Context(), id)
+			ctx = WithContext(ctx, requestLogger)
+
 			// Call next handler
-			next.ServeHTTP(rw, r)
+			next.ServeHTTP(rw, r.WithContext(ctx))
 
 			// Log response
 			requestLogger.Info("Request completed",
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redactCore scrubs messages and string-like fields before they are encoded
+type redactCore struct {
+	zapcore.Core
+	redact func(string) string
+}
+
+func newRedactCore(core zapcore.Core, redact func(string) string) zapcore.Core {
+	return &redactCore{Core: core, redact: redact}
+}
+
+func (c *redactCore) With(fields []Field) zapcore.Core {
+	return &redactCore{
+		Core:   c.Core.With(c.redactFields(fields)),
+		redact: c.redact,
+	}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []Field) error {
+	entry.Message = c.redact(entry.Message)
+	return c.Core.Write(entry, c.redactFields(fields))
+}
+
+func (c *redactCore) redactFields(fields []Field) []Field {
+	redacted := make([]Field, len(fields))
+	for i, field := range fields {
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = c.redact(field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok && err != nil {
+				field = String(field.Key, c.redact(err.Error()))
+			}
+		case zapcore.StringerType:
+			if stringer, ok := field.Interface.(fmt.Stringer); ok && stringer != nil {
+				field = String(field.Key, c.redact(stringer.String()))
+			}
+		}
+		redacted[i] = field
+	}
+	return redacted
+}
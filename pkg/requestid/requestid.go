@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header carrying the request ID, both from clients and to upstream APIs
+const Header = "X-Request-ID"
+
+// MaxLength bounds client-provided IDs so they can't bloat logs or upstream headers
+const MaxLength = 128
+
+type contextKey struct{}
+
+// New generates a fresh request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// IsValid reports whether a client-provided ID is safe to echo and forward:
+// non-empty, bounded in length and limited to visible ASCII characters
+func IsValid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or an empty string
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
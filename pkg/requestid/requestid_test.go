@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"uuid", "3f2b8c1e-9d4a-4c55-8f0e-2a7b6c9d1e0f", true},
+		{"visible punctuation", "req_1:a/b~c", true},
+		{"empty", "", false},
+		{"at the length limit", strings.Repeat("a", MaxLength), true},
+		{"over the length limit", strings.Repeat("a", MaxLength+1), false},
+		{"space", "req 1", false},
+		{"newline", "req\n1", false},
+		{"non-ASCII", "réq", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValid(tt.id); got != tt.want {
+				t.Errorf("IsValid(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	id := New()
+	if !IsValid(id) {
+		t.Errorf("New() = %q is not a valid ID", id)
+	}
+	if other := New(); other == id {
+		t.Errorf("New() returned %q twice", id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := NewContext(context.Background(), "req-1")
+	if got := FromContext(ctx); got != "req-1" {
+		t.Errorf("FromContext = %q, want req-1", got)
+	}
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("FromContext without an ID = %q, want empty", got)
+	}
+	if got := FromContext(nil); got != "" {
+		t.Errorf("FromContext(nil) = %q, want empty", got)
+	}
+}